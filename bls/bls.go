@@ -0,0 +1,174 @@
+// Package bls implements the BLS signatures over the BN128, with the
+// signatures in G1 and the public keys in G2
+// (https://datatracker.ietf.org/doc/draft-irtf-cfrg-bls-signature/). The
+// messages are hashed to G1 with bn128.HashToG1, and a signature of a message
+// m is sk*H(m), verified with e(sig, g2) == e(H(m), pk).
+//
+// The signatures can be aggregated by adding them. To prevent the rogue key
+// attacks, where a key built from the keys of others makes a forged aggregated
+// signature verify, it uses the proof of possession scheme of the draft: each
+// public key comes with a signature of itself (PopProve), and the keys are only
+// used in FastAggregateVerify and AggregateVerify once its proof has been
+// checked with PopVerify.
+package bls
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/fields"
+)
+
+var (
+	// DstSig is the domain separation tag of the hash of the messages
+	DstSig = []byte("BLS_SIG_BN254G1_XMD:SHA-256_SVDW_RO_POP_")
+	// DstPop is the domain separation tag of the hash of the public keys in
+	// the proofs of possession
+	DstPop = []byte("BLS_POP_BN254G1_XMD:SHA-256_SVDW_RO_POP_")
+)
+
+// BLS is the data structure of the BLS signatures scheme
+type BLS struct {
+	Bn bn128.Bn128
+	Fr fields.Fq // the field of the secret keys, over the order R of G1 and G2
+}
+
+// NewBLS returns the BLS signatures scheme over the BN128
+func NewBLS() (BLS, error) {
+	bn, err := bn128.NewBn128()
+	if err != nil {
+		return BLS{}, err
+	}
+	fr, err := bn128.NewFqR()
+	if err != nil {
+		return BLS{}, err
+	}
+	return BLS{bn, fr}, nil
+}
+
+// GenerateKey returns a random secret key and its public key, read from crypto/rand
+func (b BLS) GenerateKey() (*big.Int, bn128.G2Point, error) {
+	return b.GenerateKeyWithReader(rand.Reader)
+}
+
+// GenerateKeyWithReader returns a random secret key and its public key, read
+// from the given io.Reader
+func (b BLS) GenerateKeyWithReader(rnd io.Reader) (*big.Int, bn128.G2Point, error) {
+	for {
+		sk, err := b.Fr.RandFrom(rnd)
+		if err != nil {
+			return nil, bn128.G2Point{}, err
+		}
+		if !b.Fr.IsZero(sk) {
+			return sk, b.PublicKey(sk), nil
+		}
+	}
+}
+
+// PublicKey returns the public key sk*g2 of the secret key sk
+func (b BLS) PublicKey(sk *big.Int) bn128.G2Point {
+	return b.Bn.G2.Point(b.Bn.G2.MulScalarCT(b.Bn.G2.G, sk)).Affine()
+}
+
+// ValidatePublicKey returns an error if the public key is not in G2, or is
+// the point at infinity
+func (b BLS) ValidatePublicKey(pk bn128.G2Point) error {
+	if pk.IsZero() {
+		return errors.New("public key is the point at infinity")
+	}
+	return b.Bn.G2.Validate(pk.Array())
+}
+
+func (b BLS) sign(sk *big.Int, msg, dst []byte) bn128.G1Point {
+	return b.Bn.G1.Point(b.Bn.G1.MulScalarCT(b.Bn.HashToG1(msg, dst), sk)).Affine()
+}
+
+// coreVerify checks that the product of the pairings e(H(msgs[i]), pks[i])
+// is e(sig, g2)
+func (b BLS) coreVerify(pks []bn128.G2Point, msgs [][]byte, sig bn128.G1Point, dst []byte) bool {
+	if len(pks) == 0 || len(pks) != len(msgs) {
+		return false
+	}
+	if b.Bn.G1.Validate(sig.Array()) != nil {
+		return false
+	}
+	g1s := [][3]*big.Int{sig.Array()}
+	g2s := [][3][2]*big.Int{b.Bn.G2.Neg(b.Bn.G2.G)}
+	for i := 0; i < len(pks); i++ {
+		if b.ValidatePublicKey(pks[i]) != nil {
+			return false
+		}
+		g1s = append(g1s, b.Bn.HashToG1(msgs[i], dst))
+		g2s = append(g2s, pks[i].Array())
+	}
+	return b.Bn.PairingCheck(g1s, g2s)
+}
+
+// Sign returns the signature of the msg with the secret key sk
+func (b BLS) Sign(sk *big.Int, msg []byte) bn128.G1Point {
+	return b.sign(sk, msg, DstSig)
+}
+
+// Verify returns true if sig is a signature of the msg for the public key pk
+func (b BLS) Verify(pk bn128.G2Point, msg []byte, sig bn128.G1Point) bool {
+	return b.coreVerify([]bn128.G2Point{pk}, [][]byte{msg}, sig, DstSig)
+}
+
+// Aggregate returns the aggregated signature of the given signatures
+func (b BLS) Aggregate(sigs []bn128.G1Point) bn128.G1Point {
+	r := b.Bn.G1.Point([3]*big.Int{b.Bn.Fq1.Zero(), b.Bn.Fq1.Zero(), b.Bn.Fq1.Zero()})
+	for i := 0; i < len(sigs); i++ {
+		r = r.Add(sigs[i])
+	}
+	return r.Affine()
+}
+
+// AggregatePublicKeys returns the aggregated public key of the given public
+// keys, that verifies the aggregated signatures of a single message
+func (b BLS) AggregatePublicKeys(pks []bn128.G2Point) bn128.G2Point {
+	r := b.Bn.G2.Point(b.Bn.G2.Zero())
+	for i := 0; i < len(pks); i++ {
+		r = r.Add(pks[i])
+	}
+	return r.Affine()
+}
+
+// AggregateVerify returns true if sig is the aggregation of the signatures
+// of the msgs[i] for the public keys pks[i]. The proof of possession of the
+// public keys must have been verified with PopVerify
+func (b BLS) AggregateVerify(pks []bn128.G2Point, msgs [][]byte, sig bn128.G1Point) bool {
+	return b.coreVerify(pks, msgs, sig, DstSig)
+}
+
+// FastAggregateVerify returns true if sig is the aggregation of the
+// signatures of the same msg for all the public keys pks, with a single
+// pairing check against the aggregated public key. The proof of possession of
+// the public keys must have been verified with PopVerify
+func (b BLS) FastAggregateVerify(pks []bn128.G2Point, msg []byte, sig bn128.G1Point) bool {
+	if len(pks) == 0 {
+		return false
+	}
+	for i := 0; i < len(pks); i++ {
+		if b.ValidatePublicKey(pks[i]) != nil {
+			return false
+		}
+	}
+	return b.Verify(b.AggregatePublicKeys(pks), msg, sig)
+}
+
+// PopProve returns the proof of possession of the secret key sk: the
+// signature of its public key, with the DstPop domain separation tag
+func (b BLS) PopProve(sk *big.Int) bn128.G1Point {
+	pk := b.PublicKey(sk)
+	return b.sign(sk, b.Bn.G2.MarshalBinary(pk.Array()), DstPop)
+}
+
+// PopVerify returns true if proof is a proof of possession of the secret key
+// of the public key pk
+func (b BLS) PopVerify(pk bn128.G2Point, proof bn128.G1Point) bool {
+	msg := b.Bn.G2.MarshalBinary(pk.Array())
+	return b.coreVerify([]bn128.G2Point{pk}, [][]byte{msg}, proof, DstPop)
+}
@@ -0,0 +1,77 @@
+package bls
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	b, err := NewBLS()
+	assert.Nil(t, err)
+
+	sk, pk, err := b.GenerateKeyWithReader(mrand.New(mrand.NewSource(1)))
+	assert.Nil(t, err)
+	assert.Nil(t, b.ValidatePublicKey(pk))
+
+	msg := []byte("proof receipt")
+	sig := b.Sign(sk, msg)
+	assert.True(t, b.Verify(pk, msg, sig))
+	assert.False(t, b.Verify(pk, []byte("another receipt"), sig))
+	assert.False(t, b.Verify(b.PublicKey(big.NewInt(2)), msg, sig))
+	assert.False(t, b.Verify(pk, msg, sig.Double()))
+	assert.False(t, b.Verify(pk.Group().Point(pk.Group().Zero()), msg, b.Aggregate(nil)))
+
+	proof := b.PopProve(sk)
+	assert.True(t, b.PopVerify(pk, proof))
+	assert.False(t, b.PopVerify(b.PublicKey(big.NewInt(2)), proof))
+	// the proof of possession is not a signature of the public key bytes
+	assert.False(t, b.Verify(pk, b.Bn.G2.MarshalBinary(pk.Array()), proof))
+}
+
+func TestAggregate(t *testing.T) {
+	b, err := NewBLS()
+	assert.Nil(t, err)
+
+	rnd := mrand.New(mrand.NewSource(2))
+	msg := []byte("proof receipt")
+	var msgs [][]byte
+	var pks []bn128.G2Point
+	var sigs, sigs2 []bn128.G1Point
+	for i := 0; i < 4; i++ {
+		sk, pk, err := b.GenerateKeyWithReader(rnd)
+		assert.Nil(t, err)
+		assert.True(t, b.PopVerify(pk, b.PopProve(sk)))
+		pks = append(pks, pk)
+		sigs = append(sigs, b.Sign(sk, msg))
+		msgs = append(msgs, []byte{byte(i)})
+		sigs2 = append(sigs2, b.Sign(sk, msgs[i]))
+	}
+
+	sig := b.Aggregate(sigs)
+	assert.True(t, b.FastAggregateVerify(pks, msg, sig))
+	assert.True(t, b.Verify(b.AggregatePublicKeys(pks), msg, sig))
+	assert.False(t, b.FastAggregateVerify(pks[1:], msg, sig))
+	assert.False(t, b.FastAggregateVerify(nil, msg, sig))
+	assert.False(t, b.FastAggregateVerify(pks, []byte("another receipt"), sig))
+
+	sig2 := b.Aggregate(sigs2)
+	assert.True(t, b.AggregateVerify(pks, msgs, sig2))
+	assert.False(t, b.AggregateVerify(pks, msgs[1:], sig2))
+	msgs[0], msgs[1] = msgs[1], msgs[0]
+	assert.False(t, b.AggregateVerify(pks, msgs, sig2))
+
+	// rogue key attack: with the key pkR = a*g2 - pks[0], the aggregated key
+	// of pks[0] and pkR is a*g2, so a*H(msg) verifies for both keys, without
+	// the secret key of pks[0]. The attacker can not give a proof of
+	// possession of pkR
+	a := big.NewInt(12345)
+	pkR := b.PublicKey(a).Sub(pks[0]).Affine()
+	forged := b.Sign(a, msg)
+	assert.True(t, b.FastAggregateVerify([]bn128.G2Point{pks[0], pkR}, msg, forged))
+	assert.False(t, b.PopVerify(pkR, b.PopProve(a)))
+	assert.False(t, b.PopVerify(pkR, b.Sign(a, b.Bn.G2.MarshalBinary(pkR.Array()))))
+}
@@ -0,0 +1,240 @@
+package bls12381
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// Bls12381 is the data structure of the BLS12-381 curve
+// (https://electriccoin.co/blog/new-snark-curve/), y^2 = x^3 + 4 over Fq, with
+// the twist y^2 = x^3 + 4(u+1) over Fq2 = Fq[u]/(u^2+1). It uses the same
+// fields tower than the BN128, and the group operations of bn128.G1 and
+// bn128.G2, that work for any curve y^2 = x^3 + b. Fq has 381 bits, so the
+// fields run over big.Int, without the Montgomery backend
+type Bls12381 struct {
+	Q             *big.Int
+	R             *big.Int
+	X             *big.Int // curve parameter (negative), q = (x-1)^2 (x^4 - x^2 + 1) / 3 + x
+	Gg1           [2]*big.Int
+	Gg2           [2][2]*big.Int
+	NonResidueFq2 *big.Int
+	NonResidueFq6 [2]*big.Int
+	Fq1           fields.Fq
+	Fq2           fields.Fq2
+	Fq6           fields.Fq6
+	Fq12          fields.Fq12
+	G1            bn128.G1
+	G2            bn128.G2
+	FinalExpHard  *big.Int // 3 (q^4 - q^2 + 1) / r, the hard part of the final exponentiation
+}
+
+// NewBls12381 returns the BLS12-381
+func NewBls12381() (Bls12381, error) {
+	var b Bls12381
+	var ok bool
+	b.Q, ok = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	if !ok {
+		return b, errors.New("err with q")
+	}
+	b.R, ok = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+	if !ok {
+		return b, errors.New("err with r")
+	}
+	b.X, ok = new(big.Int).SetString("-d201000000010000", 16)
+	if !ok {
+		return b, errors.New("err with x")
+	}
+
+	g1x, _ := new(big.Int).SetString("17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb", 16)
+	g1y, _ := new(big.Int).SetString("08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1", 16)
+	b.Gg1 = [2]*big.Int{g1x, g1y}
+
+	g2x0, _ := new(big.Int).SetString("024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8", 16)
+	g2x1, _ := new(big.Int).SetString("13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e", 16)
+	g2y0, _ := new(big.Int).SetString("0ce5d527727d6e118cc9cdc6da2e351aadfd9baa8cbdd3a76d429a695160d12c923ac9cc3baca289e193548608b82801", 16)
+	g2y1, _ := new(big.Int).SetString("0606c4a02ea734cc32acd2b02bc28b99cb3e287e85a763af267492ab572e99ab3f370d275cec1da1aaa9075ff05f79be", 16)
+	b.Gg2 = [2][2]*big.Int{
+		{g2x0, g2x1},
+		{g2y0, g2y1},
+	}
+
+	// u^2 = -1, v^3 = u + 1, w^2 = v
+	b.NonResidueFq2 = new(big.Int).Sub(b.Q, big.NewInt(int64(1)))
+	b.NonResidueFq6 = [2]*big.Int{
+		big.NewInt(int64(1)),
+		big.NewInt(int64(1)),
+	}
+
+	b.Fq1 = fields.NewFq(b.Q)
+	b.Fq2 = fields.NewFq2(b.Fq1, b.NonResidueFq2)
+	b.Fq6 = fields.NewFq6(b.Fq2, b.NonResidueFq6)
+	b.Fq12 = fields.NewFq12(b.Fq6, b.Fq2, b.NonResidueFq6)
+
+	b.G1 = bn128.NewG1(b.Fq1, b.Gg1)
+	b.G1.R = b.R
+	b.G2 = bn128.NewG2(b.Fq2, b.Gg2)
+	b.G2.R = b.R
+
+	// the generators are checked, as the curve coefficients are derived from them
+	if !b.Fq1.Equal(b.Fq1.Sub(b.Fq1.Square(g1y), b.Fq1.Mul(b.Fq1.Square(g1x), g1x)), big.NewInt(int64(4))) {
+		return b, errors.New("err with the G1 generator")
+	}
+	twistB := b.Fq2.MulScalar(b.NonResidueFq6, big.NewInt(int64(4)))
+	g2x := b.Gg2[0]
+	if !b.Fq2.Equal(b.Fq2.Sub(b.Fq2.Square(b.Gg2[1]), b.Fq2.Mul(b.Fq2.Square(g2x), g2x)), twistB) {
+		return b, errors.New("err with the G2 generator")
+	}
+
+	q2 := new(big.Int).Mul(b.Q, b.Q)
+	hard := new(big.Int).Add(new(big.Int).Sub(new(big.Int).Mul(q2, q2), q2), big.NewInt(int64(1)))
+	var rem big.Int
+	b.FinalExpHard, _ = new(big.Int).QuoRem(hard, b.R, &rem)
+	if rem.Sign() != 0 {
+		return b, errors.New("err with the final exponentiation")
+	}
+	// the other BLS12-381 implementations (blst, zkcrypto, kilic) compute the
+	// hard part with a chain over x that gives the exponent 3 (q^4 - q^2 + 1) / r,
+	// so the pairing is the cube of the reduced one. It is used here too, to
+	// get the same pairing values
+	b.FinalExpHard.Mul(b.FinalExpHard, big.NewInt(int64(3)))
+
+	return b, nil
+}
+
+// EllCoeffs are the coefficients of a line of the Miller loop, that only
+// depend on the G2 point: the slope Lambda of the line over the twist, and
+// C0 = Lambda*x - y, where (x, y) is the point of the twist in the line
+type EllCoeffs struct {
+	C0     [2]*big.Int
+	Lambda [2]*big.Int
+}
+
+// AteG2Precomp holds the line coefficients of the Miller loop of a G2 point
+type AteG2Precomp struct {
+	Coeffs []EllCoeffs
+}
+
+// PreComputeG2 returns the line coefficients of the Miller loop for the point
+// p. They only depend on p, so for a fixed point they can be computed once
+// and used in many PairingProduct. The point at infinity has no coefficients
+func (bls Bls12381) PreComputeG2(p [3][2]*big.Int) AteG2Precomp {
+	var res AteG2Precomp
+	if bls.G2.IsZero(p) {
+		return res
+	}
+	fq2 := bls.Fq2
+	q := bls.G2.Affine(p)
+	tx, ty := q[0], q[1]
+	loop := new(big.Int).Abs(bls.X)
+	for i := loop.BitLen() - 2; i >= 0; i-- {
+		// tangent at T, over the affine coordinates of the twist
+		lambda := fq2.Div(fq2.MulScalar(fq2.Square(tx), big.NewInt(int64(3))), fq2.Double(ty))
+		res.Coeffs = append(res.Coeffs, EllCoeffs{fq2.Sub(fq2.Mul(lambda, tx), ty), lambda})
+		x3 := fq2.Sub(fq2.Square(lambda), fq2.Double(tx))
+		ty = fq2.Sub(fq2.Mul(lambda, fq2.Sub(tx, x3)), ty)
+		tx = x3
+		if loop.Bit(i) == 1 {
+			// line through T and Q
+			lambda = fq2.Div(fq2.Sub(q[1], ty), fq2.Sub(q[0], tx))
+			res.Coeffs = append(res.Coeffs, EllCoeffs{fq2.Sub(fq2.Mul(lambda, tx), ty), lambda})
+			x3 = fq2.Sub(fq2.Sub(fq2.Square(lambda), tx), q[0])
+			ty = fq2.Sub(fq2.Mul(lambda, fq2.Sub(tx, x3)), ty)
+			tx = x3
+		}
+	}
+	return res
+}
+
+// mulLine multiplies f by the line of the coefficients c evaluated at the G1
+// point p (affine). The line over the curve is y - lambda/w * x + c0/w^3
+// (with the untwist (x, y) -> (x/w^2, y/w^3)), multiplied by w^3, that is
+// removed by the final exponentiation: c0 - lambda*x*v + y*v*w
+func (bls Bls12381) mulLine(f [2][3][2]*big.Int, c EllCoeffs, p [2]*big.Int) [2][3][2]*big.Int {
+	fq2 := bls.Fq2
+	line := [2][3][2]*big.Int{
+		{c.C0, fq2.Neg(fq2.MulScalar(c.Lambda, p[0])), fq2.Zero()},
+		{fq2.Zero(), {p[1], bls.Fq1.Zero()}, fq2.Zero()},
+	}
+	return bls.Fq12.Mul(f, line)
+}
+
+// multiMillerLoop returns the product of the Miller loops of the optimal ate
+// pairing of each pair (p1s in affine coordinates), sharing the squarings
+func (bls Bls12381) multiMillerLoop(p1s [][2]*big.Int, pre2s []AteG2Precomp) [2][3][2]*big.Int {
+	idx := 0
+	f := bls.Fq12.One()
+	mulLines := func() {
+		for j := 0; j < len(p1s); j++ {
+			f = bls.mulLine(f, pre2s[j].Coeffs[idx], p1s[j])
+		}
+		idx++
+	}
+	loop := new(big.Int).Abs(bls.X)
+	for i := loop.BitLen() - 2; i >= 0; i-- {
+		f = bls.Fq12.Square(f)
+		mulLines()
+		if loop.Bit(i) == 1 {
+			mulLines()
+		}
+	}
+	if bls.X.Sign() < 0 {
+		// f_{-x} is the inverse of f_x, up to the final exponentiation
+		f = bls.Fq12.Conjugate(f)
+	}
+	return f
+}
+
+// finalExponentiation computes r^(3(q^12-1)/r), with the easy part
+// (q^6-1)(q^2+1) and the hard part 3(q^4-q^2+1)/r
+func (bls Bls12381) finalExponentiation(r [2][3][2]*big.Int) [2][3][2]*big.Int {
+	fq12 := bls.Fq12
+	f := fq12.Mul(fq12.Conjugate(r), fq12.Inverse(r))
+	f = fq12.Mul(fq12.Frobenius(fq12.Frobenius(f)), f)
+	return fq12.CyclotomicExp(f, bls.FinalExpHard)
+}
+
+// PairingProduct returns the product of the pairings e(g1s[i], q_i), where
+// pre2s[i] = PreComputeG2(q_i), with a single final exponentiation. It panics
+// if the number of G1 points and G2 precomputations is different
+func (bls Bls12381) PairingProduct(g1s [][3]*big.Int, pre2s []AteG2Precomp) [2][3][2]*big.Int {
+	if len(g1s) != len(pre2s) {
+		panic("bls12381: PairingProduct with different number of G1 and G2 points")
+	}
+	var p1s [][2]*big.Int
+	var pre2sNonZero []AteG2Precomp
+	for i := 0; i < len(g1s); i++ {
+		if bls.G1.IsZero(g1s[i]) || len(pre2s[i].Coeffs) == 0 {
+			continue
+		}
+		p1s = append(p1s, bls.G1.Affine(g1s[i]))
+		pre2sNonZero = append(pre2sNonZero, pre2s[i])
+	}
+	if len(p1s) == 0 {
+		return bls.Fq12.One()
+	}
+	return bls.finalExponentiation(bls.multiMillerLoop(p1s, pre2sNonZero))
+}
+
+// Pairing calculates the optimal ate pairing of the BLS12-381
+func (bls Bls12381) Pairing(p1 [3]*big.Int, p2 [3][2]*big.Int) [2][3][2]*big.Int {
+	return bls.PairingProduct([][3]*big.Int{p1}, []AteG2Precomp{bls.PreComputeG2(p2)})
+}
+
+// PairingCheck returns true if the product of the pairings e(g1s[i], g2s[i])
+// is one, as bn128.Bn128.PairingCheck. It returns false if the number of G1
+// and G2 points is different
+func (bls Bls12381) PairingCheck(g1s [][3]*big.Int, g2s [][3][2]*big.Int) bool {
+	if len(g1s) != len(g2s) {
+		return false
+	}
+	pre2s := make([]AteG2Precomp, len(g2s))
+	for i := 0; i < len(g2s); i++ {
+		if !bls.G1.IsZero(g1s[i]) {
+			pre2s[i] = bls.PreComputeG2(g2s[i])
+		}
+	}
+	return bls.Fq12.Equal(bls.PairingProduct(g1s, pre2s), bls.Fq12.One())
+}
@@ -0,0 +1,131 @@
+package bls12381
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerators(t *testing.T) {
+	bls, err := NewBls12381()
+	assert.Nil(t, err)
+
+	assert.Nil(t, bls.G1.Validate(bls.G1.G))
+	assert.Nil(t, bls.G2.Validate(bls.G2.G))
+	assert.True(t, bls.G1.IsZero(bls.G1.MulScalar(bls.G1.G, bls.R)))
+	assert.True(t, bls.G2.IsZero(bls.G2.MulScalar(bls.G2.G, bls.R)))
+
+	// q = (x-1)^2 (x^4 - x^2 + 1) / 3 + x, r = x^4 - x^2 + 1
+	x := bls.X
+	x2 := new(big.Int).Mul(x, x)
+	r := new(big.Int).Add(new(big.Int).Sub(new(big.Int).Mul(x2, x2), x2), big.NewInt(int64(1)))
+	assert.Equal(t, bls.R, r)
+	xm1 := new(big.Int).Sub(x, big.NewInt(int64(1)))
+	q := new(big.Int).Mul(new(big.Int).Mul(xm1, xm1), r)
+	q.Div(q, big.NewInt(int64(3)))
+	assert.Equal(t, bls.Q, q.Add(q, x))
+}
+
+func TestG1CofactorPoint(t *testing.T) {
+	bls, err := NewBls12381()
+	assert.Nil(t, err)
+
+	// a point of the curve y^2 = x^3 + 4 that is not in G1
+	var p [3]*big.Int
+	for x := int64(1); ; x++ {
+		bx := big.NewInt(x)
+		y, ok := bls.Fq1.Sqrt(bls.Fq1.Add(bls.Fq1.Mul(bls.Fq1.Square(bx), bx), big.NewInt(int64(4))))
+		if ok {
+			p = [3]*big.Int{bx, y, bls.Fq1.One()}
+			break
+		}
+	}
+	assert.True(t, bls.G1.IsOnCurve(p))
+	assert.False(t, bls.G1.IsInSubgroup(p))
+	assert.NotNil(t, bls.G1.Validate(p))
+}
+
+func hexToBig(s string) *big.Int {
+	b, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("bad hex " + s)
+	}
+	return b
+}
+
+func TestPairingKnownAnswer(t *testing.T) {
+	bls, err := NewBls12381()
+	assert.Nil(t, err)
+
+	// e(G1, G2) given by github.com/kilic/bls12-381 v0.1.0 (GT.ToBytes of the
+	// result of its Engine, with the coefficients in the same tower)
+	expected := [2][3][2]*big.Int{
+		{
+			{
+				hexToBig("1250ebd871fc0a92a7b2d83168d0d727272d441befa15c503dd8e90ce98db3e7b6d194f60839c508a84305aaca1789b6"),
+				hexToBig("089a1c5b46e5110b86750ec6a532348868a84045483c92b7af5af689452eafabf1a8943e50439f1d59882a98eaa0170f"),
+			},
+			{
+				hexToBig("1368bb445c7c2d209703f239689ce34c0378a68e72a6b3b216da0e22a5031b54ddff57309396b38c881c4c849ec23e87"),
+				hexToBig("193502b86edb8857c273fa075a50512937e0794e1e65a7617c90d8bd66065b1fffe51d7a579973b1315021ec3c19934f"),
+			},
+			{
+				hexToBig("01b2f522473d171391125ba84dc4007cfbf2f8da752f7c74185203fcca589ac719c34dffbbaad8431dad1c1fb597aaa5"),
+				hexToBig("018107154f25a764bd3c79937a45b84546da634b8f6be14a8061e55cceba478b23f7dacaa35c8ca78beae9624045b4b6"),
+			},
+		},
+		{
+			{
+				hexToBig("19f26337d205fb469cd6bd15c3d5a04dc88784fbb3d0b2dbdea54d43b2b73f2cbb12d58386a8703e0f948226e47ee89d"),
+				hexToBig("06fba23eb7c5af0d9f80940ca771b6ffd5857baaf222eb95a7d2809d61bfe02e1bfd1b68ff02f0b8102ae1c2d5d5ab1a"),
+			},
+			{
+				hexToBig("11b8b424cd48bf38fcef68083b0b0ec5c81a93b330ee1a677d0d15ff7b984e8978ef48881e32fac91b93b47333e2ba57"),
+				hexToBig("03350f55a7aefcd3c31b4fcb6ce5771cc6a0e9786ab5973320c806ad360829107ba810c5a09ffdd9be2291a0c25a99a2"),
+			},
+			{
+				hexToBig("04c581234d086a9902249b64728ffd21a189e87935a954051c7cdba7b3872629a4fafc05066245cb9108f0242d0fe3ef"),
+				hexToBig("0f41e58663bf08cf068672cbd01a7ec73baca4d72ca93544deff686bfd6df543d48eaa24afe47e1efde449383b676631"),
+			},
+		},
+	}
+	assert.True(t, bls.Fq12.Equal(expected, bls.Pairing(bls.G1.G, bls.G2.G)))
+	pre := bls.PreComputeG2(bls.G2.G)
+	assert.True(t, bls.Fq12.Equal(expected, bls.PairingProduct([][3]*big.Int{bls.G1.G}, []AteG2Precomp{pre})))
+}
+
+func TestPairing(t *testing.T) {
+	bls, err := NewBls12381()
+	assert.Nil(t, err)
+
+	a := big.NewInt(int64(25))
+	b := big.NewInt(int64(30))
+	g1a := bls.G1.MulScalar(bls.G1.G, a)
+	g2b := bls.G2.MulScalar(bls.G2.G, b)
+	g1b := bls.G1.MulScalar(bls.G1.G, b)
+	g2a := bls.G2.MulScalar(bls.G2.G, a)
+
+	// bilinearity
+	pA := bls.Pairing(g1a, g2b)
+	pB := bls.Pairing(g1b, g2a)
+	assert.True(t, bls.Fq12.Equal(pA, pB))
+	pG := bls.Pairing(bls.G1.G, bls.G2.G)
+	assert.False(t, bls.Fq12.Equal(pG, bls.Fq12.One()))
+	assert.True(t, bls.Fq12.Equal(pA, bls.Fq12.Exp(pG, new(big.Int).Mul(a, b))))
+	// the pairing values are of order R
+	assert.True(t, bls.Fq12.Equal(bls.Fq12.Exp(pG, bls.R), bls.Fq12.One()))
+
+	// e(a*G1, b*G2) * e(-ab*G1, G2) == 1
+	g1NegAB := bls.G1.Neg(bls.G1.MulScalar(bls.G1.G, new(big.Int).Mul(a, b)))
+	assert.True(t, bls.PairingCheck(
+		[][3]*big.Int{g1a, g1NegAB},
+		[][3][2]*big.Int{g2b, bls.G2.G}))
+	assert.False(t, bls.PairingCheck(
+		[][3]*big.Int{g1a, bls.G1.Neg(bls.G1.G)},
+		[][3][2]*big.Int{g2b, bls.G2.G}))
+	assert.True(t, bls.PairingCheck(
+		[][3]*big.Int{g1a, g1NegAB, g1a},
+		[][3][2]*big.Int{g2b, bls.G2.G, bls.G2.Zero()}))
+	assert.False(t, bls.PairingCheck([][3]*big.Int{g1a}, nil))
+}
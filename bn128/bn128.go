@@ -32,6 +32,7 @@ type Bn128 struct {
 	TwistMulByQX       [2]*big.Int
 	TwistMulByQY       [2]*big.Int
 	FinalExp           *big.Int
+	X                  *big.Int // curve parameter, q = 36x^4 + 36x^3 + 24x^2 + 6x + 1
 }
 
 // NewBn128 returns the BN128
@@ -98,12 +99,26 @@ func NewBn128() (Bn128, error) {
 
 	b.G1 = NewG1(b.Fq1, b.Gg1)
 	b.G2 = NewG2(b.Fq2, b.Gg2)
+	b.G2.R = r
 
 	err := b.preparePairing()
 	if err != nil {
 		return b, err
 	}
 
+	if b.Fq1.Montgomery() != nil {
+		b.G1.glv, err = newG1GLV(b.G1, r)
+		if err != nil {
+			return b, err
+		}
+		b.G2.gls, err = newG2GLS(b.G2, r, b.X, b.TwistMulByQX, b.TwistMulByQY)
+		if err != nil {
+			return b, err
+		}
+	}
+	b.G1.svdwC = newG1SvdW(b.G1)
+	b.G2.svdwC = newG2SvdW(b.G2)
+
 	return b, nil
 }
 
@@ -170,6 +185,10 @@ func (bn128 *Bn128) preparePairing() error {
 	if !ok {
 		return errors.New("error parsing finalExp")
 	}
+	bn128.X, ok = new(big.Int).SetString("4965661367192848881", 10)
+	if !ok {
+		return errors.New("error parsing x")
+	}
 
 	return nil
 
@@ -177,14 +196,63 @@ func (bn128 *Bn128) preparePairing() error {
 
 // Pairing calculates the BN128 Pairing of two given values
 func (bn128 Bn128) Pairing(p1 [3]*big.Int, p2 [3][2]*big.Int) [2][3][2]*big.Int {
+	if bn128.G1.IsZero(p1) || bn128.G2.IsZero(p2) {
+		return bn128.Fq12.One()
+	}
 	pre1 := bn128.preComputeG1(p1)
-	pre2 := bn128.preComputeG2(p2)
+	pre2 := bn128.PreComputeG2(p2)
 
 	r1 := bn128.MillerLoop(pre1, pre2)
 	res := bn128.finalExponentiation(r1)
 	return res
 }
 
+// PairingCheck returns true if the product of the pairings e(g1s[i], g2s[i])
+// is one, as the ecPairing precompile of Ethereum (EIP-197). The Miller loops
+// of all the pairs share the squarings, and are followed by a single final
+// exponentiation, so it is much cheaper than comparing products of Pairing.
+// The pairs with a point at infinity are skipped, as their pairing is one. It
+// returns false if the number of G1 and G2 points is different
+func (bn128 Bn128) PairingCheck(g1s [][3]*big.Int, g2s [][3][2]*big.Int) bool {
+	if len(g1s) != len(g2s) {
+		return false
+	}
+	var ps [][3]*big.Int
+	var pre2s []AteG2Precomp
+	for i := 0; i < len(g1s); i++ {
+		if bn128.G1.IsZero(g1s[i]) || bn128.G2.IsZero(g2s[i]) {
+			continue
+		}
+		ps = append(ps, g1s[i])
+		pre2s = append(pre2s, bn128.PreComputeG2(g2s[i]))
+	}
+	return bn128.Fq12.Equal(bn128.PairingProduct(ps, pre2s), bn128.Fq12.One())
+}
+
+// PairingProduct returns the product of the pairings e(g1s[i], q_i), where
+// pre2s[i] = PreComputeG2(q_i), with a single final exponentiation as in
+// PairingCheck. The G2 precomputations can be reused across calls, for the
+// points that are fixed. It panics if the number of G1 points and G2
+// precomputations is different
+func (bn128 Bn128) PairingProduct(g1s [][3]*big.Int, pre2s []AteG2Precomp) [2][3][2]*big.Int {
+	if len(g1s) != len(pre2s) {
+		panic("bn128: PairingProduct with different number of G1 and G2 points")
+	}
+	var pre1s []AteG1Precomp
+	var pre2sNonZero []AteG2Precomp
+	for i := 0; i < len(g1s); i++ {
+		if bn128.G1.IsZero(g1s[i]) || len(pre2s[i].Coeffs) == 0 {
+			continue
+		}
+		pre1s = append(pre1s, bn128.preComputeG1(g1s[i]))
+		pre2sNonZero = append(pre2sNonZero, pre2s[i])
+	}
+	if len(pre1s) == 0 {
+		return bn128.Fq12.One()
+	}
+	return bn128.finalExponentiation(bn128.multiMillerLoop(pre1s, pre2sNonZero))
+}
+
 type AteG1Precomp struct {
 	Px *big.Int
 	Py *big.Int
@@ -210,7 +278,13 @@ type AteG2Precomp struct {
 	Coeffs []EllCoeffs
 }
 
-func (bn128 Bn128) preComputeG2(p [3][2]*big.Int) AteG2Precomp {
+// PreComputeG2 returns the line coefficients of the Miller loop for the point
+// p, that only depend on p, so for a fixed point they can be computed once and
+// used in many PairingProduct. The point at infinity has no coefficients
+func (bn128 Bn128) PreComputeG2(p [3][2]*big.Int) AteG2Precomp {
+	if bn128.G2.IsZero(p) {
+		return AteG2Precomp{}
+	}
 	qCopy := bn128.G2.Affine(p)
 	res := AteG2Precomp{
 		qCopy[0],
@@ -399,6 +473,38 @@ func (bn128 Bn128) MillerLoop(pre1 AteG1Precomp, pre2 AteG2Precomp) [2][3][2]*bi
 	return f
 }
 
+// multiMillerLoop returns the product of the MillerLoop of each pair, doing
+// the squarings of the accumulated value once for all of them
+func (bn128 Bn128) multiMillerLoop(pre1s []AteG1Precomp, pre2s []AteG2Precomp) [2][3][2]*big.Int {
+	idx := 0
+	f := bn128.Fq12.One()
+	mulLines := func() {
+		for j := 0; j < len(pre1s); j++ {
+			c := pre2s[j].Coeffs[idx]
+			f = bn128.mulBy024(
+				f,
+				c.Ell0,
+				bn128.Fq2.MulScalar(c.EllVW, pre1s[j].Py),
+				bn128.Fq2.MulScalar(c.EllVV, pre1s[j].Px))
+		}
+		idx++
+	}
+
+	for i := bn128.LoopCount.BitLen() - 2; i >= 0; i-- {
+		f = bn128.Fq12.Square(f)
+		mulLines()
+		if bn128.LoopCount.Bit(i) == 1 {
+			mulLines()
+		}
+	}
+	if bn128.LoopCountNeg {
+		f = bn128.Fq12.Inverse(f)
+	}
+	mulLines()
+	mulLines()
+	return f
+}
+
 func (bn128 Bn128) mulBy024(a [2][3][2]*big.Int, ell0, ellVW, ellVV [2]*big.Int) [2][3][2]*big.Int {
 	b := [2][3][2]*big.Int{
 		[3][2]*big.Int{
@@ -415,7 +521,39 @@ func (bn128 Bn128) mulBy024(a [2][3][2]*big.Int, ell0, ellVW, ellVV [2]*big.Int)
 	return bn128.Fq12.Mul(a, b)
 }
 
+// finalExponentiation computes r^((q^12-1)/r) (r^FinalExp), splitting the
+// exponent into the easy part (q^6-1)(q^2+1) and the hard part (q^4-q^2+1)/r
 func (bn128 Bn128) finalExponentiation(r [2][3][2]*big.Int) [2][3][2]*big.Int {
-	res := bn128.Fq12.Exp(r, bn128.FinalExp)
-	return res
+	fq12 := bn128.Fq12
+
+	// easy part, after it the value is in the cyclotomic subgroup
+	f := fq12.Mul(fq12.Conjugate(r), fq12.Inverse(r))
+	f = fq12.Mul(fq12.Frobenius(fq12.Frobenius(f)), f)
+
+	// hard part, following Scott et al., On the Final Exponentiation for
+	// Calculating Pairings on Ordinary Elliptic Curves, https://eprint.iacr.org/2008/490.pdf
+	fp := fq12.Frobenius(f)
+	fp2 := fq12.Frobenius(fp)
+	fp3 := fq12.Frobenius(fp2)
+
+	fx := fq12.CyclotomicExp(f, bn128.X)
+	fx2 := fq12.CyclotomicExp(fx, bn128.X)
+	fx3 := fq12.CyclotomicExp(fx2, bn128.X)
+
+	y0 := fq12.Mul(fq12.Mul(fp, fp2), fp3)
+	y1 := fq12.Conjugate(f)
+	y2 := fq12.Frobenius(fq12.Frobenius(fx2))
+	y3 := fq12.Conjugate(fq12.Frobenius(fx))
+	y4 := fq12.Conjugate(fq12.Mul(fx, fq12.Frobenius(fx2)))
+	y5 := fq12.Conjugate(fx2)
+	y6 := fq12.Conjugate(fq12.Mul(fx3, fq12.Frobenius(fx3)))
+
+	t0 := fq12.Mul(fq12.Mul(fq12.CyclotomicSquare(y6), y4), y5)
+	t1 := fq12.Mul(fq12.Mul(y3, y5), t0)
+	t0 = fq12.Mul(t0, y2)
+	t1 = fq12.CyclotomicSquare(fq12.Mul(fq12.CyclotomicSquare(t1), t0))
+	t0 = fq12.Mul(t1, y1)
+	t1 = fq12.Mul(t1, y0)
+	t0 = fq12.Mul(fq12.CyclotomicSquare(t0), t1)
+	return t0
 }
@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/arnaucube/go-snark/fields"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,10 +23,10 @@ func TestBN128(t *testing.T) {
 	g2b := bn128.G2.MulScalar(bn128.G2.G, bn128.Fq1.Copy(big40))
 
 	pre1a := bn128.preComputeG1(g1a)
-	pre2a := bn128.preComputeG2(g2a)
+	pre2a := bn128.PreComputeG2(g2a)
 	assert.Nil(t, err)
 	pre1b := bn128.preComputeG1(g1b)
-	pre2b := bn128.preComputeG2(g2b)
+	pre2b := bn128.PreComputeG2(g2b)
 	assert.Nil(t, err)
 
 	r1 := bn128.MillerLoop(pre1a, pre2a)
@@ -86,3 +87,145 @@ func TestBN128Pairing2(t *testing.T) {
 	assert.True(t, bn.Fq12.Equal(gt6, bn.Pairing(bn.G1.MulScalar(bn.G1.G, big.NewInt(int64(2))), bn.G2.MulScalar(bn.G2.G, big.NewInt(int64(3))))))
 
 }
+
+func TestFinalExponentiation(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	g1 := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(25)))
+	g2 := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(30)))
+	r := bn128.MillerLoop(bn128.preComputeG1(g1), bn128.PreComputeG2(g2))
+
+	// Frobenius is the exponentiation by q
+	assert.Equal(t, bn128.Fq12.Exp(r, bn128.Q), bn128.Fq12.Frobenius(r))
+
+	// the value after the easy part is in the cyclotomic subgroup
+	f := bn128.Fq12.Mul(bn128.Fq12.Conjugate(r), bn128.Fq12.Inverse(r))
+	f = bn128.Fq12.Mul(bn128.Fq12.Frobenius(bn128.Fq12.Frobenius(f)), f)
+	assert.Equal(t, bn128.Fq12.Square(f), bn128.Fq12.CyclotomicSquare(f))
+	assert.Equal(t, bn128.Fq12.Exp(f, bn128.X), bn128.Fq12.CyclotomicExp(f, bn128.X))
+
+	assert.Equal(t, bn128.Fq12.Exp(r, bn128.FinalExp), bn128.finalExponentiation(r))
+}
+
+func TestPairingCheck(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	a := big.NewInt(int64(25))
+	b := big.NewInt(int64(30))
+	ab := new(big.Int).Mul(a, b)
+	g1a := bn128.G1.MulScalar(bn128.G1.G, a)
+	g2b := bn128.G2.MulScalar(bn128.G2.G, b)
+	g1NegAB := bn128.G1.Neg(bn128.G1.MulScalar(bn128.G1.G, ab))
+
+	// e(a*G1, b*G2) * e(-ab*G1, G2) == 1
+	assert.True(t, bn128.PairingCheck(
+		[][3]*big.Int{g1a, g1NegAB},
+		[][3][2]*big.Int{g2b, bn128.G2.G}))
+	assert.False(t, bn128.PairingCheck(
+		[][3]*big.Int{g1a, bn128.G1.Neg(bn128.G1.G)},
+		[][3][2]*big.Int{g2b, bn128.G2.G}))
+	assert.False(t, bn128.PairingCheck(
+		[][3]*big.Int{g1a},
+		[][3][2]*big.Int{g2b}))
+
+	// the pairs with the point at infinity are skipped
+	zero := [3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.Zero(), bn128.Fq1.Zero()}
+	assert.True(t, bn128.PairingCheck(
+		[][3]*big.Int{g1a, zero, g1NegAB, g1a},
+		[][3][2]*big.Int{g2b, g2b, bn128.G2.G, bn128.G2.Zero()}))
+	assert.True(t, bn128.PairingCheck(nil, nil))
+	assert.False(t, bn128.PairingCheck([][3]*big.Int{g1a}, nil))
+
+	// the shared Miller loop gives the same than the product of the pairings
+	pre1s := []AteG1Precomp{bn128.preComputeG1(g1a), bn128.preComputeG1(bn128.G1.G)}
+	pre2s := []AteG2Precomp{bn128.PreComputeG2(g2b), bn128.PreComputeG2(g2b)}
+	assert.True(t, bn128.Fq12.Equal(
+		bn128.Fq12.Mul(bn128.Pairing(g1a, g2b), bn128.Pairing(bn128.G1.G, g2b)),
+		bn128.finalExponentiation(bn128.multiMillerLoop(pre1s, pre2s))))
+}
+
+func TestPairingProduct(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	g1a := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(25)))
+	g2b := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(30)))
+	pre2b := bn128.PreComputeG2(g2b)
+	pre2g := bn128.PreComputeG2(bn128.G2.G)
+
+	// the precomputations can be reused
+	for i := 0; i < 2; i++ {
+		assert.True(t, bn128.Fq12.Equal(
+			bn128.Fq12.Mul(bn128.Pairing(g1a, g2b), bn128.Pairing(bn128.G1.G, bn128.G2.G)),
+			bn128.PairingProduct([][3]*big.Int{g1a, bn128.G1.G}, []AteG2Precomp{pre2b, pre2g})))
+	}
+
+	// the pairs with the point at infinity are skipped
+	zero := [3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.Zero(), bn128.Fq1.Zero()}
+	assert.True(t, bn128.Fq12.Equal(
+		bn128.Pairing(g1a, g2b),
+		bn128.PairingProduct(
+			[][3]*big.Int{g1a, zero, g1a},
+			[]AteG2Precomp{pre2b, pre2g, bn128.PreComputeG2(bn128.G2.Zero())})))
+	assert.True(t, bn128.Fq12.Equal(bn128.Fq12.One(), bn128.Pairing(g1a, bn128.G2.Zero())))
+	assert.True(t, bn128.Fq12.Equal(bn128.Fq12.One(), bn128.PairingProduct(nil, nil)))
+}
+
+func TestFqSqrt(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	fqR, err := NewFqR()
+	assert.Nil(t, err)
+
+	// Q = 3 mod 4, R = 1 mod 4
+	for _, fq := range []fields.Fq{bn128.Fq1, fqR} {
+		for i := 0; i < 10; i++ {
+			x, err := fq.Rand()
+			assert.Nil(t, err)
+			a := fq.Square(x)
+			assert.Equal(t, 1, fq.Legendre(a))
+			assert.True(t, fq.IsSquare(a))
+			r, ok := fq.Sqrt(a)
+			assert.True(t, ok)
+			assert.True(t, fq.Equal(a, fq.Square(r)))
+			assert.True(t, fq.Equal(r, x) || fq.Equal(r, fq.Neg(x)))
+		}
+		nonResidue := big.NewInt(int64(2))
+		for fq.IsSquare(nonResidue) {
+			nonResidue = fq.Add(nonResidue, fq.One())
+		}
+		assert.Equal(t, -1, fq.Legendre(nonResidue))
+		_, ok := fq.Sqrt(nonResidue)
+		assert.False(t, ok)
+		r, ok := fq.Sqrt(fq.Zero())
+		assert.True(t, ok)
+		assert.True(t, fq.IsZero(r))
+	}
+}
+
+func TestFq2Sqrt(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	for i := 0; i < 10; i++ {
+		x0, err := bn128.Fq1.Rand()
+		assert.Nil(t, err)
+		x1, err := bn128.Fq1.Rand()
+		assert.Nil(t, err)
+		x := [2]*big.Int{x0, x1}
+		a := bn128.Fq2.Square(x)
+		r, ok := bn128.Fq2.Sqrt(a)
+		assert.True(t, ok)
+		assert.True(t, bn128.Fq2.Equal(a, bn128.Fq2.Square(r)))
+
+		// elements of Fq embedded in Fq2 are always squares
+		r, ok = bn128.Fq2.Sqrt([2]*big.Int{x0, bn128.Fq1.Zero()})
+		assert.True(t, ok)
+		assert.True(t, bn128.Fq2.Equal([2]*big.Int{x0, bn128.Fq1.Zero()}, bn128.Fq2.Square(r)))
+	}
+	// ξ = 9 + u is not a square in Fq2
+	_, ok := bn128.Fq2.Sqrt(bn128.NonResidueFq6)
+	assert.False(t, ok)
+}
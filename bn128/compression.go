@@ -0,0 +1,153 @@
+package bn128
+
+import (
+	"errors"
+	"math/big"
+)
+
+// The compressed encoding stores only the x coordinate (32 bytes for G1 and
+// 64 bytes for G2, as in MarshalBinary), and uses the two most significant
+// bits of the first byte, which are always zero as Q has 254 bits, as flags:
+//
+//	10: y is the smallest of the two roots
+//	11: y is the largest of the two roots (y > (Q-1)/2)
+//	01: point at infinity, the rest of the bytes are zero
+const (
+	compressedMask     = byte(0xc0)
+	compressedSmallest = byte(0x80)
+	compressedLargest  = byte(0xc0)
+	compressedInfinity = byte(0x40)
+)
+
+// coefB returns the b coefficient of the curve y^2 = x^3 + b, from the generator
+func (g1 G1) coefB() *big.Int {
+	return g1.F.Sub(g1.F.Square(g1.G[1]), g1.F.Mul(g1.F.Square(g1.G[0]), g1.G[0]))
+}
+
+// coefB returns the b coefficient of the twist y^2 = x^3 + b, from the generator
+func (g2 G2) coefB() [2]*big.Int {
+	return g2.F.Sub(g2.F.Square(g2.G[1]), g2.F.Mul(g2.F.Square(g2.G[0]), g2.G[0]))
+}
+
+// isLargest returns true if a > (Q-1)/2
+func isLargest(q, a *big.Int) bool {
+	half := new(big.Int).Rsh(q, 1)
+	return a.Cmp(half) > 0
+}
+
+// isLargestFq2 compares a[1], or a[0] if a[1] is zero
+func isLargestFq2(q *big.Int, a [2]*big.Int) bool {
+	if a[1].Sign() != 0 {
+		return isLargest(q, a[1])
+	}
+	return isLargest(q, a[0])
+}
+
+// Compress returns the 32 bytes compressed encoding of the point
+func (g1 G1) Compress(p [3]*big.Int) []byte {
+	if g1.IsZero(p) {
+		b := make([]byte, g1.F.ByteLen())
+		b[0] = compressedInfinity
+		return b
+	}
+	a := g1.Affine(p)
+	b := g1.F.MarshalBinary(a[0])
+	if isLargest(g1.F.Q, g1.F.Affine(a[1])) {
+		b[0] |= compressedLargest
+	} else {
+		b[0] |= compressedSmallest
+	}
+	return b
+}
+
+// Decompress decodes a point encoded with Compress, returning it with z = 1,
+// or all zeros for the point at infinity. It rejects the non canonical
+// encodings, and the x values that are not on the curve with an InvalidPointError
+func (g1 G1) Decompress(b []byte) ([3]*big.Int, error) {
+	if len(b) != g1.F.ByteLen() {
+		return [3]*big.Int{}, errors.New("invalid compressed G1 point length")
+	}
+	flags := b[0] & compressedMask
+	xb := append([]byte{}, b...)
+	xb[0] &^= compressedMask
+	switch flags {
+	case compressedInfinity:
+		if new(big.Int).SetBytes(xb).Sign() != 0 {
+			return [3]*big.Int{}, errors.New("invalid compressed G1 point at infinity")
+		}
+		return [3]*big.Int{g1.F.Zero(), g1.F.Zero(), g1.F.Zero()}, nil
+	case compressedSmallest, compressedLargest:
+	default:
+		return [3]*big.Int{}, errors.New("invalid compressed G1 point flags")
+	}
+	x, err := g1.F.UnmarshalBinary(xb)
+	if err != nil {
+		return [3]*big.Int{}, err
+	}
+	y2 := g1.F.Add(g1.F.Mul(g1.F.Square(x), x), g1.coefB())
+	y, ok := g1.F.Sqrt(y2)
+	if !ok {
+		return [3]*big.Int{}, &InvalidPointError{"G1", ErrNotOnCurve}
+	}
+	if isLargest(g1.F.Q, y) != (flags == compressedLargest) {
+		y = g1.F.Neg(y)
+	}
+	return [3]*big.Int{x, y, g1.F.One()}, nil
+}
+
+// Compress returns the 64 bytes compressed encoding of the point
+func (g2 G2) Compress(p [3][2]*big.Int) []byte {
+	if g2.IsZero(p) {
+		b := make([]byte, 2*g2.F.F.ByteLen())
+		b[0] = compressedInfinity
+		return b
+	}
+	a := g2.Affine(p)
+	b := g2.F.MarshalBinary(a[0])
+	if isLargestFq2(g2.F.F.Q, g2.F.Affine(a[1])) {
+		b[0] |= compressedLargest
+	} else {
+		b[0] |= compressedSmallest
+	}
+	return b
+}
+
+// Decompress decodes a point encoded with Compress, returning it with z = 1,
+// or G2.Zero() for the point at infinity. It rejects the non canonical
+// encodings, and the points not in G2 with an InvalidPointError
+func (g2 G2) Decompress(b []byte) ([3][2]*big.Int, error) {
+	if len(b) != 2*g2.F.F.ByteLen() {
+		return [3][2]*big.Int{}, errors.New("invalid compressed G2 point length")
+	}
+	flags := b[0] & compressedMask
+	xb := append([]byte{}, b...)
+	xb[0] &^= compressedMask
+	switch flags {
+	case compressedInfinity:
+		if new(big.Int).SetBytes(xb).Sign() != 0 {
+			return [3][2]*big.Int{}, errors.New("invalid compressed G2 point at infinity")
+		}
+		return g2.Zero(), nil
+	case compressedSmallest, compressedLargest:
+	default:
+		return [3][2]*big.Int{}, errors.New("invalid compressed G2 point flags")
+	}
+	x, err := g2.F.UnmarshalBinary(xb)
+	if err != nil {
+		return [3][2]*big.Int{}, err
+	}
+	y2 := g2.F.Add(g2.F.Mul(g2.F.Square(x), x), g2.coefB())
+	y, ok := g2.F.Sqrt(y2)
+	if !ok {
+		return [3][2]*big.Int{}, &InvalidPointError{"G2", ErrNotOnCurve}
+	}
+	y = g2.F.Affine(y)
+	if isLargestFq2(g2.F.F.Q, y) != (flags == compressedLargest) {
+		y = g2.F.Neg(y)
+	}
+	p := [3][2]*big.Int{x, y, g2.F.One()}
+	if !g2.IsInSubgroup(p) {
+		return [3][2]*big.Int{}, &InvalidPointError{"G2", ErrNotInSubgroup}
+	}
+	return p, nil
+}
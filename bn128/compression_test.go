@@ -0,0 +1,71 @@
+package bn128
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestG1Compress(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	for i := 1; i < 20; i++ {
+		p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(i*7919)))
+		for _, q := range [][3]*big.Int{p, bn128.G1.Neg(p)} {
+			b := bn128.G1.Compress(q)
+			assert.Equal(t, 32, len(b))
+			q2, err := bn128.G1.Decompress(b)
+			assert.Nil(t, err)
+			assert.True(t, bn128.G1.Equal(q, q2))
+		}
+	}
+
+	zero := [3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.Zero(), bn128.Fq1.Zero()}
+	b := bn128.G1.Compress(zero)
+	assert.Equal(t, byte(0x40), b[0])
+	p, err := bn128.G1.Decompress(b)
+	assert.Nil(t, err)
+	assert.True(t, bn128.G1.IsZero(p))
+
+	// no flags (uncompressed x), and x = 0 which is not on the curve (3 is not a square)
+	b = make([]byte, 32)
+	_, err = bn128.G1.Decompress(b)
+	assert.NotNil(t, err)
+	b[0] = 0x80
+	_, err = bn128.G1.Decompress(b)
+	assert.NotNil(t, err)
+	// infinity with data
+	b[0] = 0x40
+	b[31] = 1
+	_, err = bn128.G1.Decompress(b)
+	assert.NotNil(t, err)
+}
+
+func TestG2Compress(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	for i := 1; i < 10; i++ {
+		p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(i*7919)))
+		for _, q := range [][3][2]*big.Int{p, bn128.G2.Neg(p)} {
+			b := bn128.G2.Compress(q)
+			assert.Equal(t, 64, len(b))
+			q2, err := bn128.G2.Decompress(b)
+			assert.Nil(t, err)
+			assert.True(t, bn128.G2.Equal(q, q2))
+		}
+	}
+
+	b := bn128.G2.Compress(bn128.G2.Zero())
+	assert.Equal(t, byte(0x40), b[0])
+	p, err := bn128.G2.Decompress(b)
+	assert.Nil(t, err)
+	assert.True(t, bn128.G2.IsZero(p))
+
+	_, err = bn128.G2.Decompress(make([]byte, 64))
+	assert.NotNil(t, err)
+	_, err = bn128.G2.Decompress(make([]byte, 32))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,327 @@
+package bn128
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// The functions in this file implement the scalar multiplication for secret
+// scalars. It is a Montgomery ladder over a fixed number of bits, that uses
+// the complete addition formulas for a = 0 curves of Renes, Costello and
+// Batina (https://eprint.iacr.org/2015/1060.pdf, algorithm 7) in homogeneous
+// projective coordinates, so there are no branches that depend on the values
+// of the scalar or of the intermediate points. The field arithmetic is done
+// with the Montgomery Elements, which are also branch free. The conversions of
+// the *big.Int points and scalars at the start and at the end are not.
+
+// ErrNoConstantTime is the panic value of MulScalarCT and MultiExpCT over a
+// field without the Montgomery backend, where the arithmetic is not constant
+// time. HasConstantTime tells if they can be used
+var ErrNoConstantTime = errors.New("constant time scalar multiplication needs the montgomery backend")
+
+// ctScalarBits is the minimum number of bits processed by the ladder, so the
+// running time does not depend on the bit length of the scalar
+const ctScalarBits = 256
+
+// ctIsZero returns 1 if x is zero, or 0 otherwise, without branching
+func ctIsZero(x *fields.Element) uint {
+	v := x[0] | x[1] | x[2] | x[3]
+	return uint(1 ^ ((v | -v) >> 63))
+}
+
+func ctBits(e *big.Int) int {
+	if e.BitLen() > ctScalarBits {
+		return e.BitLen()
+	}
+	return ctScalarBits
+}
+
+// g1Proj is a G1 point in homogeneous projective coordinates (x = X/Z,
+// y = Y/Z), in Montgomery form. The point at infinity is (0, 1, 0)
+type g1Proj [3]fields.Element
+
+// g1MontToProj converts a Jacobian point (x = X/Z^2, y = Y/Z^3) into (X*Z, Y, Z^3)
+func g1MontToProj(m *fields.Montgomery, p *g1Mont) g1Proj {
+	var r g1Proj
+	one := m.One()
+	m.Mul(&r[0], &p[0], &p[2])
+	m.Select(&r[1], &one, &p[1], ctIsZero(&p[2]))
+	m.Square(&r[2], &p[2])
+	m.Mul(&r[2], &r[2], &p[2])
+	return r
+}
+
+// g1ProjToMont converts a projective point into the Jacobian point (X*Z, Y*Z^2, Z)
+func g1ProjToMont(m *fields.Montgomery, p *g1Proj) g1Mont {
+	var r g1Mont
+	m.Mul(&r[0], &p[0], &p[2])
+	m.Square(&r[1], &p[2])
+	m.Mul(&r[1], &r[1], &p[1])
+	r[2] = p[2]
+	return r
+}
+
+// g1ProjAdd is the complete addition, valid also for doubling and the point at infinity. b3 is 3*b
+func g1ProjAdd(m *fields.Montgomery, b3 *fields.Element, p1, p2 *g1Proj) g1Proj {
+	var t0, t1, t2, t3, t4, x3, y3, z3 fields.Element
+	m.Mul(&t0, &p1[0], &p2[0])
+	m.Mul(&t1, &p1[1], &p2[1])
+	m.Mul(&t2, &p1[2], &p2[2])
+	m.Add(&t3, &p1[0], &p1[1])
+	m.Add(&t4, &p2[0], &p2[1])
+	m.Mul(&t3, &t3, &t4)
+	m.Add(&t4, &t0, &t1)
+	m.Sub(&t3, &t3, &t4)
+	m.Add(&t4, &p1[1], &p1[2])
+	m.Add(&x3, &p2[1], &p2[2])
+	m.Mul(&t4, &t4, &x3)
+	m.Add(&x3, &t1, &t2)
+	m.Sub(&t4, &t4, &x3)
+	m.Add(&x3, &p1[0], &p1[2])
+	m.Add(&y3, &p2[0], &p2[2])
+	m.Mul(&x3, &x3, &y3)
+	m.Add(&y3, &t0, &t2)
+	m.Sub(&y3, &x3, &y3)
+	m.Double(&x3, &t0)
+	m.Add(&t0, &x3, &t0)
+	m.Mul(&t2, b3, &t2)
+	m.Add(&z3, &t1, &t2)
+	m.Sub(&t1, &t1, &t2)
+	m.Mul(&y3, b3, &y3)
+	m.Mul(&x3, &t4, &y3)
+	m.Mul(&t2, &t3, &t1)
+	m.Sub(&x3, &t2, &x3)
+	m.Mul(&y3, &y3, &t0)
+	m.Mul(&t1, &t1, &z3)
+	m.Add(&y3, &t1, &y3)
+	m.Mul(&t0, &t0, &t3)
+	m.Mul(&z3, &z3, &t4)
+	m.Add(&z3, &z3, &t0)
+	return g1Proj{x3, y3, z3}
+}
+
+// g1ProjSwap swaps p1 and p2 if cond == 1, without branching
+func g1ProjSwap(m *fields.Montgomery, p1, p2 *g1Proj, cond uint) {
+	for i := 0; i < 3; i++ {
+		a, b := p1[i], p2[i]
+		m.Select(&p1[i], &b, &a, cond)
+		m.Select(&p2[i], &a, &b, cond)
+	}
+}
+
+func g1ProjMulScalar(m *fields.Montgomery, b3 *fields.Element, p *g1Proj, e *big.Int) g1Proj {
+	r0 := g1Proj{fields.Element{}, m.One(), fields.Element{}}
+	r1 := *p
+	for i := ctBits(e) - 1; i >= 0; i-- {
+		bit := e.Bit(i)
+		g1ProjSwap(m, &r0, &r1, bit)
+		r1 = g1ProjAdd(m, b3, &r0, &r1)
+		r0 = g1ProjAdd(m, b3, &r0, &r0)
+		g1ProjSwap(m, &r0, &r1, bit)
+	}
+	return r0
+}
+
+// g1MontB3 returns 3*b, with b = y^2 - x^3 computed from the generator
+func g1MontB3(m *fields.Montgomery, g [3]*big.Int) fields.Element {
+	x := m.ToMont(g[0])
+	y := m.ToMont(g[1])
+	var b, x3 fields.Element
+	m.Square(&b, &y)
+	m.Square(&x3, &x)
+	m.Mul(&x3, &x3, &x)
+	m.Sub(&b, &b, &x3)
+	m.Double(&x3, &b)
+	m.Add(&b, &x3, &b)
+	return b
+}
+
+// HasConstantTime returns true if the field of the G1 has the Montgomery
+// backend (fields.NewFq), needed by MulScalarCT and MultiExpCT
+func (g1 G1) HasConstantTime() bool {
+	return g1.F.Montgomery() != nil
+}
+
+// MulScalarCT multiplies the point by a secret scalar in constant time. The
+// result is the same point than MulScalar, but can have different projective
+// coordinates. It panics with ErrNoConstantTime if the G1 has no Montgomery
+// backend (see HasConstantTime)
+func (g1 G1) MulScalarCT(p [3]*big.Int, e *big.Int) [3]*big.Int {
+	m := g1.F.Montgomery()
+	if m == nil {
+		panic(ErrNoConstantTime)
+	}
+	b3 := g1MontB3(m, g1.G)
+	mp := g1ToMont(m, p)
+	pp := g1MontToProj(m, &mp)
+	r := g1ProjMulScalar(m, &b3, &pp, new(big.Int).Abs(e))
+	mr := g1ProjToMont(m, &r)
+	return g1FromMont(m, &mr)
+}
+
+// MultiExpCT returns Σ scalars[i] * points[i] for secret scalars, in constant
+// time as MulScalarCT. The products are accumulated with the complete
+// addition, without leaving the Montgomery projective form
+func (g1 G1) MultiExpCT(points [][3]*big.Int, scalars []*big.Int) [3]*big.Int {
+	m := g1.F.Montgomery()
+	if m == nil {
+		panic(ErrNoConstantTime)
+	}
+	b3 := g1MontB3(m, g1.G)
+	acc := g1Proj{fields.Element{}, m.One(), fields.Element{}}
+	for i := 0; i < len(points); i++ {
+		mp := g1ToMont(m, points[i])
+		pp := g1MontToProj(m, &mp)
+		r := g1ProjMulScalar(m, &b3, &pp, new(big.Int).Abs(scalars[i]))
+		acc = g1ProjAdd(m, &b3, &acc, &r)
+	}
+	mr := g1ProjToMont(m, &acc)
+	return g1FromMont(m, &mr)
+}
+
+// g2Proj is a G2 point in homogeneous projective coordinates, in Montgomery form
+type g2Proj [3]e2
+
+func (f *fq2Mont) selectE2(z, a, b *e2, cond uint) {
+	f.m.Select(&z[0], &a[0], &b[0], cond)
+	f.m.Select(&z[1], &a[1], &b[1], cond)
+}
+
+func (f *fq2Mont) one() e2 {
+	return e2{f.m.One(), fields.Element{}}
+}
+
+func (f *fq2Mont) g2MontToProj(p *g2Mont) g2Proj {
+	var r g2Proj
+	one := f.one()
+	f.mul(&r[0], &p[0], &p[2])
+	f.selectE2(&r[1], &one, &p[1], ctIsZero(&p[2][0])&ctIsZero(&p[2][1]))
+	f.square(&r[2], &p[2])
+	f.mul(&r[2], &r[2], &p[2])
+	return r
+}
+
+func (f *fq2Mont) g2ProjToMont(p *g2Proj) g2Mont {
+	var r g2Mont
+	f.mul(&r[0], &p[0], &p[2])
+	f.square(&r[1], &p[2])
+	f.mul(&r[1], &r[1], &p[1])
+	r[2] = p[2]
+	return r
+}
+
+// g2ProjAdd is the complete addition over the twist, as g1ProjAdd
+func (f *fq2Mont) g2ProjAdd(b3 *e2, p1, p2 *g2Proj) g2Proj {
+	var t0, t1, t2, t3, t4, x3, y3, z3 e2
+	f.mul(&t0, &p1[0], &p2[0])
+	f.mul(&t1, &p1[1], &p2[1])
+	f.mul(&t2, &p1[2], &p2[2])
+	f.add(&t3, &p1[0], &p1[1])
+	f.add(&t4, &p2[0], &p2[1])
+	f.mul(&t3, &t3, &t4)
+	f.add(&t4, &t0, &t1)
+	f.sub(&t3, &t3, &t4)
+	f.add(&t4, &p1[1], &p1[2])
+	f.add(&x3, &p2[1], &p2[2])
+	f.mul(&t4, &t4, &x3)
+	f.add(&x3, &t1, &t2)
+	f.sub(&t4, &t4, &x3)
+	f.add(&x3, &p1[0], &p1[2])
+	f.add(&y3, &p2[0], &p2[2])
+	f.mul(&x3, &x3, &y3)
+	f.add(&y3, &t0, &t2)
+	f.sub(&y3, &x3, &y3)
+	f.double(&x3, &t0)
+	f.add(&t0, &x3, &t0)
+	f.mul(&t2, b3, &t2)
+	f.add(&z3, &t1, &t2)
+	f.sub(&t1, &t1, &t2)
+	f.mul(&y3, b3, &y3)
+	f.mul(&x3, &t4, &y3)
+	f.mul(&t2, &t3, &t1)
+	f.sub(&x3, &t2, &x3)
+	f.mul(&y3, &y3, &t0)
+	f.mul(&t1, &t1, &z3)
+	f.add(&y3, &t1, &y3)
+	f.mul(&t0, &t0, &t3)
+	f.mul(&z3, &z3, &t4)
+	f.add(&z3, &z3, &t0)
+	return g2Proj{x3, y3, z3}
+}
+
+func (f *fq2Mont) g2ProjSwap(p1, p2 *g2Proj, cond uint) {
+	for i := 0; i < 3; i++ {
+		a, b := p1[i], p2[i]
+		f.selectE2(&p1[i], &b, &a, cond)
+		f.selectE2(&p2[i], &a, &b, cond)
+	}
+}
+
+func (f *fq2Mont) g2ProjMulScalar(b3 *e2, p *g2Proj, e *big.Int) g2Proj {
+	r0 := g2Proj{e2{}, f.one(), e2{}}
+	r1 := *p
+	for i := ctBits(e) - 1; i >= 0; i-- {
+		bit := e.Bit(i)
+		f.g2ProjSwap(&r0, &r1, bit)
+		r1 = f.g2ProjAdd(b3, &r0, &r1)
+		r0 = f.g2ProjAdd(b3, &r0, &r0)
+		f.g2ProjSwap(&r0, &r1, bit)
+	}
+	return r0
+}
+
+// g2B3 returns 3*b of the twist, with b = y^2 - x^3 computed from the generator
+func (f *fq2Mont) g2B3(g [3][2]*big.Int) e2 {
+	x := f.toMont(g[0])
+	y := f.toMont(g[1])
+	var b, x3 e2
+	f.square(&b, &y)
+	f.square(&x3, &x)
+	f.mul(&x3, &x3, &x)
+	f.sub(&b, &b, &x3)
+	f.double(&x3, &b)
+	f.add(&b, &x3, &b)
+	return b
+}
+
+// HasConstantTime returns true if the field of the G2 has the Montgomery
+// backend, needed by MulScalarCT and MultiExpCT
+func (g2 G2) HasConstantTime() bool {
+	return g2.montgomery() != nil
+}
+
+// MulScalarCT multiplies the point by a secret scalar in constant time, as
+// G1.MulScalarCT. It panics with ErrNoConstantTime if the G2 has no
+// Montgomery backend (see HasConstantTime)
+func (g2 G2) MulScalarCT(p [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
+	f := g2.montgomery()
+	if f == nil {
+		panic(ErrNoConstantTime)
+	}
+	b3 := f.g2B3(g2.G)
+	mp := f.g2ToMont(p)
+	pp := f.g2MontToProj(&mp)
+	r := f.g2ProjMulScalar(&b3, &pp, new(big.Int).Abs(e))
+	mr := f.g2ProjToMont(&r)
+	return f.g2FromMont(&mr)
+}
+
+// MultiExpCT returns Σ scalars[i] * points[i] for secret scalars, as G1.MultiExpCT
+func (g2 G2) MultiExpCT(points [][3][2]*big.Int, scalars []*big.Int) [3][2]*big.Int {
+	f := g2.montgomery()
+	if f == nil {
+		panic(ErrNoConstantTime)
+	}
+	b3 := f.g2B3(g2.G)
+	acc := g2Proj{e2{}, f.one(), e2{}}
+	for i := 0; i < len(points); i++ {
+		mp := f.g2ToMont(points[i])
+		pp := f.g2MontToProj(&mp)
+		r := f.g2ProjMulScalar(&b3, &pp, new(big.Int).Abs(scalars[i]))
+		acc = f.g2ProjAdd(&b3, &acc, &r)
+	}
+	mr := f.g2ProjToMont(&acc)
+	return f.g2FromMont(&mr)
+}
@@ -0,0 +1,81 @@
+package bn128
+
+import (
+	"errors"
+	"math/big"
+)
+
+// The binary encoding of the points is the one of the Ethereum precompiles
+// (EIP-196 and EIP-197): the affine coordinates x and y, each one with the
+// canonical fixed size encoding of its field. The point at infinity is
+// encoded as all zeros, which is not a point of the curve.
+
+// MarshalBinary returns the 64 bytes encoding of the point in affine coordinates
+func (g1 G1) MarshalBinary(p [3]*big.Int) []byte {
+	if g1.IsZero(p) {
+		return make([]byte, 2*g1.F.ByteLen())
+	}
+	a := g1.Affine(p)
+	return append(g1.F.MarshalBinary(a[0]), g1.F.MarshalBinary(a[1])...)
+}
+
+// UnmarshalBinary decodes a point encoded with MarshalBinary, returning it
+// with z = 1, or all zeros for the point at infinity. It rejects the non
+// canonical coordinates, and the points not on the curve with an InvalidPointError
+func (g1 G1) UnmarshalBinary(b []byte) ([3]*big.Int, error) {
+	n := g1.F.ByteLen()
+	if len(b) != 2*n {
+		return [3]*big.Int{}, errors.New("invalid G1 point length")
+	}
+	x, err := g1.F.UnmarshalBinary(b[:n])
+	if err != nil {
+		return [3]*big.Int{}, err
+	}
+	y, err := g1.F.UnmarshalBinary(b[n:])
+	if err != nil {
+		return [3]*big.Int{}, err
+	}
+	if g1.F.IsZero(x) && g1.F.IsZero(y) {
+		return [3]*big.Int{g1.F.Zero(), g1.F.Zero(), g1.F.Zero()}, nil
+	}
+	p := [3]*big.Int{x, y, g1.F.One()}
+	if err := g1.Validate(p); err != nil {
+		return [3]*big.Int{}, err
+	}
+	return p, nil
+}
+
+// MarshalBinary returns the 128 bytes encoding of the point in affine coordinates
+func (g2 G2) MarshalBinary(p [3][2]*big.Int) []byte {
+	if g2.IsZero(p) {
+		return make([]byte, 4*g2.F.F.ByteLen())
+	}
+	a := g2.Affine(p)
+	return append(g2.F.MarshalBinary(a[0]), g2.F.MarshalBinary(a[1])...)
+}
+
+// UnmarshalBinary decodes a point encoded with MarshalBinary, returning it
+// with z = 1, or G2.Zero() for the point at infinity. It rejects the non
+// canonical coordinates, and the points not in G2 with an InvalidPointError
+func (g2 G2) UnmarshalBinary(b []byte) ([3][2]*big.Int, error) {
+	n := 2 * g2.F.F.ByteLen()
+	if len(b) != 2*n {
+		return [3][2]*big.Int{}, errors.New("invalid G2 point length")
+	}
+	x, err := g2.F.UnmarshalBinary(b[:n])
+	if err != nil {
+		return [3][2]*big.Int{}, err
+	}
+	y, err := g2.F.UnmarshalBinary(b[n:])
+	if err != nil {
+		return [3][2]*big.Int{}, err
+	}
+	if g2.F.IsZero(x) && g2.F.IsZero(y) {
+		return g2.Zero(), nil
+	}
+	p := [3][2]*big.Int{x, y, g2.F.One()}
+	if err := g2.Validate(p); err != nil {
+		return [3][2]*big.Int{}, err
+	}
+	return p, nil
+}
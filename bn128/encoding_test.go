@@ -0,0 +1,69 @@
+package bn128
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestG1MarshalBinary(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(1234)))
+	b := bn128.G1.MarshalBinary(p)
+	assert.Equal(t, 64, len(b))
+	p2, err := bn128.G1.UnmarshalBinary(b)
+	assert.Nil(t, err)
+	assert.True(t, bn128.G1.Equal(p, p2))
+	assert.Equal(t, bn128.Fq1.One(), p2[2])
+
+	// the generator is (1, 2)
+	b = bn128.G1.MarshalBinary(bn128.G1.G)
+	assert.Equal(t, byte(1), b[31])
+	assert.Equal(t, byte(2), b[63])
+
+	zero := [3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.Zero(), bn128.Fq1.Zero()}
+	b = bn128.G1.MarshalBinary(zero)
+	assert.Equal(t, make([]byte, 64), b)
+	p2, err = bn128.G1.UnmarshalBinary(b)
+	assert.Nil(t, err)
+	assert.True(t, bn128.G1.IsZero(p2))
+
+	// non canonical coordinate
+	b = bn128.G1.MarshalBinary(p)
+	copy(b[:32], bn128.Q.Bytes())
+	_, err = bn128.G1.UnmarshalBinary(b)
+	assert.NotNil(t, err)
+	_, err = bn128.G1.UnmarshalBinary(b[:63])
+	assert.NotNil(t, err)
+}
+
+func TestG2MarshalBinary(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(1234)))
+	b := bn128.G2.MarshalBinary(p)
+	assert.Equal(t, 128, len(b))
+	p2, err := bn128.G2.UnmarshalBinary(b)
+	assert.Nil(t, err)
+	assert.True(t, bn128.G2.Equal(p, p2))
+
+	// the imaginary part goes first
+	b = bn128.G2.MarshalBinary(bn128.G2.G)
+	assert.Equal(t, bn128.Fq1.MarshalBinary(bn128.G2.G[0][1]), b[:32])
+	assert.Equal(t, bn128.Fq1.MarshalBinary(bn128.G2.G[0][0]), b[32:64])
+
+	b = bn128.G2.MarshalBinary(bn128.G2.Zero())
+	assert.Equal(t, make([]byte, 128), b)
+	p2, err = bn128.G2.UnmarshalBinary(b)
+	assert.Nil(t, err)
+	assert.Equal(t, bn128.G2.Zero(), p2)
+
+	b = bn128.G2.MarshalBinary(p)
+	copy(b[96:], new(big.Int).Add(bn128.Q, big.NewInt(int64(1))).Bytes())
+	_, err = bn128.G2.UnmarshalBinary(b)
+	assert.NotNil(t, err)
+}
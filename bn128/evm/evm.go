@@ -0,0 +1,128 @@
+// Package evm implements the BN128 precompiles of Ethereum, ecAdd (EIP-196,
+// address 0x06), ecMul (EIP-196, address 0x07) and ecPairing (EIP-197,
+// address 0x08), over their byte inputs and outputs, so the results can be
+// cross-checked with the ones of the EVM.
+//
+// As in the precompiles, the inputs of ecAdd and ecMul shorter than their
+// size are padded with zeros at the right, and the extra bytes are ignored.
+// The points are encoded as the affine coordinates in big-endian of 32 bytes
+// (the G2 coordinates as the imaginary part followed by the real one), with
+// the point at infinity as all zeros. The inputs with coordinates not lower
+// than the modulus, or with points not in G1 or G2, make the call fail, which
+// is returned as an error.
+package evm
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/arnaucube/go-snark/bn128"
+)
+
+const (
+	// AddInputLen is the length of the ecAdd input: two G1 points
+	AddInputLen = 128
+	// MulInputLen is the length of the ecMul input: a G1 point and a scalar
+	MulInputLen = 96
+	// PairingPairLen is the length of each pair of the ecPairing input: a G1
+	// point and a G2 point
+	PairingPairLen = 192
+
+	g1Len   = 64
+	wordLen = 32
+)
+
+// ErrPairingInputLen is returned by ECPairing when the input length is not a
+// multiple of PairingPairLen
+var ErrPairingInputLen = errors.New("bad elliptic curve pairing input size")
+
+var (
+	curveOnce sync.Once
+	curve     bn128.Bn128
+	curveErr  error
+)
+
+func getCurve() (*bn128.Bn128, error) {
+	curveOnce.Do(func() {
+		curve, curveErr = bn128.NewBn128()
+	})
+	return &curve, curveErr
+}
+
+// getData returns size bytes of the input starting at start, padded with zeros
+// at the right
+func getData(input []byte, start, size int) []byte {
+	b := make([]byte, size)
+	if start < len(input) {
+		copy(b, input[start:])
+	}
+	return b
+}
+
+// ECAdd returns the 64 bytes encoding of the sum of the two G1 points of the
+// input
+func ECAdd(input []byte) ([]byte, error) {
+	bn, err := getCurve()
+	if err != nil {
+		return nil, err
+	}
+	input = getData(input, 0, AddInputLen)
+	p1, err := bn.G1.UnmarshalBinary(input[:g1Len])
+	if err != nil {
+		return nil, err
+	}
+	p2, err := bn.G1.UnmarshalBinary(input[g1Len:])
+	if err != nil {
+		return nil, err
+	}
+	return bn.G1.MarshalBinary(bn.G1.Add(p1, p2)), nil
+}
+
+// ECMul returns the 64 bytes encoding of the G1 point of the input multiplied
+// by the scalar of the input (any 256 bits value, not only the ones lower than
+// the order of G1)
+func ECMul(input []byte) ([]byte, error) {
+	bn, err := getCurve()
+	if err != nil {
+		return nil, err
+	}
+	input = getData(input, 0, MulInputLen)
+	p, err := bn.G1.UnmarshalBinary(input[:g1Len])
+	if err != nil {
+		return nil, err
+	}
+	e := new(big.Int).SetBytes(input[g1Len:])
+	e.Mod(e, bn.R)
+	return bn.G1.MarshalBinary(bn.G1.MulScalar(p, e)), nil
+}
+
+// ECPairing returns the 32 bytes word 1 if the product of the pairings of the
+// pairs (G1 point, G2 point) of the input is one, and 0 otherwise. The empty
+// input returns 1
+func ECPairing(input []byte) ([]byte, error) {
+	if len(input)%PairingPairLen != 0 {
+		return nil, ErrPairingInputLen
+	}
+	bn, err := getCurve()
+	if err != nil {
+		return nil, err
+	}
+	n := len(input) / PairingPairLen
+	g1s := make([][3]*big.Int, n)
+	g2s := make([][3][2]*big.Int, n)
+	for i := 0; i < n; i++ {
+		pair := input[i*PairingPairLen : (i+1)*PairingPairLen]
+		if g1s[i], err = bn.G1.UnmarshalBinary(pair[:g1Len]); err != nil {
+			return nil, err
+		}
+		if g2s[i], err = bn.G2.UnmarshalBinary(pair[g1Len:]); err != nil {
+			return nil, err
+		}
+	}
+	res := make([]byte, wordLen)
+	if bn.PairingCheck(g1s, g2s) {
+		res[wordLen-1] = 1
+	}
+	return res, nil
+}
@@ -0,0 +1,113 @@
+package evm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/stretchr/testify/assert"
+)
+
+// testVector is a precompile test vector, in the format of the go-ethereum
+// precompile fixtures
+type testVector struct {
+	Name     string
+	Input    string
+	Expected string
+}
+
+// testVectors runs the vectors of testdata/<name>.json, taken from the
+// go-ethereum precompile fixtures, and of testdata/<name>_extra.json, built for
+// this package from the generators.
+//
+// The testdata/<name>.json files hold only a subset of the go-ethereum
+// fixtures. They are to be replaced by the complete files bn256Add.json,
+// bn256ScalarMul.json and bn256Pairing.json of core/vm/testdata/precompiles,
+// copied unchanged
+func testVectors(t *testing.T, name string, f func([]byte) ([]byte, error)) {
+	for _, file := range []string{name + ".json", name + "_extra.json"} {
+		b, err := ioutil.ReadFile("testdata/" + file)
+		assert.Nil(t, err)
+		var vectors []testVector
+		assert.Nil(t, json.Unmarshal(b, &vectors))
+		assert.NotEqual(t, 0, len(vectors), file)
+		for _, v := range vectors {
+			input, err := hex.DecodeString(v.Input)
+			assert.Nil(t, err)
+			out, err := f(input)
+			assert.Nil(t, err, v.Name)
+			assert.Equal(t, v.Expected, hex.EncodeToString(out), v.Name)
+		}
+	}
+}
+
+func TestECAdd(t *testing.T) {
+	testVectors(t, "ecadd", ECAdd)
+
+	// x = Q
+	bn, err := bn128.NewBn128()
+	assert.Nil(t, err)
+	input := make([]byte, AddInputLen)
+	bn.Q.FillBytes(input[:32])
+	_, err = ECAdd(input)
+	assert.NotNil(t, err)
+
+	// (1, 3) is not on the curve
+	input = make([]byte, AddInputLen)
+	input[31] = 1
+	input[63] = 3
+	_, err = ECAdd(input)
+	assert.True(t, errors.Is(err, bn128.ErrNotOnCurve))
+}
+
+func TestECMul(t *testing.T) {
+	testVectors(t, "ecmul", ECMul)
+
+	// the scalar is not reduced before the call, so R*G is the infinity
+	bn, err := bn128.NewBn128()
+	assert.Nil(t, err)
+	input := append(bn.G1.MarshalBinary(bn.G1.G), bn.R.FillBytes(make([]byte, 32))...)
+	out, err := ECMul(input)
+	assert.Nil(t, err)
+	assert.Equal(t, make([]byte, 64), out)
+
+	input = append(bn.G1.MarshalBinary(bn.G1.G), new(big.Int).Add(bn.R, big.NewInt(3)).FillBytes(make([]byte, 32))...)
+	out, err = ECMul(input)
+	assert.Nil(t, err)
+	assert.Equal(t, bn.G1.MarshalBinary(bn.G1.MulScalar(bn.G1.G, big.NewInt(3))), out)
+
+	input[63] = 3
+	_, err = ECMul(input)
+	assert.True(t, errors.Is(err, bn128.ErrNotOnCurve))
+}
+
+func TestECPairing(t *testing.T) {
+	testVectors(t, "ecpairing", ECPairing)
+
+	bn, err := bn128.NewBn128()
+	assert.Nil(t, err)
+	pair := append(bn.G1.MarshalBinary(bn.G1.G), bn.G2.MarshalBinary(bn.G2.G)...)
+	_, err = ECPairing(pair[:PairingPairLen-1])
+	assert.Equal(t, ErrPairingInputLen, err)
+
+	// a point of the twist that is not in G2
+	x := [2]*big.Int{big.NewInt(1), big.NewInt(0)}
+	var y [2]*big.Int
+	for {
+		y2 := bn.Fq2.Add(bn.Fq2.Mul(bn.Fq2.Square(x), x), bn.TwistCoefB)
+		var ok bool
+		if y, ok = bn.Fq2.Sqrt(y2); ok {
+			break
+		}
+		x[0] = new(big.Int).Add(x[0], big.NewInt(1))
+	}
+	q := [3][2]*big.Int{x, y, bn.Fq2.One()}
+	assert.True(t, bn.G2.IsOnCurve(q))
+	copy(pair[64:], append(bn.Fq2.MarshalBinary(x), bn.Fq2.MarshalBinary(y)...))
+	_, err = ECPairing(pair)
+	assert.True(t, errors.Is(err, bn128.ErrNotInSubgroup))
+}
@@ -0,0 +1,119 @@
+package bn128
+
+import (
+	"math/big"
+
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// The fixed-base tables hold the multiples d * 2^(w*i) * P of a point P, for
+// all the digits d of w bits and all the windows i of the scalar, so a
+// multiplication of P is just one addition per window, without doublings.
+// They are worth it when the same point is multiplied many times, as the
+// generators are in the trusted setup. The running time depends on the
+// scalar, as in MulScalar and MultiExp.
+
+const (
+	fixedBaseWindow = 6
+	// fixedBaseBits is the maximum bit length of the scalars, bigger
+	// scalars are multiplied with MulScalar
+	fixedBaseBits = 256
+)
+
+// FixedBaseTable is a fixed-base table of a G1 point, created with G1.NewFixedBaseTable
+type FixedBaseTable struct {
+	g1     G1
+	base   [3]*big.Int
+	m      *fields.Montgomery
+	points [][]g1Mont // points[i][d-1] = d * 2^(w*i) * base
+}
+
+// NewFixedBaseTable precomputes the fixed-base table of the point p. Without
+// a Montgomery backend the table is empty, and Mul uses MulScalar
+func (g1 G1) NewFixedBaseTable(p [3]*big.Int) *FixedBaseTable {
+	t := &FixedBaseTable{g1: g1, base: p, m: g1.F.Montgomery()}
+	if t.m == nil {
+		return t
+	}
+	base := g1ToMont(t.m, p)
+	nWindows := (fixedBaseBits + fixedBaseWindow - 1) / fixedBaseWindow
+	t.points = make([][]g1Mont, nWindows)
+	for i := 0; i < nWindows; i++ {
+		row := make([]g1Mont, 1<<fixedBaseWindow-1)
+		row[0] = base
+		for d := 1; d < len(row); d++ {
+			row[d] = g1MontAdd(t.m, &row[d-1], &base)
+		}
+		t.points[i] = row
+		for k := 0; k < fixedBaseWindow; k++ {
+			base = g1MontDouble(t.m, &base)
+		}
+	}
+	return t
+}
+
+// Mul returns e * p, where p is the point of the table. As in MulScalar, the sign of e is ignored
+func (t *FixedBaseTable) Mul(e *big.Int) [3]*big.Int {
+	abs := new(big.Int).Abs(e)
+	if t.m == nil || abs.BitLen() > fixedBaseBits {
+		return t.g1.MulScalar(t.base, abs)
+	}
+	var acc g1Mont
+	for i := 0; i < len(t.points); i++ {
+		d := scalarDigit(abs, i*fixedBaseWindow, fixedBaseWindow)
+		if d != 0 {
+			acc = g1MontAdd(t.m, &acc, &t.points[i][d-1])
+		}
+	}
+	return g1FromMont(t.m, &acc)
+}
+
+// G2FixedBaseTable is a fixed-base table of a G2 point, created with G2.NewFixedBaseTable
+type G2FixedBaseTable struct {
+	g2     G2
+	base   [3][2]*big.Int
+	f      *fq2Mont
+	points [][]g2Mont
+}
+
+// NewFixedBaseTable precomputes the fixed-base table of the point p, as G1.NewFixedBaseTable
+func (g2 G2) NewFixedBaseTable(p [3][2]*big.Int) *G2FixedBaseTable {
+	t := &G2FixedBaseTable{g2: g2, base: p, f: g2.montgomery()}
+	if t.f == nil {
+		return t
+	}
+	base := t.f.g2ToMont(p)
+	nWindows := (fixedBaseBits + fixedBaseWindow - 1) / fixedBaseWindow
+	t.points = make([][]g2Mont, nWindows)
+	for i := 0; i < nWindows; i++ {
+		row := make([]g2Mont, 1<<fixedBaseWindow-1)
+		row[0] = base
+		for d := 1; d < len(row); d++ {
+			row[d] = t.f.g2Add(&row[d-1], &base)
+		}
+		t.points[i] = row
+		for k := 0; k < fixedBaseWindow; k++ {
+			base = t.f.g2Double(&base)
+		}
+	}
+	return t
+}
+
+// Mul returns e * p, where p is the point of the table. As in MulScalar, the sign of e is ignored
+func (t *G2FixedBaseTable) Mul(e *big.Int) [3][2]*big.Int {
+	abs := new(big.Int).Abs(e)
+	if t.f == nil || abs.BitLen() > fixedBaseBits {
+		return t.g2.MulScalar(t.base, abs)
+	}
+	var acc g2Mont
+	for i := 0; i < len(t.points); i++ {
+		d := scalarDigit(abs, i*fixedBaseWindow, fixedBaseWindow)
+		if d != 0 {
+			acc = t.f.g2Add(&acc, &t.points[i][d-1])
+		}
+	}
+	if t.f.isZero(&acc[2]) {
+		return t.g2.Zero()
+	}
+	return t.f.g2FromMont(&acc)
+}
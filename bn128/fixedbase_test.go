@@ -0,0 +1,65 @@
+package bn128
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestG1FixedBaseTable(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	rnd := mrand.New(mrand.NewSource(1))
+
+	table := bn128.G1.NewFixedBaseTable(bn128.G1.G)
+	scalars := randScalars(rnd, bn128.R, 20)
+	scalars = append(scalars,
+		big.NewInt(int64(0)),
+		big.NewInt(int64(1)),
+		bn128.R,
+		new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(int64(1)), 256), big.NewInt(int64(1))),
+		new(big.Int).Lsh(big.NewInt(int64(1)), 300),
+	)
+	for _, e := range scalars {
+		assert.True(t, bn128.G1.Equal(bn128.G1.MulScalar(bn128.G1.G, e), table.Mul(e)))
+	}
+
+	g1Big := NewG1(fields.Fq{Q: bn128.Q}, bn128.Gg1)
+	tableBig := g1Big.NewFixedBaseTable(g1Big.G)
+	assert.True(t, g1Big.Equal(g1Big.MulScalar(g1Big.G, scalars[0]), tableBig.Mul(scalars[0])))
+}
+
+func TestG2FixedBaseTable(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	rnd := mrand.New(mrand.NewSource(2))
+
+	table := bn128.G2.NewFixedBaseTable(bn128.G2.G)
+	scalars := randScalars(rnd, bn128.R, 10)
+	scalars = append(scalars, big.NewInt(int64(0)), big.NewInt(int64(1)), bn128.R)
+	for _, e := range scalars {
+		assert.True(t, bn128.G2.Equal(bn128.G2.MulScalar(bn128.G2.G, e), table.Mul(e)))
+	}
+	assert.True(t, bn128.G2.IsZero(table.Mul(bn128.R)))
+}
+
+func BenchmarkG1FixedBaseTable(b *testing.B) {
+	bn128, err := NewBn128()
+	assert.Nil(b, err)
+	e := new(big.Int).Rand(mrand.New(mrand.NewSource(1)), bn128.R)
+
+	b.Run("MulScalar", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bn128.G1.MulScalar(bn128.G1.G, e)
+		}
+	})
+	table := bn128.G1.NewFixedBaseTable(bn128.G1.G)
+	b.Run("FixedBaseTable", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			table.Mul(e)
+		}
+	})
+}
@@ -9,6 +9,12 @@ import (
 type G1 struct {
 	F fields.Fq
 	G [3]*big.Int
+	// R is the order of the subgroup generated by G, used by IsInSubgroup. It
+	// is only needed for the curves with a cofactor in G1 (not for the BN128)
+	R *big.Int
+
+	glv   *g1GLV  // endomorphism used by MulScalar, set by NewBn128
+	svdwC *g1SvdW // constants of the map of HashToG1, set by NewBn128
 }
 
 func NewG1(f fields.Fq, g [2]*big.Int) G1 {
@@ -41,6 +47,12 @@ func (g1 G1) Add(p1, p2 [3]*big.Int) [3]*big.Int {
 	if g1.IsZero(p2) {
 		return p1
 	}
+	if m := g1.F.Montgomery(); m != nil {
+		mp1 := g1ToMont(m, p1)
+		mp2 := g1ToMont(m, p2)
+		r := g1MontAdd(m, &mp1, &mp2)
+		return g1FromMont(m, &r)
+	}
 
 	x1 := p1[0]
 	y1 := p1[1]
@@ -62,6 +74,10 @@ func (g1 G1) Add(p1, p2 [3]*big.Int) [3]*big.Int {
 	s2 := g1.F.Mul(y2, t1)
 
 	h := g1.F.Sub(u2, u1)
+	if g1.F.IsZero(h) && g1.F.Equal(s1, s2) {
+		// p1 == p2, the addition formula does not work for doubling
+		return g1.Double(p1)
+	}
 	t2 := g1.F.Add(h, h)
 	i := g1.F.Square(t2)
 	j := g1.F.Mul(h, i)
@@ -107,6 +123,11 @@ func (g1 G1) Double(p [3]*big.Int) [3]*big.Int {
 	if g1.IsZero(p) {
 		return p
 	}
+	if m := g1.F.Montgomery(); m != nil {
+		mp := g1ToMont(m, p)
+		r := g1MontDouble(m, &mp)
+		return g1FromMont(m, &r)
+	}
 
 	a := g1.F.Square(p[0])
 	b := g1.F.Square(p[1])
@@ -137,9 +158,30 @@ func (g1 G1) Double(p [3]*big.Int) [3]*big.Int {
 	return [3]*big.Int{x3, y3, z3}
 }
 
+// MulScalar multiplies the point by the scalar. With the GLV endomorphism
+// (set by NewBn128) it uses it, otherwise it does double-and-add. The result
+// is in affine coordinates (z = 1, or all zeros for the point at infinity),
+// so it is the same point with the same coordinates in both cases
 func (g1 G1) MulScalar(p [3]*big.Int, e *big.Int) [3]*big.Int {
+	if m := g1.F.Montgomery(); m != nil && g1.glv != nil {
+		mp := g1ToMont(m, p)
+		r := g1.glv.mulScalar(m, &mp, new(big.Int).Abs(e))
+		return g1.normalize(g1FromMont(m, &r))
+	}
+	return g1.normalize(g1.mulScalar(p, e))
+}
+
+// mulScalar multiplies the point by the scalar with double-and-add, without
+// the endomorphism, and returns it in Jacobian coordinates
+func (g1 G1) mulScalar(p [3]*big.Int, e *big.Int) [3]*big.Int {
 	// https://en.wikipedia.org/wiki/Elliptic_curve_point_multiplication#Double-and-add
-	// for more possible implementations see g2.go file, at the function g2.MulScalar()
+	// for more possible implementations see g2.go file, at the function g2.mulScalar()
+
+	if m := g1.F.Montgomery(); m != nil {
+		mp := g1ToMont(m, p)
+		r := g1MontMulScalar(m, &mp, new(big.Int).Abs(e))
+		return g1FromMont(m, &r)
+	}
 
 	q := [3]*big.Int{g1.F.Zero(), g1.F.Zero(), g1.F.Zero()}
 	d := g1.F.Copy(e)
@@ -154,6 +196,15 @@ func (g1 G1) MulScalar(p [3]*big.Int, e *big.Int) [3]*big.Int {
 	return q
 }
 
+// normalize returns the point with z = 1, or all zeros for the point at infinity
+func (g1 G1) normalize(p [3]*big.Int) [3]*big.Int {
+	if g1.IsZero(p) {
+		return [3]*big.Int{g1.F.Zero(), g1.F.Zero(), g1.F.Zero()}
+	}
+	a := g1.Affine(p)
+	return [3]*big.Int{a[0], a[1], g1.F.One()}
+}
+
 func (g1 G1) Affine(p [3]*big.Int) [2]*big.Int {
 	if g1.IsZero(p) {
 		return g1.Zero()
@@ -169,6 +220,33 @@ func (g1 G1) Affine(p [3]*big.Int) [2]*big.Int {
 	return [2]*big.Int{x, y}
 }
 
+// BatchAffine converts the given points to affine coordinates doing a single
+// field inversion for all of them. The points are returned in the same
+// [3]*big.Int layout, with z = 1 (or all zeros for the points at infinity)
+func (g1 G1) BatchAffine(ps [][3]*big.Int) [][3]*big.Int {
+	zs := make([]*big.Int, len(ps))
+	for i := 0; i < len(ps); i++ {
+		zs[i] = ps[i][2]
+	}
+	zinvs := g1.F.BatchInverse(zs)
+
+	res := make([][3]*big.Int, len(ps))
+	for i := 0; i < len(ps); i++ {
+		if g1.IsZero(ps[i]) {
+			res[i] = [3]*big.Int{g1.F.Zero(), g1.F.Zero(), g1.F.Zero()}
+			continue
+		}
+		zinv2 := g1.F.Square(zinvs[i])
+		zinv3 := g1.F.Mul(zinv2, zinvs[i])
+		res[i] = [3]*big.Int{
+			g1.F.Mul(ps[i][0], zinv2),
+			g1.F.Mul(ps[i][1], zinv3),
+			g1.F.One(),
+		}
+	}
+	return res
+}
+
 func (g1 G1) Equal(p1, p2 [3]*big.Int) bool {
 	if g1.IsZero(p1) {
 		return g1.IsZero(p2)
@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/arnaucube/go-snark/fields"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,3 +30,80 @@ func TestG1(t *testing.T) {
 	assert.Equal(t, "2f978c0ab89ebaa576866706b14787f360c4d6c3869efe5a72f7c3651a72ff00", hex.EncodeToString(a[0].Bytes()))
 	assert.Equal(t, "12e4ba7f0edca8b4fa668fe153aebd908d322dc26ad964d4cd314795844b62b2", hex.EncodeToString(a[1].Bytes()))
 }
+
+func TestG1MontgomeryMatchesBigInt(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	g1Big := NewG1(fields.Fq{Q: bn128.Q}, bn128.Gg1)
+
+	for i := 0; i < 10; i++ {
+		k1, err := bn128.Fq1.Rand()
+		assert.Nil(t, err)
+		k2, err := bn128.Fq1.Rand()
+		assert.Nil(t, err)
+
+		p1 := bn128.G1.MulScalar(bn128.G1.G, k1)
+		assert.Equal(t, g1Big.MulScalar(g1Big.G, k1), p1)
+		p2 := bn128.G1.MulScalar(bn128.G1.G, k2)
+		assert.Equal(t, g1Big.Add(p1, p2), bn128.G1.Add(p1, p2))
+		assert.Equal(t, g1Big.Double(p1), bn128.G1.Double(p1))
+	}
+}
+
+func TestG1BatchAffine(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	var ps [][3]*big.Int
+	for i := 1; i < 6; i++ {
+		ps = append(ps, bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(i*1000+7))))
+	}
+	ps = append(ps, bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(0))))
+	affs := bn128.G1.BatchAffine(ps)
+	assert.Equal(t, len(ps), len(affs))
+	for i := 0; i < len(ps)-1; i++ {
+		aff := bn128.G1.Affine(ps[i])
+		assert.Equal(t, [3]*big.Int{aff[0], aff[1], bn128.Fq1.One()}, affs[i])
+		assert.True(t, bn128.G1.Equal(ps[i], affs[i]))
+	}
+	assert.True(t, bn128.G1.IsZero(affs[len(affs)-1]))
+}
+
+func TestG1MulScalarCT(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	// a point with z != 1
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(12345)))
+	for i := 0; i < 10; i++ {
+		e, err := bn128.Fq1.Rand()
+		assert.Nil(t, err)
+		assert.True(t, bn128.G1.Equal(bn128.G1.MulScalar(p, e), bn128.G1.MulScalarCT(p, e)))
+	}
+	// scalars over 256 bits are not truncated
+	e := new(big.Int).Lsh(big.NewInt(int64(3)), 300)
+	assert.True(t, bn128.G1.Equal(bn128.G1.MulScalar(p, e), bn128.G1.MulScalarCT(p, e)))
+
+	assert.True(t, bn128.G1.IsZero(bn128.G1.MulScalarCT(p, big.NewInt(int64(0)))))
+	assert.True(t, bn128.G1.IsZero(bn128.G1.MulScalarCT(p, bn128.R)))
+	zero := [3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.Zero(), bn128.Fq1.Zero()}
+	assert.True(t, bn128.G1.IsZero(bn128.G1.MulScalarCT(zero, big.NewInt(int64(5)))))
+
+	// over big.Int there is no constant-time arithmetic
+	assert.True(t, bn128.G1.HasConstantTime())
+	g1Big := NewG1(fields.Fq{Q: bn128.Q}, bn128.Gg1)
+	assert.False(t, g1Big.HasConstantTime())
+	assert.PanicsWithValue(t, ErrNoConstantTime, func() { g1Big.MulScalarCT(p, e) })
+	assert.PanicsWithValue(t, ErrNoConstantTime, func() { g1Big.MultiExpCT([][3]*big.Int{p}, []*big.Int{e}) })
+}
+
+func TestG1AddEqualPoints(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	g1Big := NewG1(fields.Fq{Q: bn128.Q}, bn128.Gg1)
+
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(5)))
+	assert.True(t, bn128.G1.Equal(bn128.G1.Double(p), bn128.G1.Add(p, p)))
+	assert.True(t, g1Big.Equal(g1Big.Double(p), g1Big.Add(p, p)))
+	assert.True(t, bn128.G1.IsZero(bn128.G1.Add(p, bn128.G1.Neg(p))))
+}
@@ -9,11 +9,17 @@ import (
 type G2 struct {
 	F fields.Fq2
 	G [3][2]*big.Int
+	R *big.Int // order of the subgroup generated by G, used by IsInSubgroup
+
+	gls   *g2GLS   // endomorphism used by MulScalar and IsInSubgroup, set by NewBn128
+	mont  *fq2Mont // Montgomery Fq2 context, set by NewG2
+	svdwC *g2SvdW  // constants of the map of HashToG2, set by NewBn128
 }
 
 func NewG2(f fields.Fq2, g [2][2]*big.Int) G2 {
 	var g2 G2
 	g2.F = f
+	g2.mont = newFq2Mont(f)
 	g2.G = [3][2]*big.Int{
 		g[0],
 		g[1],
@@ -22,6 +28,15 @@ func NewG2(f fields.Fq2, g [2][2]*big.Int) G2 {
 	return g2
 }
 
+// montgomery returns the Montgomery Fq2 context of the G2, or nil if its
+// field runs over big.Int
+func (g2 G2) montgomery() *fq2Mont {
+	if g2.mont != nil {
+		return g2.mont
+	}
+	return newFq2Mont(g2.F)
+}
+
 func (g2 G2) Zero() [3][2]*big.Int {
 	return [3][2]*big.Int{g2.F.Zero(), g2.F.One(), g2.F.Zero()}
 }
@@ -41,6 +56,12 @@ func (g2 G2) Add(p1, p2 [3][2]*big.Int) [3][2]*big.Int {
 	if g2.IsZero(p2) {
 		return p1
 	}
+	if f := g2.montgomery(); f != nil {
+		mp1 := f.g2ToMont(p1)
+		mp2 := f.g2ToMont(p2)
+		r := f.g2Add(&mp1, &mp2)
+		return f.g2FromMont(&r)
+	}
 
 	x1 := p1[0]
 	y1 := p1[1]
@@ -62,6 +83,10 @@ func (g2 G2) Add(p1, p2 [3][2]*big.Int) [3][2]*big.Int {
 	s2 := g2.F.Mul(y2, t1)
 
 	h := g2.F.Sub(u2, u1)
+	if g2.F.IsZero(h) && g2.F.Equal(s1, s2) {
+		// p1 == p2, the addition formula does not work for doubling
+		return g2.Double(p1)
+	}
 	t2 := g2.F.Add(h, h)
 	i := g2.F.Square(t2)
 	j := g2.F.Mul(h, i)
@@ -109,6 +134,11 @@ func (g2 G2) Double(p [3][2]*big.Int) [3][2]*big.Int {
 	if g2.IsZero(p) {
 		return p
 	}
+	if f := g2.montgomery(); f != nil {
+		mp := f.g2ToMont(p)
+		r := f.g2Double(&mp)
+		return f.g2FromMont(&r)
+	}
 
 	a := g2.F.Square(p[0])
 	b := g2.F.Square(p[1])
@@ -139,9 +169,33 @@ func (g2 G2) Double(p [3][2]*big.Int) [3][2]*big.Int {
 	return [3][2]*big.Int{x3, y3, z3}
 }
 
+// MulScalar multiplies a point of G2 by the scalar. With the GLS
+// endomorphism ψ (set by NewBn128) it uses it, otherwise it does
+// double-and-add. ψ(p) = q*p only holds for the points of G2, so p must be in
+// it (a point decoded with UnmarshalBinary, or a multiple of the generator).
+// The result is in affine coordinates (z = 1, or Zero for the point at
+// infinity), so it is the same point with the same coordinates in both cases
 func (g2 G2) MulScalar(p [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
+	if f := g2.montgomery(); f != nil && g2.gls != nil {
+		mp := f.g2ToMont(p)
+		r := g2.gls.mulScalar(f, &mp, new(big.Int).Abs(e))
+		return g2.Affine(f.g2FromMont(&r))
+	}
+	return g2.Affine(g2.mulScalar(p, e))
+}
+
+// mulScalar multiplies any point of the twist by the scalar with
+// double-and-add, without the endomorphism, and returns it in Jacobian
+// coordinates
+func (g2 G2) mulScalar(p [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
 	// https://en.wikipedia.org/wiki/Elliptic_curve_point_multiplication#Double-and-add
 
+	if f := g2.montgomery(); f != nil {
+		mp := f.g2ToMont(p)
+		r := f.g2MulScalar(&mp, new(big.Int).Abs(e))
+		return f.g2FromMont(&r)
+	}
+
 	q := [3][2]*big.Int{g2.F.Zero(), g2.F.Zero(), g2.F.Zero()}
 	d := g2.F.F.Copy(e) // d := e
 	r := p
@@ -199,6 +253,32 @@ func (g2 G2) Affine(p [3][2]*big.Int) [3][2]*big.Int {
 	}
 }
 
+// BatchAffine converts the given points to affine coordinates (as G2.Affine
+// does) doing a single field inversion for all of them
+func (g2 G2) BatchAffine(ps [][3][2]*big.Int) [][3][2]*big.Int {
+	zs := make([][2]*big.Int, len(ps))
+	for i := 0; i < len(ps); i++ {
+		zs[i] = ps[i][2]
+	}
+	zinvs := g2.F.BatchInverse(zs)
+
+	res := make([][3][2]*big.Int, len(ps))
+	for i := 0; i < len(ps); i++ {
+		if g2.IsZero(ps[i]) {
+			res[i] = g2.Zero()
+			continue
+		}
+		zinv2 := g2.F.Square(zinvs[i])
+		zinv3 := g2.F.Mul(zinv2, zinvs[i])
+		res[i] = [3][2]*big.Int{
+			g2.F.Affine(g2.F.Mul(ps[i][0], zinv2)),
+			g2.F.Affine(g2.F.Mul(ps[i][1], zinv3)),
+			g2.F.One(),
+		}
+	}
+	return res
+}
+
 func (g2 G2) Equal(p1, p2 [3][2]*big.Int) bool {
 	if g2.IsZero(p1) {
 		return g2.IsZero(p2)
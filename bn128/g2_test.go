@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/arnaucube/go-snark/fields"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,3 +23,69 @@ func TestG2(t *testing.T) {
 	grsum2 := bn128.G2.Affine(bn128.G2.MulScalar(bn128.G2.G, r1r2))
 	assert.True(t, bn128.G2.Equal(grsum1, grsum2))
 }
+
+func TestG2MontgomeryMatchesBigInt(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	g2Big := NewG2(fields.NewFq2(fields.Fq{Q: bn128.Q}, bn128.NonResidueFq2), bn128.Gg2)
+
+	for i := 0; i < 5; i++ {
+		k1, err := bn128.Fq1.Rand()
+		assert.Nil(t, err)
+		k2, err := bn128.Fq1.Rand()
+		assert.Nil(t, err)
+
+		p1 := bn128.G2.MulScalar(bn128.G2.G, k1)
+		assert.Equal(t, g2Big.MulScalar(g2Big.G, k1), p1)
+		p2 := bn128.G2.MulScalar(bn128.G2.G, k2)
+		assert.Equal(t, g2Big.Add(p1, p2), bn128.G2.Add(p1, p2))
+		assert.Equal(t, g2Big.Double(p1), bn128.G2.Double(p1))
+	}
+}
+
+func TestG2BatchAffine(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	var ps [][3][2]*big.Int
+	for i := 1; i < 6; i++ {
+		ps = append(ps, bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(i*1000+7))))
+	}
+	ps = append(ps, bn128.G2.Zero())
+	affs := bn128.G2.BatchAffine(ps)
+	assert.Equal(t, len(ps), len(affs))
+	for i := 0; i < len(ps); i++ {
+		assert.Equal(t, bn128.G2.Affine(ps[i]), affs[i])
+	}
+}
+
+func TestG2MulScalarCT(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(12345)))
+	for i := 0; i < 5; i++ {
+		e, err := bn128.Fq1.Rand()
+		assert.Nil(t, err)
+		assert.True(t, bn128.G2.Equal(bn128.G2.MulScalar(p, e), bn128.G2.MulScalarCT(p, e)))
+	}
+	assert.True(t, bn128.G2.IsZero(bn128.G2.MulScalarCT(p, big.NewInt(int64(0)))))
+	assert.True(t, bn128.G2.IsZero(bn128.G2.MulScalarCT(p, bn128.R)))
+	assert.True(t, bn128.G2.IsZero(bn128.G2.MulScalarCT(bn128.G2.Zero(), big.NewInt(int64(5)))))
+
+	assert.True(t, bn128.G2.HasConstantTime())
+	g2Big := NewG2(fields.NewFq2(fields.Fq{Q: bn128.Q}, bn128.NonResidueFq2), bn128.Gg2)
+	assert.False(t, g2Big.HasConstantTime())
+	assert.PanicsWithValue(t, ErrNoConstantTime, func() { g2Big.MulScalarCT(p, big.NewInt(int64(5))) })
+}
+
+func TestG2AddEqualPoints(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	g2Big := NewG2(fields.NewFq2(fields.Fq{Q: bn128.Q}, bn128.NonResidueFq2), bn128.Gg2)
+
+	q := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(5)))
+	assert.True(t, bn128.G2.Equal(bn128.G2.Double(q), bn128.G2.Add(q, q)))
+	assert.True(t, g2Big.Equal(g2Big.Double(q), g2Big.Add(q, q)))
+	assert.True(t, bn128.G2.IsZero(bn128.G2.Add(q, bn128.G2.Neg(q))))
+}
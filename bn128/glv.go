@@ -0,0 +1,419 @@
+package bn128
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// The functions in this file speed up MulScalar with the endomorphisms of the
+// BN128 groups (https://www.iacr.org/archive/crypto2001/21390189.pdf and
+// https://eprint.iacr.org/2008/194.pdf):
+//	G1: φ(x, y) = (β*x, y), with β a cube root of unity of Fq, is φ(P) = λ*P
+//	G2: ψ (untwist, Frobenius, twist) is ψ(P) = q*P
+// The scalar k is decomposed as k = Σ k_i * λ^i mod R, with k_i of 128 bits
+// for G1 (2 components) and 64 bits for G2 (4 components), and Σ k_i*endo^i(P)
+// is computed interleaving the wNAF expansions of the k_i, so there are 2 and
+// 4 times less doublings. The decompositions are only valid for the points of
+// the subgroup of order R, which in G1 are all the points of the curve.
+
+const (
+	g1WNAFWindow = 5
+	g2WNAFWindow = 4
+)
+
+// glvLattice is a reduced basis of (a sublattice of) the lattice
+// {v : Σ v[i] * λ^i = 0 mod R}, used to decompose the scalars
+type glvLattice struct {
+	lambda *big.Int
+	basis  [][]*big.Int
+	adj    []*big.Int // first row of the adjugate matrix of the basis
+	det    *big.Int
+}
+
+// minor returns the matrix without the row i and the column j
+func minor(a [][]*big.Int, i, j int) [][]*big.Int {
+	var res [][]*big.Int
+	for r := 0; r < len(a); r++ {
+		if r == i {
+			continue
+		}
+		var row []*big.Int
+		for c := 0; c < len(a); c++ {
+			if c != j {
+				row = append(row, a[r][c])
+			}
+		}
+		res = append(res, row)
+	}
+	return res
+}
+
+// determinant computes the determinant by cofactor expansion, for the small lattice bases
+func determinant(a [][]*big.Int) *big.Int {
+	if len(a) == 1 {
+		return new(big.Int).Set(a[0][0])
+	}
+	det := new(big.Int)
+	for j := 0; j < len(a); j++ {
+		t := new(big.Int).Mul(a[0][j], determinant(minor(a, 0, j)))
+		if j%2 == 1 {
+			t.Neg(t)
+		}
+		det.Add(det, t)
+	}
+	return det
+}
+
+// newGLVLattice checks that the basis vectors are in the lattice of λ and
+// are linearly independent. They can generate a sublattice (the determinant
+// is a multiple of R), the decomposition is still valid
+func newGLVLattice(basis [][]*big.Int, lambda, r *big.Int) (*glvLattice, error) {
+	for _, v := range basis {
+		acc := new(big.Int)
+		pow := big.NewInt(int64(1))
+		for i := 0; i < len(v); i++ {
+			acc.Add(acc, new(big.Int).Mul(v[i], pow))
+			pow = new(big.Int).Mod(new(big.Int).Mul(pow, lambda), r)
+		}
+		if acc.Mod(acc, r).Sign() != 0 {
+			return nil, errors.New("glv basis vector not in the lattice")
+		}
+	}
+	l := &glvLattice{lambda: lambda, basis: basis, det: determinant(basis)}
+	if l.det.Sign() == 0 {
+		return nil, errors.New("glv basis vectors are not independent")
+	}
+	for j := 0; j < len(basis); j++ {
+		// adj[0][j] is the cofactor (j, 0)
+		c := determinant(minor(basis, j, 0))
+		if j%2 == 1 {
+			c.Neg(c)
+		}
+		l.adj = append(l.adj, c)
+	}
+	return l, nil
+}
+
+// roundDiv returns a/b rounded to the nearest integer
+func roundDiv(a, b *big.Int) *big.Int {
+	n := new(big.Int).Lsh(a, 1)
+	d := new(big.Int).Lsh(b, 1)
+	if d.Sign() < 0 {
+		n.Neg(n)
+		d.Neg(d)
+	}
+	// floor((2a + |b|) / 2|b|)
+	n.Add(n, new(big.Int).Abs(b))
+	return n.Div(n, d)
+}
+
+// decompose returns the small k_i with k = Σ k_i * λ^i mod R, subtracting
+// from (k, 0, ..., 0) the closest vector of the lattice (Babai's rounding)
+func (l *glvLattice) decompose(k *big.Int) []*big.Int {
+	ks := make([]*big.Int, len(l.basis))
+	for i := 0; i < len(ks); i++ {
+		ks[i] = new(big.Int)
+	}
+	ks[0].Set(k)
+	for j := 0; j < len(l.basis); j++ {
+		c := roundDiv(new(big.Int).Mul(k, l.adj[j]), l.det)
+		for i := 0; i < len(ks); i++ {
+			ks[i].Sub(ks[i], new(big.Int).Mul(c, l.basis[j][i]))
+		}
+	}
+	return ks
+}
+
+// shortBasis2 returns a reduced basis of the 2 dimensional lattice of λ,
+// with the extended Euclidean algorithm over (R, λ)
+func shortBasis2(lambda, r *big.Int) [][]*big.Int {
+	sqrtR := new(big.Int).Sqrt(r)
+	// remainders r_i and coefficients t_i, with r_i = s_i*R + t_i*λ
+	rs := []*big.Int{new(big.Int).Set(r), new(big.Int).Set(lambda)}
+	ts := []*big.Int{big.NewInt(int64(0)), big.NewInt(int64(1))}
+	for rs[len(rs)-1].Sign() != 0 {
+		n := len(rs)
+		q, rem := new(big.Int).QuoRem(rs[n-2], rs[n-1], new(big.Int))
+		rs = append(rs, rem)
+		ts = append(ts, new(big.Int).Sub(ts[n-2], new(big.Int).Mul(q, ts[n-1])))
+	}
+	m := 0
+	for i := 0; i < len(rs); i++ {
+		if rs[i].Cmp(sqrtR) >= 0 {
+			m = i
+		}
+	}
+	vec := func(i int) []*big.Int {
+		return []*big.Int{rs[i], new(big.Int).Neg(ts[i])}
+	}
+	norm := func(v []*big.Int) *big.Int {
+		return new(big.Int).Add(new(big.Int).Mul(v[0], v[0]), new(big.Int).Mul(v[1], v[1]))
+	}
+	v1 := vec(m + 1)
+	v2 := vec(m)
+	if m+2 < len(rs) && norm(vec(m+2)).Cmp(norm(v2)) < 0 {
+		v2 = vec(m + 2)
+	}
+	return [][]*big.Int{v1, v2}
+}
+
+// wnaf returns the width w NAF expansion of k >= 0, from the least
+// significant digit. The non zero digits are odd and in (-2^(w-1), 2^(w-1))
+func wnaf(k *big.Int, w uint) []int {
+	var digits []int
+	k = new(big.Int).Set(k)
+	mod := 1 << w
+	for k.Sign() > 0 {
+		d := 0
+		if k.Bit(0) == 1 {
+			d = int(uint(k.Bits()[0]) & uint(mod-1))
+			if d >= mod/2 {
+				d -= mod
+			}
+			k.Sub(k, big.NewInt(int64(d)))
+		}
+		digits = append(digits, d)
+		k.Rsh(k, 1)
+	}
+	return digits
+}
+
+// signedWNAFs returns the wNAF expansions of the k_i, with the sign of k_i applied to the digits
+func signedWNAFs(ks []*big.Int, w uint) ([][]int, int) {
+	nafs := make([][]int, len(ks))
+	maxLen := 0
+	for i := 0; i < len(ks); i++ {
+		nafs[i] = wnaf(new(big.Int).Abs(ks[i]), w)
+		if ks[i].Sign() < 0 {
+			for j := 0; j < len(nafs[i]); j++ {
+				nafs[i][j] = -nafs[i][j]
+			}
+		}
+		if len(nafs[i]) > maxLen {
+			maxLen = len(nafs[i])
+		}
+	}
+	return nafs, maxLen
+}
+
+// g1GLV holds the constants of the G1 endomorphism φ(x, y) = (β*x, y) = λ*(x, y)
+type g1GLV struct {
+	r       *big.Int
+	beta    fields.Element // in Montgomery form
+	lattice *glvLattice
+}
+
+// newG1GLV finds the cube root of unity β of Fq such that φ(G) = λ*G, for
+// the cube root of unity λ of the scalar field that has 128 bits
+func newG1GLV(g1 G1, r *big.Int) (*g1GLV, error) {
+	m := g1.F.Montgomery()
+	if m == nil {
+		return nil, errors.New("glv needs the montgomery backend")
+	}
+	third := func(n *big.Int) *big.Int {
+		return new(big.Int).Div(new(big.Int).Sub(n, big.NewInt(int64(1))), big.NewInt(int64(3)))
+	}
+	var lambda *big.Int
+	for g := int64(2); lambda == nil || lambda.Cmp(big.NewInt(int64(1))) == 0; g++ {
+		lambda = new(big.Int).Exp(big.NewInt(g), third(r), r)
+	}
+	if lambda.BitLen() > r.BitLen()/2+2 {
+		lambda.Mod(new(big.Int).Mul(lambda, lambda), r)
+	}
+	var beta *big.Int
+	for g := int64(2); beta == nil || beta.Cmp(big.NewInt(int64(1))) == 0; g++ {
+		beta = g1.F.Exp(big.NewInt(g), third(g1.F.Q))
+	}
+	lambdaG := g1.MulScalar(g1.G, lambda)
+	if !g1.Equal(lambdaG, [3]*big.Int{g1.F.Mul(beta, g1.G[0]), g1.G[1], g1.G[2]}) {
+		beta = g1.F.Square(beta)
+		if !g1.Equal(lambdaG, [3]*big.Int{g1.F.Mul(beta, g1.G[0]), g1.G[1], g1.G[2]}) {
+			return nil, errors.New("no endomorphism for the G1 generator")
+		}
+	}
+	lattice, err := newGLVLattice(shortBasis2(lambda, r), lambda, r)
+	if err != nil {
+		return nil, err
+	}
+	return &g1GLV{r: r, beta: m.ToMont(beta), lattice: lattice}, nil
+}
+
+func g1MontNeg(m *fields.Montgomery, p *g1Mont) g1Mont {
+	r := *p
+	m.Neg(&r[1], &p[1])
+	return r
+}
+
+// g1MontOddMultiples returns P, 3P, 5P, ..., (2n-1)P
+func g1MontOddMultiples(m *fields.Montgomery, p *g1Mont, n int) []g1Mont {
+	table := make([]g1Mont, n)
+	table[0] = *p
+	p2 := g1MontDouble(m, p)
+	for i := 1; i < n; i++ {
+		table[i] = g1MontAdd(m, &table[i-1], &p2)
+	}
+	return table
+}
+
+func (glv *g1GLV) mulScalar(m *fields.Montgomery, p *g1Mont, e *big.Int) g1Mont {
+	ks := glv.lattice.decompose(new(big.Int).Mod(e, glv.r))
+	nafs, maxLen := signedWNAFs(ks, g1WNAFWindow)
+
+	tables := make([][]g1Mont, 2)
+	tables[0] = g1MontOddMultiples(m, p, 1<<(g1WNAFWindow-2))
+	tables[1] = make([]g1Mont, len(tables[0]))
+	for j := 0; j < len(tables[0]); j++ {
+		tables[1][j] = tables[0][j]
+		m.Mul(&tables[1][j][0], &tables[0][j][0], &glv.beta)
+	}
+
+	var q g1Mont
+	for i := maxLen - 1; i >= 0; i-- {
+		q = g1MontDouble(m, &q)
+		for c := 0; c < len(nafs); c++ {
+			if i >= len(nafs[c]) || nafs[c][i] == 0 {
+				continue
+			}
+			d := nafs[c][i]
+			if d > 0 {
+				q = g1MontAdd(m, &q, &tables[c][d/2])
+			} else {
+				neg := g1MontNeg(m, &tables[c][-d/2])
+				q = g1MontAdd(m, &q, &neg)
+			}
+		}
+	}
+	return q
+}
+
+// g2GLS holds the constants of the G2 endomorphism ψ(P) = q*P
+type g2GLS struct {
+	r       *big.Int
+	x       *big.Int // curve parameter, used by the subgroup check
+	cx, cy  e2       // ψ(x, y) = (conj(x)*cx, conj(y)*cy), in Montgomery form
+	lattice *glvLattice
+}
+
+// newG2GLS uses the 4 dimensional basis for BN curves of Galbraith and Scott,
+// in terms of the curve parameter x, where λ = q mod R = 6x^2. cx and cy are
+// the coefficients of ψ (TwistMulByQX and TwistMulByQY)
+func newG2GLS(g2 G2, r, x *big.Int, cx, cy [2]*big.Int) (*g2GLS, error) {
+	f := g2.montgomery()
+	if f == nil {
+		return nil, errors.New("gls needs the montgomery backend")
+	}
+	lambda := new(big.Int).Mod(g2.F.F.Q, r)
+
+	n := func(c int64) *big.Int { return big.NewInt(c) }
+	lin := func(a, b int64) *big.Int { // a*x + b
+		return new(big.Int).Add(new(big.Int).Mul(n(a), x), n(b))
+	}
+	basis := [][]*big.Int{
+		{lin(1, 1), lin(1, 0), lin(1, 0), lin(-2, 0)},
+		{lin(2, 1), lin(-1, 0), lin(-1, -1), lin(-1, 0)},
+		{lin(2, 0), lin(2, 1), lin(2, 1), lin(2, 1)},
+		{lin(1, -1), lin(4, 2), lin(-2, 1), lin(1, -1)},
+	}
+	lattice, err := newGLVLattice(basis, lambda, r)
+	if err != nil {
+		return nil, err
+	}
+	gls := &g2GLS{r: r, x: x, cx: f.toMont(cx), cy: f.toMont(cy), lattice: lattice}
+
+	mg := f.g2ToMont(g2.G)
+	psiG := gls.psi(f, &mg)
+	if !g2.Equal(f.g2FromMont(&psiG), g2.mulScalar(g2.G, lambda)) {
+		return nil, errors.New("no endomorphism for the G2 generator")
+	}
+	return gls, nil
+}
+
+func (f *fq2Mont) conj(z, a *e2) {
+	z[0] = a[0]
+	f.m.Neg(&z[1], &a[1])
+}
+
+// psi computes ψ over Jacobian coordinates: (conj(X)*cx, conj(Y)*cy, conj(Z))
+func (gls *g2GLS) psi(f *fq2Mont, p *g2Mont) g2Mont {
+	var r g2Mont
+	f.conj(&r[0], &p[0])
+	f.mul(&r[0], &r[0], &gls.cx)
+	f.conj(&r[1], &p[1])
+	f.mul(&r[1], &r[1], &gls.cy)
+	f.conj(&r[2], &p[2])
+	return r
+}
+
+// isInSubgroup checks that a point of the twist is in G2 with ψ instead of a
+// multiplication by R: for the BN curves p is in G2 if and only if
+// [x+1]p + ψ([x]p) + ψ²([x]p) = ψ³([2x]p) (El Housni, Guillevic, Piellard,
+// https://eprint.iacr.org/2022/352.pdf). The multiplications are by x, which
+// is a quarter of the size of R, and do not use the endomorphism
+func (gls *g2GLS) isInSubgroup(f *fq2Mont, p *g2Mont) bool {
+	xp := f.g2MulScalar(p, gls.x)
+	l := f.g2Add(&xp, p)
+	t := gls.psi(f, &xp)
+	l = f.g2Add(&l, &t)
+	t = gls.psi(f, &t)
+	l = f.g2Add(&l, &t)
+
+	r := f.g2Double(&xp)
+	for i := 0; i < 3; i++ {
+		r = gls.psi(f, &r)
+	}
+	r = f.g2Neg(&r)
+	l = f.g2Add(&l, &r)
+	return f.isZero(&l[2])
+}
+
+func (f *fq2Mont) g2Neg(p *g2Mont) g2Mont {
+	r := *p
+	f.m.Neg(&r[1][0], &p[1][0])
+	f.m.Neg(&r[1][1], &p[1][1])
+	return r
+}
+
+// g2OddMultiples returns P, 3P, 5P, ..., (2n-1)P
+func (f *fq2Mont) g2OddMultiples(p *g2Mont, n int) []g2Mont {
+	table := make([]g2Mont, n)
+	table[0] = *p
+	p2 := f.g2Double(p)
+	for i := 1; i < n; i++ {
+		table[i] = f.g2Add(&table[i-1], &p2)
+	}
+	return table
+}
+
+func (gls *g2GLS) mulScalar(f *fq2Mont, p *g2Mont, e *big.Int) g2Mont {
+	ks := gls.lattice.decompose(new(big.Int).Mod(e, gls.r))
+	nafs, maxLen := signedWNAFs(ks, g2WNAFWindow)
+
+	tables := make([][]g2Mont, 4)
+	tables[0] = f.g2OddMultiples(p, 1<<(g2WNAFWindow-2))
+	for c := 1; c < len(tables); c++ {
+		tables[c] = make([]g2Mont, len(tables[0]))
+		for j := 0; j < len(tables[0]); j++ {
+			tables[c][j] = gls.psi(f, &tables[c-1][j])
+		}
+	}
+
+	var q g2Mont
+	for i := maxLen - 1; i >= 0; i-- {
+		q = f.g2Double(&q)
+		for c := 0; c < len(nafs); c++ {
+			if i >= len(nafs[c]) || nafs[c][i] == 0 {
+				continue
+			}
+			d := nafs[c][i]
+			if d > 0 {
+				q = f.g2Add(&q, &tables[c][d/2])
+			} else {
+				neg := f.g2Neg(&tables[c][-d/2])
+				q = f.g2Add(&q, &neg)
+			}
+		}
+	}
+	return q
+}
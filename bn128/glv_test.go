@@ -0,0 +1,148 @@
+package bn128
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/stretchr/testify/assert"
+)
+
+// glvTestScalars returns the edge case scalars and some random ones
+func glvTestScalars(r *big.Int, n int) []*big.Int {
+	max256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(int64(1)), 256), big.NewInt(int64(1)))
+	scalars := []*big.Int{
+		big.NewInt(int64(0)),
+		big.NewInt(int64(1)),
+		big.NewInt(int64(2)),
+		big.NewInt(int64(-5)),
+		new(big.Int).Sub(r, big.NewInt(int64(1))),
+		new(big.Int).Set(r),
+		new(big.Int).Add(r, big.NewInt(int64(1))),
+		new(big.Int).Neg(r),
+		max256,
+	}
+	return append(scalars, randScalars(mrand.New(mrand.NewSource(1)), r, n)...)
+}
+
+func TestGLVDecompose(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	for _, l := range []struct {
+		lattice *glvLattice
+		maxBits int
+	}{
+		{bn128.G1.glv.lattice, 128},
+		{bn128.G2.gls.lattice, 66},
+	} {
+		for _, k := range glvTestScalars(bn128.R, 50) {
+			k = new(big.Int).Mod(k, bn128.R)
+			ks := l.lattice.decompose(k)
+			acc := new(big.Int)
+			pow := big.NewInt(int64(1))
+			for i := 0; i < len(ks); i++ {
+				assert.True(t, ks[i].BitLen() <= l.maxBits)
+				acc.Add(acc, new(big.Int).Mul(ks[i], pow))
+				pow.Mod(pow.Mul(pow, l.lattice.lambda), bn128.R)
+			}
+			assert.Equal(t, 0, k.Cmp(acc.Mod(acc, bn128.R)))
+		}
+	}
+}
+
+func TestWNAF(t *testing.T) {
+	rnd := mrand.New(mrand.NewSource(1))
+	for _, k := range randScalars(rnd, new(big.Int).Lsh(big.NewInt(int64(1)), 130), 50) {
+		digits := wnaf(k, g1WNAFWindow)
+		acc := new(big.Int)
+		for i := len(digits) - 1; i >= 0; i-- {
+			acc.Lsh(acc, 1)
+			acc.Add(acc, big.NewInt(int64(digits[i])))
+			if digits[i] != 0 {
+				assert.True(t, digits[i]%2 != 0)
+				assert.True(t, digits[i] < 1<<(g1WNAFWindow-1) && digits[i] > -(1<<(g1WNAFWindow-1)))
+				// after a non zero digit there are at least w-1 zeros
+				for j := 1; j < g1WNAFWindow && i+j < len(digits); j++ {
+					assert.Equal(t, 0, digits[i+j])
+				}
+			}
+		}
+		assert.Equal(t, 0, k.Cmp(acc))
+	}
+}
+
+func TestG1GLVMulScalar(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	m := bn128.Fq1.Montgomery()
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(12345)))
+	mp := g1ToMont(m, p)
+
+	for _, e := range glvTestScalars(bn128.R, 20) {
+		plain := g1MontMulScalar(m, &mp, new(big.Int).Abs(e))
+		expected := bn128.G1.normalize(g1FromMont(m, &plain))
+		assert.Equal(t, expected, bn128.G1.MulScalar(p, e))
+	}
+}
+
+func TestG2GLSMulScalar(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	f := newFq2Mont(bn128.Fq2)
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(12345)))
+	mp := f.g2ToMont(p)
+
+	for _, e := range glvTestScalars(bn128.R, 10) {
+		plain := f.g2MulScalar(&mp, new(big.Int).Abs(e))
+		expected := bn128.G2.Affine(f.g2FromMont(&plain))
+		assert.Equal(t, expected, bn128.G2.MulScalar(p, e))
+	}
+
+	// ψ(p) = q*p does not hold out of G2, so the points of the twist out of
+	// it are multiplied with mulScalar
+	g2Big := NewG2(fields.NewFq2(fields.Fq{Q: bn128.Q}, bn128.NonResidueFq2), bn128.Gg2)
+	q := twistPointNotInG2(bn128)
+	for _, e := range glvTestScalars(bn128.R, 5) {
+		assert.Equal(t, g2Big.mulScalar(q, e), bn128.G2.mulScalar(q, e))
+	}
+}
+
+func BenchmarkG1MulScalar(b *testing.B) {
+	bn128, err := NewBn128()
+	assert.Nil(b, err)
+	e := new(big.Int).Rand(mrand.New(mrand.NewSource(1)), bn128.R)
+	m := bn128.Fq1.Montgomery()
+	mp := g1ToMont(m, bn128.G1.G)
+
+	b.Run("DoubleAndAdd", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			g1MontMulScalar(m, &mp, e)
+		}
+	})
+	b.Run("GLV", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bn128.G1.glv.mulScalar(m, &mp, e)
+		}
+	})
+}
+
+func BenchmarkG2MulScalar(b *testing.B) {
+	bn128, err := NewBn128()
+	assert.Nil(b, err)
+	e := new(big.Int).Rand(mrand.New(mrand.NewSource(1)), bn128.R)
+	f := newFq2Mont(bn128.Fq2)
+	mp := f.g2ToMont(bn128.G2.G)
+
+	b.Run("DoubleAndAdd", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			f.g2MulScalar(&mp, e)
+		}
+	})
+	b.Run("GLS", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bn128.G2.gls.mulScalar(f, &mp, e)
+		}
+	})
+}
@@ -0,0 +1,249 @@
+package bn128
+
+import (
+	"crypto/sha256"
+	"math/big"
+)
+
+// The functions in this file hash arbitrary messages to points of G1 and G2,
+// following the structure of the IETF hash-to-curve draft
+// (https://datatracker.ietf.org/doc/draft-irtf-cfrg-hash-to-curve/) for the
+// suite BN254G1_XMD:SHA-256_SVDW_RO_, and the same construction over the
+// twist for G2:
+//	u0, u1 = hash_to_field(msg, 2), with expand_message_xmd and SHA-256
+//	P = clear_cofactor(map_to_curve(u0) + map_to_curve(u1))
+// where map_to_curve is the Shallue-van de Woestijne map, that works for the
+// curves y^2 = x^3 + b. The dst is the domain separation tag, different for
+// each application. The running time depends on the message.
+
+const (
+	// hashToFieldLen is the number of bytes of each element of hash_to_field:
+	// ceil((ceil(log2(Q)) + k) / 8), for the security level k = 128
+	hashToFieldLen = 48
+	// svdwZ is the Z constant of the map, for both G1 and G2, as given by
+	// the find_z_svdw procedure of the draft
+	svdwZ = 1
+)
+
+// expandMessageXMD is the expand_message_xmd function of the draft with
+// SHA-256, that returns lenInBytes uniformly random bytes from the msg and the dst
+func expandMessageXMD(msg, dst []byte, lenInBytes int) []byte {
+	const bInBytes, sInBytes = sha256.Size, sha256.BlockSize
+	ell := (lenInBytes + bInBytes - 1) / bInBytes
+	if ell > 255 {
+		panic("bn128: expand_message_xmd output too long")
+	}
+	if len(dst) > 255 {
+		h := sha256.Sum256(append([]byte("H2C-OVERSIZE-DST-"), dst...))
+		dst = h[:]
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := sha256.New()
+	h.Write(make([]byte, sInBytes))
+	h.Write(msg)
+	h.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	uniform := append([]byte{}, bi...)
+	for i := 2; i <= ell; i++ {
+		x := make([]byte, bInBytes)
+		for j := 0; j < bInBytes; j++ {
+			x[j] = b0[j] ^ bi[j]
+		}
+		h.Reset()
+		h.Write(x)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		uniform = append(uniform, bi...)
+	}
+	return uniform[:lenInBytes]
+}
+
+// hashToField returns count*m elements of Fq from the msg (m is 1 for the
+// Fq and 2 for the Fq2), as hash_to_field of the draft
+func hashToField(q *big.Int, msg, dst []byte, count, m int) []*big.Int {
+	uniform := expandMessageXMD(msg, dst, count*m*hashToFieldLen)
+	elems := make([]*big.Int, count*m)
+	for i := 0; i < len(elems); i++ {
+		e := new(big.Int).SetBytes(uniform[i*hashToFieldLen : (i+1)*hashToFieldLen])
+		elems[i] = e.Mod(e, q)
+	}
+	return elems
+}
+
+// sgn0 returns the sign of a, as defined in the draft
+func sgn0(a *big.Int) uint {
+	return a.Bit(0)
+}
+
+// sgn0Fq2 returns the sign of a over the Fq2, as defined in the draft
+func sgn0Fq2(a [2]*big.Int) uint {
+	if a[0].Sign() == 0 {
+		return a[1].Bit(0)
+	}
+	return a[0].Bit(0)
+}
+
+// g1SvdW holds the constants of the SvdW map over the Fq, that only depend
+// on the curve: c1 = g(Z), c2 = -Z/2, c3 = sqrt(-g(Z) * 3Z^2) with
+// sgn0(c3) = 0, c4 = -4g(Z) / 3Z^2
+type g1SvdW struct {
+	z, c1, c2, c3, c4 *big.Int
+}
+
+// newG1SvdW computes the constants of the SvdW map of the G1
+func newG1SvdW(g1 G1) *g1SvdW {
+	f := g1.F
+	z := big.NewInt(int64(svdwZ))
+	c1 := f.Add(f.Mul(f.Square(z), z), g1.coefB())
+	c2 := f.Div(f.Neg(z), big.NewInt(int64(2)))
+	threeZ2 := f.MulScalar(f.Square(z), big.NewInt(int64(3)))
+	c3, _ := f.Sqrt(f.Neg(f.Mul(c1, threeZ2)))
+	if sgn0(c3) != 0 {
+		c3 = f.Neg(c3)
+	}
+	c4 := f.Div(f.Neg(f.MulScalar(c1, big.NewInt(int64(4)))), threeZ2)
+	return &g1SvdW{z, c1, c2, c3, c4}
+}
+
+// svdw maps u to a point of the curve with the Shallue-van de Woestijne
+// map (as map_to_curve_svdw of the draft, with A = 0)
+func (g1 G1) svdw(u *big.Int) [3]*big.Int {
+	f := g1.F
+	b := g1.coefB()
+	gx := func(x *big.Int) *big.Int {
+		return f.Add(f.Mul(f.Square(x), x), b)
+	}
+	inv0 := func(a *big.Int) *big.Int {
+		if f.IsZero(a) {
+			return f.Zero()
+		}
+		return f.Inverse(a)
+	}
+	c := g1.svdwC
+	if c == nil {
+		c = newG1SvdW(g1)
+	}
+	z, c1, c2, c3, c4 := c.z, c.c1, c.c2, c.c3, c.c4
+
+	tv1 := f.Mul(f.Square(u), c1)
+	tv2 := f.Add(f.One(), tv1)
+	tv1 = f.Sub(f.One(), tv1)
+	tv3 := inv0(f.Mul(tv1, tv2))
+	tv4 := f.Mul(f.Mul(f.Mul(u, tv1), tv3), c3)
+	x1 := f.Sub(c2, tv4)
+	x2 := f.Add(c2, tv4)
+	x3 := f.Add(f.Mul(f.Square(f.Mul(f.Square(tv2), tv3)), c4), z)
+
+	var x *big.Int
+	switch {
+	case f.IsSquare(gx(x1)):
+		x = x1
+	case f.IsSquare(gx(x2)):
+		x = x2
+	default:
+		x = x3
+	}
+	y, _ := f.Sqrt(gx(x))
+	if sgn0(u) != sgn0(y) {
+		y = f.Neg(y)
+	}
+	return [3]*big.Int{x, y, f.One()}
+}
+
+// g2SvdW holds the constants of the SvdW map over the Fq2, as g1SvdW
+type g2SvdW struct {
+	z, c1, c2, c3, c4 [2]*big.Int
+}
+
+// newG2SvdW computes the constants of the SvdW map of the G2
+func newG2SvdW(g2 G2) *g2SvdW {
+	f := g2.F
+	z := [2]*big.Int{big.NewInt(int64(svdwZ)), f.F.Zero()}
+	c1 := f.Add(f.Mul(f.Square(z), z), g2.coefB())
+	c2 := f.MulScalar(f.Neg(z), f.F.Inverse(big.NewInt(int64(2))))
+	threeZ2 := f.MulScalar(f.Square(z), big.NewInt(int64(3)))
+	c3, _ := f.Sqrt(f.Neg(f.Mul(c1, threeZ2)))
+	if sgn0Fq2(c3) != 0 {
+		c3 = f.Neg(c3)
+	}
+	c4 := f.Div(f.Neg(f.MulScalar(c1, big.NewInt(int64(4)))), threeZ2)
+	return &g2SvdW{z, c1, c2, c3, c4}
+}
+
+// svdw maps u to a point of the twist, as G1.svdw over the Fq2
+func (g2 G2) svdw(u [2]*big.Int) [3][2]*big.Int {
+	f := g2.F
+	b := g2.coefB()
+	gx := func(x [2]*big.Int) [2]*big.Int {
+		return f.Add(f.Mul(f.Square(x), x), b)
+	}
+	inv0 := func(a [2]*big.Int) [2]*big.Int {
+		if f.IsZero(a) {
+			return f.Zero()
+		}
+		return f.Inverse(a)
+	}
+	isSquare := func(a [2]*big.Int) bool {
+		_, ok := f.Sqrt(a)
+		return ok
+	}
+	c := g2.svdwC
+	if c == nil {
+		c = newG2SvdW(g2)
+	}
+	z, c1, c2, c3, c4 := c.z, c.c1, c.c2, c.c3, c.c4
+
+	tv1 := f.Mul(f.Square(u), c1)
+	tv2 := f.Add(f.One(), tv1)
+	tv1 = f.Sub(f.One(), tv1)
+	tv3 := inv0(f.Mul(tv1, tv2))
+	tv4 := f.Mul(f.Mul(f.Mul(u, tv1), tv3), c3)
+	x1 := f.Sub(c2, tv4)
+	x2 := f.Add(c2, tv4)
+	x3 := f.Add(f.Mul(f.Square(f.Mul(f.Square(tv2), tv3)), c4), z)
+
+	var x [2]*big.Int
+	switch {
+	case isSquare(gx(x1)):
+		x = x1
+	case isSquare(gx(x2)):
+		x = x2
+	default:
+		x = x3
+	}
+	y, _ := f.Sqrt(gx(x))
+	y = f.Affine(y)
+	if sgn0Fq2(u) != sgn0Fq2(y) {
+		y = f.Neg(y)
+	}
+	return [3][2]*big.Int{x, y, f.One()}
+}
+
+// HashToG1 hashes the msg to a point of G1, with the domain separation tag
+// dst. As the cofactor of G1 is 1, there is no cofactor clearing
+func (bn128 Bn128) HashToG1(msg, dst []byte) [3]*big.Int {
+	u := hashToField(bn128.Q, msg, dst, 2, 1)
+	return bn128.G1.Add(bn128.G1.svdw(u[0]), bn128.G1.svdw(u[1]))
+}
+
+// HashToG2 hashes the msg to a point of G2, with the domain separation tag
+// dst. The points given by the map are on the twist, so they are multiplied
+// by its cofactor 2Q - R to get a point of the subgroup of order R
+func (bn128 Bn128) HashToG2(msg, dst []byte) [3][2]*big.Int {
+	u := hashToField(bn128.Q, msg, dst, 2, 2)
+	p := bn128.G2.Add(
+		bn128.G2.svdw([2]*big.Int{u[0], u[1]}),
+		bn128.G2.svdw([2]*big.Int{u[2], u[3]}))
+	cofactor := new(big.Int).Sub(new(big.Int).Lsh(bn128.Q, 1), bn128.R)
+	return bn128.G2.Affine(bn128.G2.mulScalar(p, cofactor))
+}
@@ -0,0 +1,93 @@
+package bn128
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandMessageXMD(t *testing.T) {
+	// test vectors of the draft, for expand_message_xmd with SHA-256
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	vectors := []struct {
+		msg      string
+		expected string
+	}{
+		{"", "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{"abc", "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+		{"abcdef0123456789", "eff31487c770a893cfb36f912fbfcbff40d5661771ca4b2cb4eafe524333f5c1"},
+	}
+	for _, v := range vectors {
+		assert.Equal(t, v.expected, hex.EncodeToString(expandMessageXMD([]byte(v.msg), dst, 32)))
+	}
+	assert.Equal(t, 256, len(expandMessageXMD([]byte("abc"), dst, 256)))
+}
+
+func TestHashToG1(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	dst := []byte("QUUX-V01-CS02-with-BN254G1_XMD:SHA-256_SVDW_RO_")
+
+	// test vectors of the draft, for BN254G1_XMD:SHA-256_SVDW_RO_
+	vectors := []struct {
+		msg string
+		x   string
+		y   string
+	}{
+		{"",
+			"0a976ab906170db1f9638d376514dbf8c42aef256a54bbd48521f20749e59e86",
+			"02925ead66b9e68bfc309b014398640ab55f6619ab59bc1fab2210ad4c4d53d5"},
+		{"abc",
+			"23f717bee89b1003957139f193e6be7da1df5f1374b26a4643b0378b5baf53d1",
+			"04142f826b71ee574452dbc47e05bc3e1a647478403a7ba38b7b93948f4e151d"},
+		{"abcdef0123456789",
+			"187dbf1c3c89aceceef254d6548d7163fdfa43084145f92c4c91c85c21442d4a",
+			"0abd99d5b0000910b56058f9cc3b0ab0a22d47cf27615f588924fac1e5c63b4d"},
+		{"q128_" + strings.Repeat("q", 128),
+			"00fe2b0743575324fc452d590d217390ad48e5a16cf051bee5c40a2eba233f5c",
+			"0794211e0cc72d3cbbdf8e4e5cd6e7d7e78d101ff94862caae8acbe63e9fdc78"},
+		{"a512_" + strings.Repeat("a", 512),
+			"01b05dc540bd79fd0fea4fbb07de08e94fc2e7bd171fe025c479dc212a2173ce",
+			"1bf028afc00c0f843d113758968f580640541728cfc6d32ced9779aa613cd9b0"},
+	}
+	for _, v := range vectors {
+		p := bn128.G1.Affine(bn128.HashToG1([]byte(v.msg), dst))
+		assert.Equal(t, v.x, fmt.Sprintf("%064x", p[0]))
+		assert.Equal(t, v.y, fmt.Sprintf("%064x", p[1]))
+	}
+}
+
+func TestHashToG1Messages(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	dst := []byte("go-snark-test-G1")
+
+	p := bn128.HashToG1([]byte("abc"), dst)
+	assert.Nil(t, bn128.G1.Validate(p))
+	assert.True(t, bn128.G1.Equal(p, bn128.HashToG1([]byte("abc"), dst)))
+	assert.False(t, bn128.G1.Equal(p, bn128.HashToG1([]byte("abd"), dst)))
+	assert.False(t, bn128.G1.Equal(p, bn128.HashToG1([]byte("abc"), []byte("go-snark-test-G1-other"))))
+}
+
+func TestHashToG2(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	dst := []byte("go-snark-test-G2")
+
+	p := bn128.HashToG2([]byte("abc"), dst)
+	assert.Nil(t, bn128.G2.Validate(p))
+	assert.False(t, bn128.G2.IsZero(p))
+	assert.Equal(t, bn128.G2.Affine(p), bn128.G2.Affine(bn128.HashToG2([]byte("abc"), dst)))
+	assert.False(t, bn128.G2.Equal(p, bn128.HashToG2([]byte("abd"), dst)))
+	assert.False(t, bn128.G2.Equal(p, bn128.HashToG2([]byte("abc"), []byte("go-snark-test-G2-other"))))
+
+	// the map alone gives points of the twist that are not in G2
+	u := hashToField(bn128.Q, []byte("abc"), dst, 1, 2)
+	q := bn128.G2.svdw([2]*big.Int{u[0], u[1]})
+	assert.True(t, bn128.G2.IsOnCurve(q))
+	assert.False(t, bn128.G2.IsInSubgroup(q))
+}
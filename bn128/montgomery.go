@@ -0,0 +1,288 @@
+package bn128
+
+import (
+	"math/big"
+
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// The functions in this file run the G1 and G2 point operations over the
+// fixed size Montgomery Elements of the fields package, so a full scalar
+// multiplication is done without allocating intermediate *big.Int values.
+// They use the same formulas than the big.Int implementations in g1.go and
+// g2.go, so the resulting coordinates are the same.
+
+// g1Mont is a G1 point in Jacobian coordinates, in Montgomery form
+type g1Mont [3]fields.Element
+
+func g1ToMont(m *fields.Montgomery, p [3]*big.Int) g1Mont {
+	return g1Mont{m.ToMont(p[0]), m.ToMont(p[1]), m.ToMont(p[2])}
+}
+
+func g1FromMont(m *fields.Montgomery, p *g1Mont) [3]*big.Int {
+	return [3]*big.Int{m.FromMont(&p[0]), m.FromMont(&p[1]), m.FromMont(&p[2])}
+}
+
+func g1MontAdd(m *fields.Montgomery, p1, p2 *g1Mont) g1Mont {
+	if p1[2].IsZero() {
+		return *p2
+	}
+	if p2[2].IsZero() {
+		return *p1
+	}
+	var z1z1, z2z2, u1, u2, t0, s1, s2, h, i, j, r, v, x3, y3, z3 fields.Element
+	m.Square(&z1z1, &p1[2])
+	m.Square(&z2z2, &p2[2])
+	m.Mul(&u1, &p1[0], &z2z2)
+	m.Mul(&u2, &p2[0], &z1z1)
+	m.Mul(&t0, &p2[2], &z2z2)
+	m.Mul(&s1, &p1[1], &t0)
+	m.Mul(&t0, &p1[2], &z1z1)
+	m.Mul(&s2, &p2[1], &t0)
+
+	m.Sub(&h, &u2, &u1)
+	m.Double(&i, &h)
+	m.Square(&i, &i)
+	m.Mul(&j, &h, &i)
+	m.Sub(&r, &s2, &s1)
+	if h.IsZero() && r.IsZero() {
+		// p1 == p2, the addition formula does not work for doubling
+		return g1MontDouble(m, p1)
+	}
+	m.Double(&r, &r)
+	m.Mul(&v, &u1, &i)
+
+	m.Square(&x3, &r)
+	m.Sub(&x3, &x3, &j)
+	m.Sub(&x3, &x3, &v)
+	m.Sub(&x3, &x3, &v)
+
+	m.Sub(&y3, &v, &x3)
+	m.Mul(&y3, &r, &y3)
+	m.Mul(&t0, &s1, &j)
+	m.Double(&t0, &t0)
+	m.Sub(&y3, &y3, &t0)
+
+	m.Add(&z3, &p1[2], &p2[2])
+	m.Square(&z3, &z3)
+	m.Sub(&z3, &z3, &z1z1)
+	m.Sub(&z3, &z3, &z2z2)
+	m.Mul(&z3, &z3, &h)
+
+	return g1Mont{x3, y3, z3}
+}
+
+func g1MontDouble(m *fields.Montgomery, p *g1Mont) g1Mont {
+	if p[2].IsZero() {
+		return *p
+	}
+	var a, b, c, d, e, f, t, x3, y3, z3 fields.Element
+	m.Square(&a, &p[0])
+	m.Square(&b, &p[1])
+	m.Square(&c, &b)
+
+	m.Add(&d, &p[0], &b)
+	m.Square(&d, &d)
+	m.Sub(&d, &d, &a)
+	m.Sub(&d, &d, &c)
+	m.Double(&d, &d)
+
+	m.Double(&e, &a)
+	m.Add(&e, &e, &a)
+	m.Square(&f, &e)
+
+	m.Double(&t, &d)
+	m.Sub(&x3, &f, &t)
+
+	m.Sub(&y3, &d, &x3)
+	m.Mul(&y3, &e, &y3)
+	m.Double(&t, &c)
+	m.Double(&t, &t)
+	m.Double(&t, &t)
+	m.Sub(&y3, &y3, &t)
+
+	m.Mul(&z3, &p[1], &p[2])
+	m.Double(&z3, &z3)
+
+	return g1Mont{x3, y3, z3}
+}
+
+func g1MontMulScalar(m *fields.Montgomery, p *g1Mont, e *big.Int) g1Mont {
+	var q g1Mont
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		q = g1MontDouble(m, &q)
+		if e.Bit(i) == 1 {
+			q = g1MontAdd(m, &q, p)
+		}
+	}
+	return q
+}
+
+// fq2Mont performs the Fq2 operations over pairs of Montgomery Elements
+type fq2Mont struct {
+	m          *fields.Montgomery
+	nonResidue fields.Element
+}
+
+// e2 is an Fq2 element in Montgomery form
+type e2 [2]fields.Element
+
+func newFq2Mont(f fields.Fq2) *fq2Mont {
+	m := f.F.Montgomery()
+	if m == nil {
+		return nil
+	}
+	return &fq2Mont{
+		m:          m,
+		nonResidue: m.ToMont(f.NonResidue),
+	}
+}
+
+func (f *fq2Mont) toMont(a [2]*big.Int) e2 {
+	return e2{f.m.ToMont(a[0]), f.m.ToMont(a[1])}
+}
+
+func (f *fq2Mont) fromMont(a *e2) [2]*big.Int {
+	return [2]*big.Int{f.m.FromMont(&a[0]), f.m.FromMont(&a[1])}
+}
+
+func (f *fq2Mont) isZero(a *e2) bool {
+	return a[0].IsZero() && a[1].IsZero()
+}
+
+func (f *fq2Mont) add(z, a, b *e2) {
+	f.m.Add(&z[0], &a[0], &b[0])
+	f.m.Add(&z[1], &a[1], &b[1])
+}
+
+func (f *fq2Mont) double(z, a *e2) {
+	f.add(z, a, a)
+}
+
+func (f *fq2Mont) sub(z, a, b *e2) {
+	f.m.Sub(&z[0], &a[0], &b[0])
+	f.m.Sub(&z[1], &a[1], &b[1])
+}
+
+func (f *fq2Mont) mul(z, a, b *e2) {
+	// Karatsuba, as in fields.Fq2.Mul
+	var v0, v1, s0, s1 fields.Element
+	f.m.Mul(&v0, &a[0], &b[0])
+	f.m.Mul(&v1, &a[1], &b[1])
+	f.m.Add(&s0, &a[0], &a[1])
+	f.m.Add(&s1, &b[0], &b[1])
+	f.m.Mul(&s0, &s0, &s1)
+	f.m.Sub(&s0, &s0, &v0)
+	f.m.Sub(&z[1], &s0, &v1)
+	f.m.Mul(&v1, &v1, &f.nonResidue)
+	f.m.Add(&z[0], &v0, &v1)
+}
+
+func (f *fq2Mont) square(z, a *e2) {
+	f.mul(z, a, a)
+}
+
+// g2Mont is a G2 point in Jacobian coordinates, in Montgomery form
+type g2Mont [3]e2
+
+func (f *fq2Mont) g2ToMont(p [3][2]*big.Int) g2Mont {
+	return g2Mont{f.toMont(p[0]), f.toMont(p[1]), f.toMont(p[2])}
+}
+
+func (f *fq2Mont) g2FromMont(p *g2Mont) [3][2]*big.Int {
+	return [3][2]*big.Int{f.fromMont(&p[0]), f.fromMont(&p[1]), f.fromMont(&p[2])}
+}
+
+func (f *fq2Mont) g2Add(p1, p2 *g2Mont) g2Mont {
+	if f.isZero(&p1[2]) {
+		return *p2
+	}
+	if f.isZero(&p2[2]) {
+		return *p1
+	}
+	var z1z1, z2z2, u1, u2, t0, s1, s2, h, i, j, r, v, x3, y3, z3 e2
+	f.square(&z1z1, &p1[2])
+	f.square(&z2z2, &p2[2])
+	f.mul(&u1, &p1[0], &z2z2)
+	f.mul(&u2, &p2[0], &z1z1)
+	f.mul(&t0, &p2[2], &z2z2)
+	f.mul(&s1, &p1[1], &t0)
+	f.mul(&t0, &p1[2], &z1z1)
+	f.mul(&s2, &p2[1], &t0)
+
+	f.sub(&h, &u2, &u1)
+	f.double(&i, &h)
+	f.square(&i, &i)
+	f.mul(&j, &h, &i)
+	f.sub(&r, &s2, &s1)
+	if f.isZero(&h) && f.isZero(&r) {
+		return f.g2Double(p1)
+	}
+	f.double(&r, &r)
+	f.mul(&v, &u1, &i)
+
+	f.square(&x3, &r)
+	f.sub(&x3, &x3, &j)
+	f.sub(&x3, &x3, &v)
+	f.sub(&x3, &x3, &v)
+
+	f.sub(&y3, &v, &x3)
+	f.mul(&y3, &r, &y3)
+	f.mul(&t0, &s1, &j)
+	f.double(&t0, &t0)
+	f.sub(&y3, &y3, &t0)
+
+	f.add(&z3, &p1[2], &p2[2])
+	f.square(&z3, &z3)
+	f.sub(&z3, &z3, &z1z1)
+	f.sub(&z3, &z3, &z2z2)
+	f.mul(&z3, &z3, &h)
+
+	return g2Mont{x3, y3, z3}
+}
+
+func (f *fq2Mont) g2Double(p *g2Mont) g2Mont {
+	if f.isZero(&p[2]) {
+		return *p
+	}
+	var a, b, c, d, e, ff, t, x3, y3, z3 e2
+	f.square(&a, &p[0])
+	f.square(&b, &p[1])
+	f.square(&c, &b)
+
+	f.add(&d, &p[0], &b)
+	f.square(&d, &d)
+	f.sub(&d, &d, &a)
+	f.sub(&d, &d, &c)
+	f.double(&d, &d)
+
+	f.double(&e, &a)
+	f.add(&e, &e, &a)
+	f.square(&ff, &e)
+
+	f.double(&t, &d)
+	f.sub(&x3, &ff, &t)
+
+	f.sub(&y3, &d, &x3)
+	f.mul(&y3, &e, &y3)
+	f.double(&t, &c)
+	f.double(&t, &t)
+	f.double(&t, &t)
+	f.sub(&y3, &y3, &t)
+
+	f.mul(&z3, &p[1], &p[2])
+	f.double(&z3, &z3)
+
+	return g2Mont{x3, y3, z3}
+}
+
+func (f *fq2Mont) g2MulScalar(p *g2Mont, e *big.Int) g2Mont {
+	var q g2Mont
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		q = f.g2Double(&q)
+		if e.Bit(i) == 1 {
+			q = f.g2Add(&q, p)
+		}
+	}
+	return q
+}
@@ -0,0 +1,225 @@
+package bn128
+
+import (
+	"math/big"
+	"math/bits"
+	"sync"
+
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// The functions in this file compute the multi-scalar multiplication
+// Σ scalars[i] * points[i] with the bucket method of Pippenger: the scalars
+// are split in windows of c bits, and for each window the points are added
+// to the bucket of their digit, so each point costs one addition per window
+// instead of one doubling and (on average) half an addition per bit. The
+// buckets are combined with running sums, and the windows with c doublings.
+// As in MulScalar, the sign of the scalars is ignored.
+
+// multiExpWindow returns the window size for n points, close to log2(n) - 3,
+// which minimizes the number of additions for the BN128 scalar sizes
+func multiExpWindow(n int) int {
+	c := bits.Len(uint(n)) - 3
+	if c < 2 {
+		return 2
+	}
+	if c > 16 {
+		return 16
+	}
+	return c
+}
+
+// multiExpScalars returns the absolute values of the scalars and the maximum bit length
+func multiExpScalars(scalars []*big.Int) ([]*big.Int, int) {
+	abs := make([]*big.Int, len(scalars))
+	nbits := 0
+	for i := 0; i < len(scalars); i++ {
+		abs[i] = new(big.Int).Abs(scalars[i])
+		if abs[i].BitLen() > nbits {
+			nbits = abs[i].BitLen()
+		}
+	}
+	return abs, nbits
+}
+
+// scalarDigit returns the c bits of e starting at the bit start
+func scalarDigit(e *big.Int, start, c int) int {
+	d := 0
+	for k := c - 1; k >= 0; k-- {
+		d = d<<1 | int(e.Bit(start+k))
+	}
+	return d
+}
+
+// multiExpChunks splits n elements in the given number of chunks, returning the bounds of each chunk
+func multiExpChunks(n, routines int) [][2]int {
+	if routines > n {
+		routines = n
+	}
+	if routines < 1 {
+		routines = 1
+	}
+	chunks := make([][2]int, routines)
+	for i := 0; i < routines; i++ {
+		chunks[i] = [2]int{i * n / routines, (i + 1) * n / routines}
+	}
+	return chunks
+}
+
+func checkMultiExpLen(nPoints, nScalars int) {
+	if nPoints != nScalars {
+		panic("bn128: MultiExp with different number of points and scalars")
+	}
+}
+
+func g1MontMultiExp(m *fields.Montgomery, points []g1Mont, scalars []*big.Int, nbits int) g1Mont {
+	c := multiExpWindow(len(points))
+	buckets := make([]g1Mont, 1<<uint(c)-1)
+	var acc g1Mont
+	for start := ((nbits+c-1)/c - 1) * c; start >= 0; start -= c {
+		for j := 0; j < c; j++ {
+			acc = g1MontDouble(m, &acc)
+		}
+		for j := 0; j < len(buckets); j++ {
+			buckets[j] = g1Mont{}
+		}
+		for i := 0; i < len(points); i++ {
+			d := scalarDigit(scalars[i], start, c)
+			if d != 0 {
+				buckets[d-1] = g1MontAdd(m, &buckets[d-1], &points[i])
+			}
+		}
+		// Σ (j+1) * buckets[j], as the sum of the running sums
+		var sum, windowSum g1Mont
+		for j := len(buckets) - 1; j >= 0; j-- {
+			sum = g1MontAdd(m, &sum, &buckets[j])
+			windowSum = g1MontAdd(m, &windowSum, &sum)
+		}
+		acc = g1MontAdd(m, &acc, &windowSum)
+	}
+	return acc
+}
+
+// MultiExp returns Σ scalars[i] * points[i]. It is much faster than a
+// MulScalar and an Add for each point, but its running time depends on the
+// scalars, so it must not be used over secrets that have to be protected
+// from timing side channels (see MulScalarCT). It panics if the number of
+// points and scalars is different
+func (g1 G1) MultiExp(points [][3]*big.Int, scalars []*big.Int) [3]*big.Int {
+	checkMultiExpLen(len(points), len(scalars))
+	m := g1.F.Montgomery()
+	if m == nil {
+		q := [3]*big.Int{g1.F.Zero(), g1.F.Zero(), g1.F.Zero()}
+		for i := 0; i < len(points); i++ {
+			q = g1.Add(q, g1.MulScalar(points[i], scalars[i]))
+		}
+		return q
+	}
+	abs, nbits := multiExpScalars(scalars)
+	mps := make([]g1Mont, len(points))
+	for i := 0; i < len(points); i++ {
+		mps[i] = g1ToMont(m, points[i])
+	}
+	r := g1MontMultiExp(m, mps, abs, nbits)
+	return g1FromMont(m, &r)
+}
+
+// MultiExpParallel returns the same point than MultiExp, splitting the
+// points in the given number of goroutines
+func (g1 G1) MultiExpParallel(points [][3]*big.Int, scalars []*big.Int, routines int) [3]*big.Int {
+	checkMultiExpLen(len(points), len(scalars))
+	chunks := multiExpChunks(len(points), routines)
+	if len(chunks) == 1 {
+		return g1.MultiExp(points, scalars)
+	}
+	results := make([][3]*big.Int, len(chunks))
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, from, to int) {
+			defer wg.Done()
+			results[i] = g1.MultiExp(points[from:to], scalars[from:to])
+		}(i, chunk[0], chunk[1])
+	}
+	wg.Wait()
+	q := results[0]
+	for i := 1; i < len(results); i++ {
+		q = g1.Add(q, results[i])
+	}
+	return q
+}
+
+func (f *fq2Mont) g2MultiExp(points []g2Mont, scalars []*big.Int, nbits int) g2Mont {
+	c := multiExpWindow(len(points))
+	buckets := make([]g2Mont, 1<<uint(c)-1)
+	var acc g2Mont
+	for start := ((nbits+c-1)/c - 1) * c; start >= 0; start -= c {
+		for j := 0; j < c; j++ {
+			acc = f.g2Double(&acc)
+		}
+		for j := 0; j < len(buckets); j++ {
+			buckets[j] = g2Mont{}
+		}
+		for i := 0; i < len(points); i++ {
+			d := scalarDigit(scalars[i], start, c)
+			if d != 0 {
+				buckets[d-1] = f.g2Add(&buckets[d-1], &points[i])
+			}
+		}
+		var sum, windowSum g2Mont
+		for j := len(buckets) - 1; j >= 0; j-- {
+			sum = f.g2Add(&sum, &buckets[j])
+			windowSum = f.g2Add(&windowSum, &sum)
+		}
+		acc = f.g2Add(&acc, &windowSum)
+	}
+	return acc
+}
+
+// MultiExp returns Σ scalars[i] * points[i], as G1.MultiExp
+func (g2 G2) MultiExp(points [][3][2]*big.Int, scalars []*big.Int) [3][2]*big.Int {
+	checkMultiExpLen(len(points), len(scalars))
+	f := g2.montgomery()
+	if f == nil {
+		q := g2.Zero()
+		for i := 0; i < len(points); i++ {
+			q = g2.Add(q, g2.MulScalar(points[i], scalars[i]))
+		}
+		return q
+	}
+	abs, nbits := multiExpScalars(scalars)
+	mps := make([]g2Mont, len(points))
+	for i := 0; i < len(points); i++ {
+		mps[i] = f.g2ToMont(points[i])
+	}
+	r := f.g2MultiExp(mps, abs, nbits)
+	if f.isZero(&r[2]) {
+		return g2.Zero()
+	}
+	return f.g2FromMont(&r)
+}
+
+// MultiExpParallel returns the same point than MultiExp, splitting the
+// points in the given number of goroutines
+func (g2 G2) MultiExpParallel(points [][3][2]*big.Int, scalars []*big.Int, routines int) [3][2]*big.Int {
+	checkMultiExpLen(len(points), len(scalars))
+	chunks := multiExpChunks(len(points), routines)
+	if len(chunks) == 1 {
+		return g2.MultiExp(points, scalars)
+	}
+	results := make([][3][2]*big.Int, len(chunks))
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, from, to int) {
+			defer wg.Done()
+			results[i] = g2.MultiExp(points[from:to], scalars[from:to])
+		}(i, chunk[0], chunk[1])
+	}
+	wg.Wait()
+	q := results[0]
+	for i := 1; i < len(results); i++ {
+		q = g2.Add(q, results[i])
+	}
+	return q
+}
@@ -0,0 +1,96 @@
+package bn128
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/stretchr/testify/assert"
+)
+
+func randScalars(rnd *mrand.Rand, q *big.Int, n int) []*big.Int {
+	scalars := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		scalars[i] = new(big.Int).Rand(rnd, q)
+	}
+	return scalars
+}
+
+func TestG1MultiExp(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	g1Big := NewG1(fields.Fq{Q: bn128.Q}, bn128.Gg1)
+	rnd := mrand.New(mrand.NewSource(1))
+
+	for _, n := range []int{0, 1, 2, 7, 40, 300} {
+		points := make([][3]*big.Int, n)
+		for i := 0; i < n; i++ {
+			points[i] = bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(i%5+1)))
+		}
+		scalars := randScalars(rnd, bn128.R, n)
+		if n > 2 {
+			scalars[1] = big.NewInt(int64(0))
+			scalars[2] = new(big.Int).Add(bn128.R, big.NewInt(int64(3))) // over R
+		}
+		expected := [3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.Zero(), bn128.Fq1.Zero()}
+		for i := 0; i < n; i++ {
+			expected = bn128.G1.Add(expected, bn128.G1.MulScalar(points[i], scalars[i]))
+		}
+		assert.True(t, bn128.G1.Equal(expected, bn128.G1.MultiExp(points, scalars)))
+		assert.True(t, bn128.G1.Equal(expected, bn128.G1.MultiExpParallel(points, scalars, 4)))
+		assert.True(t, bn128.G1.Equal(expected, g1Big.MultiExp(points, scalars)))
+		assert.True(t, bn128.G1.Equal(expected, bn128.G1.MultiExpCT(points, scalars)))
+	}
+
+	assert.Panics(t, func() {
+		bn128.G1.MultiExp([][3]*big.Int{bn128.G1.G}, []*big.Int{})
+	})
+}
+
+func TestG2MultiExp(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	rnd := mrand.New(mrand.NewSource(2))
+
+	for _, n := range []int{0, 1, 9, 60} {
+		points := make([][3][2]*big.Int, n)
+		for i := 0; i < n; i++ {
+			points[i] = bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(i%3+1)))
+		}
+		scalars := randScalars(rnd, bn128.R, n)
+		expected := bn128.G2.Zero()
+		for i := 0; i < n; i++ {
+			expected = bn128.G2.Add(expected, bn128.G2.MulScalar(points[i], scalars[i]))
+		}
+		assert.True(t, bn128.G2.Equal(expected, bn128.G2.MultiExp(points, scalars)))
+		assert.True(t, bn128.G2.Equal(expected, bn128.G2.MultiExpParallel(points, scalars, 3)))
+		assert.True(t, bn128.G2.Equal(expected, bn128.G2.MultiExpCT(points, scalars)))
+	}
+}
+
+func BenchmarkG1MultiExp(b *testing.B) {
+	bn128, err := NewBn128()
+	assert.Nil(b, err)
+	rnd := mrand.New(mrand.NewSource(1))
+	n := 1000
+	points := make([][3]*big.Int, n)
+	for i := 0; i < n; i++ {
+		points[i] = bn128.G1.MulScalar(bn128.G1.G, new(big.Int).Rand(rnd, bn128.R))
+	}
+	scalars := randScalars(rnd, bn128.R, n)
+
+	b.Run("MulScalar", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			q := [3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.Zero(), bn128.Fq1.Zero()}
+			for j := 0; j < n; j++ {
+				q = bn128.G1.Add(q, bn128.G1.MulScalar(points[j], scalars[j]))
+			}
+		}
+	})
+	b.Run("MultiExp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bn128.G1.MultiExp(points, scalars)
+		}
+	})
+}
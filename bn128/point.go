@@ -0,0 +1,456 @@
+package bn128
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// G1Point, G2Point and GTElement are the values of G1, G2 and GT with their
+// operations as methods, so the points of the different groups (and the
+// field elements) can not be mixed up. The points created with G1.Point (or
+// G2.Point, NewGTElement) belong to that group, so they can also be values of
+// other curves that use the G1 and G2 operations (as the BLS12-381). The zero
+// values, and the values decoded with UnmarshalJSON, belong to the BN128
+// (G1.PointFromJSON and G2.PointFromJSON decode them in other groups). The old
+// array forms are available with the Array methods.
+
+var (
+	defaultBn128Once sync.Once
+	defaultBn128     Bn128
+)
+
+// defaultCurve returns the BN128 of the values that do not set their group
+func defaultCurve() *Bn128 {
+	defaultBn128Once.Do(func() {
+		var err error
+		defaultBn128, err = NewBn128()
+		if err != nil {
+			panic(err)
+		}
+	})
+	return &defaultBn128
+}
+
+// G1Point is a point of a G1 group in Jacobian coordinates. The zero value is
+// the point at infinity of the BN128 G1
+type G1Point struct {
+	p [3]*big.Int
+	g *G1
+}
+
+// Point returns the point p of the G1 as a G1Point
+func (g1 G1) Point(p [3]*big.Int) G1Point {
+	return G1Point{p, &g1}
+}
+
+// Points returns the points ps of the G1 as G1Points
+func (g1 G1) Points(ps [][3]*big.Int) []G1Point {
+	res := make([]G1Point, len(ps))
+	for i := 0; i < len(ps); i++ {
+		res[i] = G1Point{ps[i], &g1}
+	}
+	return res
+}
+
+// G1Arrays returns the array form of the points
+func G1Arrays(ps []G1Point) [][3]*big.Int {
+	res := make([][3]*big.Int, len(ps))
+	for i := 0; i < len(ps); i++ {
+		res[i] = ps[i].Array()
+	}
+	return res
+}
+
+func (p G1Point) group() *G1 {
+	if p.g == nil {
+		return &defaultCurve().G1
+	}
+	return p.g
+}
+
+func (p G1Point) with(q [3]*big.Int) G1Point {
+	return G1Point{q, p.g}
+}
+
+// Group returns the G1 of the point
+func (p G1Point) Group() G1 {
+	return *p.group()
+}
+
+// Array returns the Jacobian coordinates of the point
+func (p G1Point) Array() [3]*big.Int {
+	if p.p[2] == nil {
+		f := p.group().F
+		return [3]*big.Int{f.Zero(), f.Zero(), f.Zero()}
+	}
+	return p.p
+}
+
+// Add returns p + q
+func (p G1Point) Add(q G1Point) G1Point {
+	return p.with(p.group().Add(p.Array(), q.Array()))
+}
+
+// Sub returns p - q
+func (p G1Point) Sub(q G1Point) G1Point {
+	return p.with(p.group().Sub(p.Array(), q.Array()))
+}
+
+// Neg returns -p
+func (p G1Point) Neg() G1Point {
+	return p.with(p.group().Neg(p.Array()))
+}
+
+// Double returns 2p
+func (p G1Point) Double() G1Point {
+	return p.with(p.group().Double(p.Array()))
+}
+
+// ScalarMul returns e * p
+func (p G1Point) ScalarMul(e *big.Int) G1Point {
+	return p.with(p.group().MulScalar(p.Array(), e))
+}
+
+// IsZero returns true if p is the point at infinity
+func (p G1Point) IsZero() bool {
+	return p.group().IsZero(p.Array())
+}
+
+// Equal returns true if p and q are the same point, with any coordinates
+func (p G1Point) Equal(q G1Point) bool {
+	return p.group().Equal(p.Array(), q.Array())
+}
+
+// Affine returns the point with z = 1, or all zeros for the point at infinity
+func (p G1Point) Affine() G1Point {
+	return p.with(p.group().BatchAffine([][3]*big.Int{p.Array()})[0])
+}
+
+// Validate returns an InvalidPointError if p is not in the G1 subgroup
+func (p G1Point) Validate() error {
+	return p.group().Validate(p.Array())
+}
+
+// String returns the affine coordinates of the point
+func (p G1Point) String() string {
+	if p.IsZero() {
+		return "G1(infinity)"
+	}
+	a := p.Affine().p
+	return fmt.Sprintf("G1(%s, %s)", a[0], a[1])
+}
+
+// MarshalJSON encodes the point as the decimal strings of its affine
+// coordinates [x, y, 1], or [0, 0, 0] for the point at infinity
+func (p G1Point) MarshalJSON() ([]byte, error) {
+	a := p.Affine().p
+	return json.Marshal([3]string{a[0].String(), a[1].String(), a[2].String()})
+}
+
+// UnmarshalJSON decodes a point of the BN128 G1 encoded with MarshalJSON, as
+// G1.PointFromJSON
+func (p *G1Point) UnmarshalJSON(b []byte) error {
+	point, err := defaultCurve().G1.PointFromJSON(b)
+	if err != nil {
+		return err
+	}
+	*p = point
+	return nil
+}
+
+// PointFromJSON decodes a point of the G1 encoded with G1Point.MarshalJSON. It
+// returns an InvalidPointError if the point is not in the G1, or is the point
+// at infinity not encoded as [0, 0, 0]
+func (g1 G1) PointFromJSON(b []byte) (G1Point, error) {
+	var s [3]string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return G1Point{}, err
+	}
+	var q [3]*big.Int
+	for i := 0; i < 3; i++ {
+		var err error
+		if q[i], err = parseCoordinate(g1.F.Q, s[i]); err != nil {
+			return G1Point{}, err
+		}
+	}
+	if err := g1.CheckInfinity(q); err != nil {
+		return G1Point{}, err
+	}
+	point := g1.Point(q)
+	if err := point.Validate(); err != nil {
+		return G1Point{}, err
+	}
+	return point, nil
+}
+
+// G2Point is a point of a G2 group in Jacobian coordinates. The zero value is
+// the point at infinity of the BN128 G2
+type G2Point struct {
+	p [3][2]*big.Int
+	g *G2
+}
+
+// Point returns the point p of the G2 as a G2Point
+func (g2 G2) Point(p [3][2]*big.Int) G2Point {
+	return G2Point{p, &g2}
+}
+
+// Points returns the points ps of the G2 as G2Points
+func (g2 G2) Points(ps [][3][2]*big.Int) []G2Point {
+	res := make([]G2Point, len(ps))
+	for i := 0; i < len(ps); i++ {
+		res[i] = G2Point{ps[i], &g2}
+	}
+	return res
+}
+
+// G2Arrays returns the array form of the points
+func G2Arrays(ps []G2Point) [][3][2]*big.Int {
+	res := make([][3][2]*big.Int, len(ps))
+	for i := 0; i < len(ps); i++ {
+		res[i] = ps[i].Array()
+	}
+	return res
+}
+
+func (p G2Point) group() *G2 {
+	if p.g == nil {
+		return &defaultCurve().G2
+	}
+	return p.g
+}
+
+func (p G2Point) with(q [3][2]*big.Int) G2Point {
+	return G2Point{q, p.g}
+}
+
+// Group returns the G2 of the point
+func (p G2Point) Group() G2 {
+	return *p.group()
+}
+
+// Array returns the Jacobian coordinates of the point
+func (p G2Point) Array() [3][2]*big.Int {
+	if p.p[2][0] == nil {
+		return p.group().Zero()
+	}
+	return p.p
+}
+
+// Add returns p + q
+func (p G2Point) Add(q G2Point) G2Point {
+	return p.with(p.group().Add(p.Array(), q.Array()))
+}
+
+// Sub returns p - q
+func (p G2Point) Sub(q G2Point) G2Point {
+	return p.with(p.group().Sub(p.Array(), q.Array()))
+}
+
+// Neg returns -p
+func (p G2Point) Neg() G2Point {
+	return p.with(p.group().Neg(p.Array()))
+}
+
+// Double returns 2p
+func (p G2Point) Double() G2Point {
+	return p.with(p.group().Double(p.Array()))
+}
+
+// ScalarMul returns e * p
+func (p G2Point) ScalarMul(e *big.Int) G2Point {
+	return p.with(p.group().MulScalar(p.Array(), e))
+}
+
+// IsZero returns true if p is the point at infinity
+func (p G2Point) IsZero() bool {
+	return p.group().IsZero(p.Array())
+}
+
+// Equal returns true if p and q are the same point, with any coordinates
+func (p G2Point) Equal(q G2Point) bool {
+	return p.group().Equal(p.Array(), q.Array())
+}
+
+// Affine returns the point with z = 1, or G2.Zero() for the point at infinity
+func (p G2Point) Affine() G2Point {
+	return p.with(p.group().Affine(p.Array()))
+}
+
+// Validate returns an InvalidPointError if p is not in G2
+func (p G2Point) Validate() error {
+	return p.group().Validate(p.Array())
+}
+
+// String returns the affine coordinates of the point
+func (p G2Point) String() string {
+	if p.IsZero() {
+		return "G2(infinity)"
+	}
+	a := p.Affine().p
+	return fmt.Sprintf("G2((%s, %s), (%s, %s))", a[0][0], a[0][1], a[1][0], a[1][1])
+}
+
+// MarshalJSON encodes the point as the decimal strings of its affine
+// coordinates [[x0, x1], [y0, y1], [1, 0]], or the ones of G2.Zero() for the
+// point at infinity
+func (p G2Point) MarshalJSON() ([]byte, error) {
+	a := p.Affine().p
+	var s [3][2]string
+	for i := 0; i < 3; i++ {
+		s[i] = [2]string{a[i][0].String(), a[i][1].String()}
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON decodes a point of the BN128 G2 encoded with MarshalJSON, as
+// G2.PointFromJSON
+func (p *G2Point) UnmarshalJSON(b []byte) error {
+	point, err := defaultCurve().G2.PointFromJSON(b)
+	if err != nil {
+		return err
+	}
+	*p = point
+	return nil
+}
+
+// PointFromJSON decodes a point of the G2 encoded with G2Point.MarshalJSON. It
+// returns an InvalidPointError if the point is not in the G2, or is the point
+// at infinity not encoded as G2.Zero()
+func (g2 G2) PointFromJSON(b []byte) (G2Point, error) {
+	var s [3][2]string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return G2Point{}, err
+	}
+	var q [3][2]*big.Int
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 2; j++ {
+			var err error
+			if q[i][j], err = parseCoordinate(g2.F.F.Q, s[i][j]); err != nil {
+				return G2Point{}, err
+			}
+		}
+	}
+	if err := g2.CheckInfinity(q); err != nil {
+		return G2Point{}, err
+	}
+	point := g2.Point(q)
+	if err := point.Validate(); err != nil {
+		return G2Point{}, err
+	}
+	return point, nil
+}
+
+// GTElement is an element of the target group GT of the pairing, in the
+// Fq12. The zero value is the identity of the BN128 GT
+type GTElement struct {
+	a [2][3][2]*big.Int
+	f *fields.Fq12
+}
+
+// NewGTElement returns the element a of the Fq12 as a GTElement
+func NewGTElement(f fields.Fq12, a [2][3][2]*big.Int) GTElement {
+	return GTElement{a, &f}
+}
+
+func (e GTElement) field() *fields.Fq12 {
+	if e.f == nil {
+		return &defaultCurve().Fq12
+	}
+	return e.f
+}
+
+func (e GTElement) with(a [2][3][2]*big.Int) GTElement {
+	return GTElement{a, e.f}
+}
+
+// Array returns the Fq12 coordinates of the element
+func (e GTElement) Array() [2][3][2]*big.Int {
+	if e.a[0][0][0] == nil {
+		return e.field().One()
+	}
+	return e.a
+}
+
+// Mul returns e * o
+func (e GTElement) Mul(o GTElement) GTElement {
+	return e.with(e.field().Mul(e.Array(), o.Array()))
+}
+
+// Exp returns e^k
+func (e GTElement) Exp(k *big.Int) GTElement {
+	return e.with(e.field().Exp(e.Array(), k))
+}
+
+// Inverse returns e^-1
+func (e GTElement) Inverse() GTElement {
+	return e.with(e.field().Inverse(e.Array()))
+}
+
+// IsOne returns true if e is the identity
+func (e GTElement) IsOne() bool {
+	return e.field().Equal(e.Array(), e.field().One())
+}
+
+// Equal returns true if e and o are the same element
+func (e GTElement) Equal(o GTElement) bool {
+	return e.field().Equal(e.Array(), o.Array())
+}
+
+// String returns the coordinates of the element
+func (e GTElement) String() string {
+	return fmt.Sprintf("GT%v", e.field().Affine(e.Array()))
+}
+
+// MarshalJSON encodes the element as the decimal strings of its coordinates
+func (e GTElement) MarshalJSON() ([]byte, error) {
+	a := e.field().Affine(e.Array())
+	var s [2][3][2]string
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			s[i][j] = [2]string{a[i][j][0].String(), a[i][j][1].String()}
+		}
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON decodes an element of the BN128 GT encoded with MarshalJSON.
+// It only checks that the coordinates are in the Fq
+func (e *GTElement) UnmarshalJSON(b []byte) error {
+	var s [2][3][2]string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	var a [2][3][2]*big.Int
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 2; k++ {
+				var err error
+				if a[i][j][k], err = parseCoordinate(defaultCurve().Q, s[i][j][k]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	*e = NewGTElement(defaultCurve().Fq12, a)
+	return nil
+}
+
+// parseCoordinate parses a decimal coordinate of the Fq over q
+func parseCoordinate(q *big.Int, s string) (*big.Int, error) {
+	a, ok := new(big.Int).SetString(s, 10)
+	if !ok || a.Sign() < 0 || a.Cmp(q) >= 0 {
+		return nil, errors.New("invalid coordinate " + s)
+	}
+	return a, nil
+}
+
+// PairingPoints returns the pairing e(p, q) of points of the BN128
+func (bn128 Bn128) PairingPoints(p G1Point, q G2Point) GTElement {
+	return NewGTElement(bn128.Fq12, bn128.Pairing(p.Array(), q.Array()))
+}
@@ -0,0 +1,113 @@
+package bn128
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestG1Point(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	g := bn128.G1.Point(bn128.G1.G)
+	p := g.ScalarMul(big.NewInt(int64(5)))
+	q := g.ScalarMul(big.NewInt(int64(3)))
+	assert.True(t, p.Add(q).Equal(g.ScalarMul(big.NewInt(int64(8)))))
+	assert.True(t, p.Sub(q).Equal(g.Double()))
+	assert.True(t, p.Add(p.Neg()).IsZero())
+	assert.True(t, bn128.G1.Equal(p.Array(), bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(5)))))
+	assert.Equal(t, 0, p.Affine().Array()[2].Cmp(big.NewInt(int64(1))))
+
+	// the zero value is the point at infinity of the BN128
+	var zero G1Point
+	assert.True(t, zero.IsZero())
+	assert.True(t, zero.Add(p).Equal(p))
+	assert.Equal(t, "G1(infinity)", zero.String())
+
+	b, err := json.Marshal(p)
+	assert.Nil(t, err)
+	var p2 G1Point
+	assert.Nil(t, json.Unmarshal(b, &p2))
+	assert.True(t, p.Equal(p2))
+	assert.Equal(t, p.String(), p2.String())
+	b, err = json.Marshal(zero)
+	assert.Nil(t, err)
+	assert.Equal(t, `["0","0","0"]`, string(b))
+	assert.Nil(t, json.Unmarshal(b, &p2))
+	assert.True(t, p2.IsZero())
+
+	err = json.Unmarshal([]byte(`["1","3","1"]`), &p2)
+	assert.True(t, errors.Is(err, ErrNotOnCurve))
+	assert.NotNil(t, json.Unmarshal([]byte(`["1","-2","1"]`), &p2))
+	// the point at infinity only has the encoding [0, 0, 0]
+	err = json.Unmarshal([]byte(`["1","3","0"]`), &p2)
+	assert.True(t, errors.Is(err, ErrNonCanonicalInfinity))
+
+	ps := bn128.G1.Points([][3]*big.Int{bn128.G1.G, p.Array()})
+	assert.Equal(t, [][3]*big.Int{bn128.G1.G, p.Array()}, G1Arrays(ps))
+}
+
+func TestG2Point(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	g := bn128.G2.Point(bn128.G2.G)
+	p := g.ScalarMul(big.NewInt(int64(5)))
+	q := g.ScalarMul(big.NewInt(int64(3)))
+	assert.True(t, p.Add(q).Equal(g.ScalarMul(big.NewInt(int64(8)))))
+	assert.True(t, p.Sub(q).Equal(g.Double()))
+	assert.True(t, p.Add(p.Neg()).IsZero())
+
+	var zero G2Point
+	assert.True(t, zero.IsZero())
+	assert.True(t, zero.Add(p).Equal(p))
+
+	b, err := json.Marshal(p)
+	assert.Nil(t, err)
+	var p2 G2Point
+	assert.Nil(t, json.Unmarshal(b, &p2))
+	assert.True(t, p.Equal(p2))
+	b, err = json.Marshal(zero)
+	assert.Nil(t, err)
+	assert.Equal(t, `[["0","0"],["1","0"],["0","0"]]`, string(b))
+	assert.Nil(t, json.Unmarshal(b, &p2))
+	assert.True(t, p2.IsZero())
+
+	err = json.Unmarshal([]byte(`[["1","0"],["3","0"],["1","0"]]`), &p2)
+	assert.True(t, errors.Is(err, ErrNotOnCurve))
+	// the point at infinity only has the encoding of G2.Zero()
+	err = json.Unmarshal([]byte(`[["0","0"],["0","0"],["0","0"]]`), &p2)
+	assert.True(t, errors.Is(err, ErrNonCanonicalInfinity))
+
+	ps := bn128.G2.Points([][3][2]*big.Int{bn128.G2.G})
+	assert.Equal(t, [][3][2]*big.Int{bn128.G2.G}, G2Arrays(ps))
+}
+
+func TestGTElement(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	g1 := bn128.G1.Point(bn128.G1.G)
+	g2 := bn128.G2.Point(bn128.G2.G)
+	e := bn128.PairingPoints(g1, g2)
+	assert.False(t, e.IsOne())
+	a := big.NewInt(int64(6))
+	assert.True(t, bn128.PairingPoints(g1.ScalarMul(a), g2).Equal(e.Exp(a)))
+	assert.True(t, e.Mul(e.Inverse()).IsOne())
+	assert.True(t, e.Exp(bn128.R).IsOne())
+
+	var one GTElement
+	assert.True(t, one.IsOne())
+	assert.True(t, one.Mul(e).Equal(e))
+
+	b, err := json.Marshal(e)
+	assert.Nil(t, err)
+	var e2 GTElement
+	assert.Nil(t, json.Unmarshal(b, &e2))
+	assert.True(t, e.Equal(e2))
+	assert.Equal(t, e.String(), e2.String())
+}
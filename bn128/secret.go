@@ -0,0 +1,86 @@
+package bn128
+
+import (
+	"math/big"
+	"runtime"
+)
+
+// The functions in this file multiply by the secret scalars of the trusted
+// setups and of the provers. When ct is set they use the constant-time
+// ladder of consttime.go, otherwise the faster variable-time algorithms.
+
+// MulScalarSecret multiplies the point by a secret scalar, with MulScalarCT
+// if ct is set, or with MulScalar otherwise
+func (g1 G1) MulScalarSecret(p [3]*big.Int, e *big.Int, ct bool) [3]*big.Int {
+	if ct {
+		return g1.MulScalarCT(p, e)
+	}
+	return g1.MulScalar(p, e)
+}
+
+// MultiExpSecret returns Σ scalars[i] * points[i] for secret scalars, with
+// MultiExpCT if ct is set, or with a MultiExpParallel over all the CPUs
+// otherwise
+func (g1 G1) MultiExpSecret(points [][3]*big.Int, scalars []*big.Int, ct bool) [3]*big.Int {
+	if ct {
+		return g1.MultiExpCT(points, scalars)
+	}
+	return g1.MultiExpParallel(points, scalars, runtime.NumCPU())
+}
+
+// MulScalarSecret multiplies a point of G2 by a secret scalar, as
+// G1.MulScalarSecret
+func (g2 G2) MulScalarSecret(p [3][2]*big.Int, e *big.Int, ct bool) [3][2]*big.Int {
+	if ct {
+		return g2.MulScalarCT(p, e)
+	}
+	return g2.MulScalar(p, e)
+}
+
+// MultiExpSecret returns Σ scalars[i] * points[i] for secret scalars, as
+// G1.MultiExpSecret
+func (g2 G2) MultiExpSecret(points [][3][2]*big.Int, scalars []*big.Int, ct bool) [3][2]*big.Int {
+	if ct {
+		return g2.MultiExpCT(points, scalars)
+	}
+	return g2.MultiExpParallel(points, scalars, runtime.NumCPU())
+}
+
+// GeneratorMul multiplies the generators of a G1 and a G2 by secret scalars,
+// as a trusted setup does. Without ct it uses fixed-base tables of the
+// generators. With ct it uses MulScalarCT, as the running time of the table
+// lookups depends on the scalars
+type GeneratorMul struct {
+	g1 G1
+	g2 G2
+	ct bool
+	t1 *FixedBaseTable
+	t2 *G2FixedBaseTable
+}
+
+// NewGeneratorMul returns the GeneratorMul of the generators of g1 and g2,
+// building their tables if ct is not set
+func NewGeneratorMul(g1 G1, g2 G2, ct bool) *GeneratorMul {
+	gm := &GeneratorMul{g1: g1, g2: g2, ct: ct}
+	if !ct {
+		gm.t1 = g1.NewFixedBaseTable(g1.G)
+		gm.t2 = g2.NewFixedBaseTable(g2.G)
+	}
+	return gm
+}
+
+// G1 returns the G1 generator multiplied by the secret scalar
+func (gm *GeneratorMul) G1(e *big.Int) [3]*big.Int {
+	if gm.ct {
+		return gm.g1.MulScalarCT(gm.g1.G, e)
+	}
+	return gm.t1.Mul(e)
+}
+
+// G2 returns the G2 generator multiplied by the secret scalar
+func (gm *GeneratorMul) G2(e *big.Int) [3][2]*big.Int {
+	if gm.ct {
+		return gm.g2.MulScalarCT(gm.g2.G, e)
+	}
+	return gm.t2.Mul(e)
+}
@@ -0,0 +1,30 @@
+package bn128
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretMul(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	p1 := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(33)))
+	p2 := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(33)))
+	e := new(big.Int).Sub(bn128.R, big.NewInt(int64(987654321)))
+	es := []*big.Int{e, big.NewInt(int64(12))}
+
+	assert.True(t, bn128.G1.Equal(bn128.G1.MulScalarSecret(p1, e, false), bn128.G1.MulScalarSecret(p1, e, true)))
+	assert.True(t, bn128.G2.Equal(bn128.G2.MulScalarSecret(p2, e, false), bn128.G2.MulScalarSecret(p2, e, true)))
+	ps1 := [][3]*big.Int{p1, bn128.G1.G}
+	ps2 := [][3][2]*big.Int{p2, bn128.G2.G}
+	assert.True(t, bn128.G1.Equal(bn128.G1.MultiExpSecret(ps1, es, false), bn128.G1.MultiExpSecret(ps1, es, true)))
+	assert.True(t, bn128.G2.Equal(bn128.G2.MultiExpSecret(ps2, es, false), bn128.G2.MultiExpSecret(ps2, es, true)))
+
+	for _, ct := range []bool{false, true} {
+		gm := NewGeneratorMul(bn128.G1, bn128.G2, ct)
+		assert.True(t, bn128.G1.Equal(bn128.G1.MulScalar(bn128.G1.G, e), gm.G1(e)))
+		assert.True(t, bn128.G2.Equal(bn128.G2.MulScalar(bn128.G2.G, e), gm.G2(e)))
+	}
+}
@@ -0,0 +1,140 @@
+package bn128
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	// ErrNotOnCurve is the reason of an InvalidPointError for the points that
+	// do not satisfy the curve equation
+	ErrNotOnCurve = errors.New("point not on the curve")
+	// ErrNotInSubgroup is the reason of an InvalidPointError for the points
+	// that are on the curve (or the twist) but not in the subgroup of order R
+	ErrNotInSubgroup = errors.New("point not in the subgroup of order R")
+	// ErrNonCanonicalInfinity is the reason of an InvalidPointError for the
+	// decoded points at infinity that are not encoded as the canonical one,
+	// [0, 0, 0] in G1 and G2.Zero() in G2. All the points with z = 0 are the
+	// point at infinity, so without it a point would have many encodings
+	ErrNonCanonicalInfinity = errors.New("non canonical encoding of the point at infinity")
+)
+
+// InvalidPointError is the error returned when a point is not a valid element
+// of G1 or G2. Err is ErrNotOnCurve, ErrNotInSubgroup or
+// ErrNonCanonicalInfinity, so it can be checked with errors.Is
+type InvalidPointError struct {
+	Group string // "G1" or "G2"
+	Err   error
+}
+
+func (e *InvalidPointError) Error() string {
+	return "invalid " + e.Group + " point: " + e.Err.Error()
+}
+
+func (e *InvalidPointError) Unwrap() error {
+	return e.Err
+}
+
+// IsOnCurve returns true if the point satisfies the curve equation
+// y^2 = x^3 + b, in Jacobian coordinates Y^2 = X^3 + b*Z^6. The point at
+// infinity is on the curve
+func (g1 G1) IsOnCurve(p [3]*big.Int) bool {
+	if g1.IsZero(p) {
+		return true
+	}
+	z2 := g1.F.Square(p[2])
+	z6 := g1.F.Mul(g1.F.Square(z2), z2)
+	y2 := g1.F.Square(p[1])
+	x3 := g1.F.Mul(g1.F.Square(p[0]), p[0])
+	return g1.F.Equal(y2, g1.F.Add(x3, g1.F.Mul(g1.coefB(), z6)))
+}
+
+// IsInSubgroup returns true if the point is on the curve and, when the order
+// R of G1 is set (for the curves with a cofactor), R*p is the point at
+// infinity. For the BN128 the cofactor is 1, so all the points on the curve
+// are in G1
+func (g1 G1) IsInSubgroup(p [3]*big.Int) bool {
+	if !g1.IsOnCurve(p) {
+		return false
+	}
+	if g1.R == nil {
+		return true
+	}
+	return g1.IsZero(g1.mulScalar(p, g1.R))
+}
+
+// Validate returns an InvalidPointError if the point is not on the curve, or
+// is not in the subgroup of order R (see IsInSubgroup)
+func (g1 G1) Validate(p [3]*big.Int) error {
+	if !g1.IsOnCurve(p) {
+		return &InvalidPointError{"G1", ErrNotOnCurve}
+	}
+	if !g1.IsInSubgroup(p) {
+		return &InvalidPointError{"G1", ErrNotInSubgroup}
+	}
+	return nil
+}
+
+// CheckInfinity returns an InvalidPointError with ErrNonCanonicalInfinity if
+// the point is at infinity and is not [0, 0, 0]. The decoders use it, so each
+// point has a single encoding
+func (g1 G1) CheckInfinity(p [3]*big.Int) error {
+	if g1.IsZero(p) && (!g1.F.IsZero(p[0]) || !g1.F.IsZero(p[1])) {
+		return &InvalidPointError{"G1", ErrNonCanonicalInfinity}
+	}
+	return nil
+}
+
+// CheckInfinity returns an InvalidPointError with ErrNonCanonicalInfinity if
+// the point is at infinity and is not G2.Zero(), as G1.CheckInfinity
+func (g2 G2) CheckInfinity(p [3][2]*big.Int) error {
+	if g2.IsZero(p) && (!g2.F.IsZero(p[0]) || !g2.F.Equal(p[1], g2.F.One())) {
+		return &InvalidPointError{"G2", ErrNonCanonicalInfinity}
+	}
+	return nil
+}
+
+// IsOnCurve returns true if the point satisfies the twist equation
+// y^2 = x^3 + b, in Jacobian coordinates. The point at infinity is on the twist
+func (g2 G2) IsOnCurve(p [3][2]*big.Int) bool {
+	if g2.IsZero(p) {
+		return true
+	}
+	z2 := g2.F.Square(p[2])
+	z6 := g2.F.Mul(g2.F.Square(z2), z2)
+	y2 := g2.F.Square(p[1])
+	x3 := g2.F.Mul(g2.F.Square(p[0]), p[0])
+	return g2.F.Equal(y2, g2.F.Add(x3, g2.F.Mul(g2.coefB(), z6)))
+}
+
+// IsInSubgroup returns true if the point is on the twist and R*p is the point
+// at infinity. The twist has a large cofactor, so the points on it are not
+// always in G2. When the G2 has the endomorphism ψ (set by NewBn128) the check
+// uses it, otherwise it needs the order R of the G2, and returns false when it
+// is not set
+func (g2 G2) IsInSubgroup(p [3][2]*big.Int) bool {
+	if !g2.IsOnCurve(p) {
+		return false
+	}
+	if g2.gls != nil {
+		f := g2.montgomery()
+		mp := f.g2ToMont(p)
+		return g2.gls.isInSubgroup(f, &mp)
+	}
+	if g2.R == nil {
+		return false
+	}
+	return g2.IsZero(g2.mulScalar(p, g2.R))
+}
+
+// Validate returns an InvalidPointError if the point is not on the twist, or
+// is not in the subgroup of order R
+func (g2 G2) Validate(p [3][2]*big.Int) error {
+	if !g2.IsOnCurve(p) {
+		return &InvalidPointError{"G2", ErrNotOnCurve}
+	}
+	if !g2.IsInSubgroup(p) {
+		return &InvalidPointError{"G2", ErrNotInSubgroup}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package bn128
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// twistPointNotInG2 returns a point on the twist that is not in G2
+func twistPointNotInG2(bn128 Bn128) [3][2]*big.Int {
+	for i := int64(1); ; i++ {
+		x := [2]*big.Int{big.NewInt(i), bn128.Fq1.Zero()}
+		y2 := bn128.Fq2.Add(bn128.Fq2.Mul(bn128.Fq2.Square(x), x), bn128.G2.coefB())
+		if y, ok := bn128.Fq2.Sqrt(y2); ok {
+			return [3][2]*big.Int{x, y, bn128.Fq2.One()}
+		}
+	}
+}
+
+func TestG1IsOnCurve(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	assert.True(t, bn128.G1.IsOnCurve(bn128.G1.G))
+	p := bn128.G1.MulScalar(bn128.G1.G, big.NewInt(int64(1234)))
+	assert.True(t, bn128.G1.IsOnCurve(p))
+	assert.Nil(t, bn128.G1.Validate(p))
+	zero := [3]*big.Int{bn128.Fq1.Zero(), bn128.Fq1.Zero(), bn128.Fq1.Zero()}
+	assert.True(t, bn128.G1.IsOnCurve(zero))
+
+	bad := [3]*big.Int{big.NewInt(int64(1)), big.NewInt(int64(3)), bn128.Fq1.One()}
+	assert.False(t, bn128.G1.IsOnCurve(bad))
+	err = bn128.G1.Validate(bad)
+	assert.True(t, errors.Is(err, ErrNotOnCurve))
+	var invalid *InvalidPointError
+	assert.True(t, errors.As(err, &invalid))
+	assert.Equal(t, "G1", invalid.Group)
+
+	b := append(bn128.Fq1.MarshalBinary(bad[0]), bn128.Fq1.MarshalBinary(bad[1])...)
+	_, err = bn128.G1.UnmarshalBinary(b)
+	assert.True(t, errors.Is(err, ErrNotOnCurve))
+}
+
+func TestG2IsInSubgroup(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+
+	assert.True(t, bn128.G2.IsOnCurve(bn128.G2.G))
+	assert.True(t, bn128.G2.IsInSubgroup(bn128.G2.G))
+	p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(int64(1234)))
+	assert.True(t, bn128.G2.IsOnCurve(p))
+	assert.True(t, bn128.G2.IsInSubgroup(p))
+	assert.Nil(t, bn128.G2.Validate(p))
+	assert.True(t, bn128.G2.IsInSubgroup(bn128.G2.Zero()))
+
+	bad := [3][2]*big.Int{bn128.G2.G[0], bn128.G2.G[0], bn128.Fq2.One()}
+	assert.False(t, bn128.G2.IsOnCurve(bad))
+	assert.True(t, errors.Is(bn128.G2.Validate(bad), ErrNotOnCurve))
+
+	q := twistPointNotInG2(bn128)
+	assert.True(t, bn128.G2.IsOnCurve(q))
+	assert.False(t, bn128.G2.IsInSubgroup(q))
+	err = bn128.G2.Validate(q)
+	assert.True(t, errors.Is(err, ErrNotInSubgroup))
+
+	_, err = bn128.G2.UnmarshalBinary(bn128.G2.MarshalBinary(q))
+	assert.True(t, errors.Is(err, ErrNotInSubgroup))
+	_, err = bn128.G2.Decompress(bn128.G2.Compress(q))
+	assert.True(t, errors.Is(err, ErrNotInSubgroup))
+}
+
+func TestG2IsInSubgroupPsi(t *testing.T) {
+	bn128, err := NewBn128()
+	assert.Nil(t, err)
+	assert.NotNil(t, bn128.G2.gls)
+
+	// the ψ check gives the same result than the multiplication by R, for
+	// points in G2, points out of it, and their sums
+	q := twistPointNotInG2(bn128)
+	for i := int64(1); i < 8; i++ {
+		p := bn128.G2.MulScalar(bn128.G2.G, big.NewInt(i*7919))
+		qi := bn128.G2.mulScalar(q, big.NewInt(i))
+		for _, a := range [][3][2]*big.Int{p, qi, bn128.G2.Add(p, qi)} {
+			expected := bn128.G2.IsZero(bn128.G2.mulScalar(a, bn128.R))
+			assert.Equal(t, expected, bn128.G2.IsInSubgroup(a))
+		}
+		assert.True(t, bn128.G2.IsInSubgroup(p))
+		assert.False(t, bn128.G2.IsInSubgroup(bn128.G2.Add(p, qi)))
+	}
+}
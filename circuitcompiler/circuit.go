@@ -2,8 +2,11 @@ package circuitcompiler
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/arnaucube/go-snark/r1csqap"
 )
@@ -18,11 +21,7 @@ type Circuit struct {
 	Signals       []string
 	Witness       []*big.Int
 	Constraints   []Constraint
-	R1CS          struct {
-		A [][]*big.Int
-		B [][]*big.Int
-		C [][]*big.Int
-	}
+	R1CS          r1csqap.SparseR1CS
 }
 
 // Constraint is the data structure of a flat code operation
@@ -53,46 +52,84 @@ func isValue(a string) (bool, int) {
 	}
 	return true, v
 }
-func insertVar(arr []*big.Int, signals []string, v string, used map[string]bool) ([]*big.Int, map[string]bool) {
+func insertVar(lc r1csqap.LinearCombination, signals []string, v string, used map[string]bool) (r1csqap.LinearCombination, map[string]bool) {
 	isVal, value := isValue(v)
 	valueBigInt := big.NewInt(int64(value))
 	if isVal {
-		arr[0] = new(big.Int).Add(arr[0], valueBigInt)
+		lc = addTerm(lc, 0, valueBigInt)
 	} else {
 		if !used[v] {
 			panic(errors.New("using variable before it's set"))
 		}
-		arr[indexInArray(signals, v)] = new(big.Int).Add(arr[indexInArray(signals, v)], big.NewInt(int64(1)))
+		lc = addTerm(lc, indexInArray(signals, v), big.NewInt(int64(1)))
 	}
-	return arr, used
+	return lc, used
 }
-func insertVarNeg(arr []*big.Int, signals []string, v string, used map[string]bool) ([]*big.Int, map[string]bool) {
+func insertVarNeg(lc r1csqap.LinearCombination, signals []string, v string, used map[string]bool) (r1csqap.LinearCombination, map[string]bool) {
 	isVal, value := isValue(v)
 	valueBigInt := big.NewInt(int64(value))
 	if isVal {
-		arr[0] = new(big.Int).Add(arr[0], valueBigInt)
+		lc = addTerm(lc, 0, valueBigInt)
 	} else {
 		if !used[v] {
 			panic(errors.New("using variable before it's set"))
 		}
-		arr[indexInArray(signals, v)] = new(big.Int).Add(arr[indexInArray(signals, v)], big.NewInt(int64(-1)))
+		lc = addTerm(lc, indexInArray(signals, v), big.NewInt(int64(-1)))
+	}
+	return lc, used
+}
+
+// addTerm adds coeff to the coefficient of the signal in the linear
+// combination
+func addTerm(lc r1csqap.LinearCombination, signal int, coeff *big.Int) r1csqap.LinearCombination {
+	for i := 0; i < len(lc); i++ {
+		if lc[i].Signal == signal {
+			lc[i].Coeff = new(big.Int).Add(lc[i].Coeff, coeff)
+			return lc
+		}
+	}
+	return append(lc, r1csqap.Term{Signal: signal, Coeff: coeff})
+}
+
+// setTerm sets the coefficient of the signal in the linear combination
+func setTerm(lc r1csqap.LinearCombination, signal int, coeff *big.Int) r1csqap.LinearCombination {
+	for i := 0; i < len(lc); i++ {
+		if lc[i].Signal == signal {
+			lc[i].Coeff = coeff
+			return lc
+		}
 	}
-	return arr, used
+	return append(lc, r1csqap.Term{Signal: signal, Coeff: coeff})
 }
 
-// GenerateR1CS generates the R1CS polynomials from the Circuit
+// normalize returns the terms of the linear combination sorted by signal,
+// without the zero coefficients, as in r1csqap.NewSparseR1CS
+func normalize(lc r1csqap.LinearCombination) r1csqap.LinearCombination {
+	var r r1csqap.LinearCombination
+	for _, t := range lc {
+		if t.Coeff.Sign() != 0 {
+			r = append(r, t)
+		}
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Signal < r[j].Signal })
+	return r
+}
+
+// GenerateR1CS generates the R1CS polynomials from the Circuit, as
+// GenerateSparseR1CS, and returns its dense matrices
 func (circ *Circuit) GenerateR1CS() ([][]*big.Int, [][]*big.Int, [][]*big.Int) {
-	// from flat code to R1CS
+	return circ.GenerateSparseR1CS().Dense()
+}
 
-	var a [][]*big.Int
-	var b [][]*big.Int
-	var c [][]*big.Int
+// GenerateSparseR1CS generates the R1CS from the Circuit, with only the non
+// zero terms of each constraint, and sets it in circ.R1CS
+func (circ *Circuit) GenerateSparseR1CS() r1csqap.SparseR1CS {
+	// from flat code to R1CS
+	r := r1csqap.SparseR1CS{NSignals: len(circ.Signals)}
 
 	used := make(map[string]bool)
 	for _, constraint := range circ.Constraints {
-		aConstraint := r1csqap.ArrayOfBigZeros(len(circ.Signals))
-		bConstraint := r1csqap.ArrayOfBigZeros(len(circ.Signals))
-		cConstraint := r1csqap.ArrayOfBigZeros(len(circ.Signals))
+		var aConstraint, bConstraint, cConstraint r1csqap.LinearCombination
 
 		// if existInArray(constraint.Out) {
 		// if used[constraint.Out] {
@@ -100,42 +137,82 @@ func (circ *Circuit) GenerateR1CS() ([][]*big.Int, [][]*big.Int, [][]*big.Int) {
 		// }
 		used[constraint.Out] = true
 		if constraint.Op == "in" {
-			for i := 0; i <= len(circ.PublicInputs); i++ {
-				aConstraint[indexInArray(circ.Signals, constraint.Out)] = new(big.Int).Add(aConstraint[indexInArray(circ.Signals, constraint.Out)], big.NewInt(int64(1)))
-				aConstraint, used = insertVar(aConstraint, circ.Signals, constraint.Out, used)
-				bConstraint[0] = big.NewInt(int64(1))
-			}
+			// the inputs have no constraint
 			continue
 
 		} else if constraint.Op == "+" {
-			cConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
+			cConstraint = setTerm(cConstraint, indexInArray(circ.Signals, constraint.Out), big.NewInt(int64(1)))
 			aConstraint, used = insertVar(aConstraint, circ.Signals, constraint.V1, used)
 			aConstraint, used = insertVar(aConstraint, circ.Signals, constraint.V2, used)
-			bConstraint[0] = big.NewInt(int64(1))
+			bConstraint = setTerm(bConstraint, 0, big.NewInt(int64(1)))
 		} else if constraint.Op == "-" {
-			cConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
+			cConstraint = setTerm(cConstraint, indexInArray(circ.Signals, constraint.Out), big.NewInt(int64(1)))
 			aConstraint, used = insertVarNeg(aConstraint, circ.Signals, constraint.V1, used)
 			aConstraint, used = insertVarNeg(aConstraint, circ.Signals, constraint.V2, used)
-			bConstraint[0] = big.NewInt(int64(1))
+			bConstraint = setTerm(bConstraint, 0, big.NewInt(int64(1)))
 		} else if constraint.Op == "*" {
-			cConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
+			cConstraint = setTerm(cConstraint, indexInArray(circ.Signals, constraint.Out), big.NewInt(int64(1)))
 			aConstraint, used = insertVar(aConstraint, circ.Signals, constraint.V1, used)
 			bConstraint, used = insertVar(bConstraint, circ.Signals, constraint.V2, used)
 		} else if constraint.Op == "/" {
 			cConstraint, used = insertVar(cConstraint, circ.Signals, constraint.V1, used)
-			cConstraint[indexInArray(circ.Signals, constraint.Out)] = big.NewInt(int64(1))
+			cConstraint = setTerm(cConstraint, indexInArray(circ.Signals, constraint.Out), big.NewInt(int64(1)))
 			bConstraint, used = insertVar(bConstraint, circ.Signals, constraint.V2, used)
 		}
 
-		a = append(a, aConstraint)
-		b = append(b, bConstraint)
-		c = append(c, cConstraint)
+		r.A = append(r.A, normalize(aConstraint))
+		r.B = append(r.B, normalize(bConstraint))
+		r.C = append(r.C, normalize(cConstraint))
 
 	}
-	circ.R1CS.A = a
-	circ.R1CS.B = b
-	circ.R1CS.C = c
-	return a, b, c
+	circ.R1CS = r
+	return r
+}
+
+// R1CSConstraints returns the Constraints of the rows of the R1CS, in the same
+// order: all the constraints of the circuit but the inputs
+func (circ *Circuit) R1CSConstraints() []Constraint {
+	var cs []Constraint
+	for _, constraint := range circ.Constraints {
+		if constraint.Op != "in" {
+			cs = append(cs, constraint)
+		}
+	}
+	return cs
+}
+
+// CheckSatisfied returns the constraints of the circuit R1CS that are not
+// satisfied by the witness w over the field of pf, with the values of its
+// linear combinations and the Literal of its flat code
+func (circ *Circuit) CheckSatisfied(pf r1csqap.PolynomialField, w []*big.Int) ([]r1csqap.UnsatisfiedConstraint, error) {
+	unsatisfied, err := pf.CheckSparseSatisfied(circ.R1CS, w)
+	if err != nil {
+		return nil, err
+	}
+	constraints := circ.R1CSConstraints()
+	for i := 0; i < len(unsatisfied); i++ {
+		if unsatisfied[i].Index < len(constraints) {
+			unsatisfied[i].Literal = constraints[unsatisfied[i].Index].Literal
+		}
+	}
+	return unsatisfied, nil
+}
+
+// CheckWitness returns an error with the unsatisfied constraints if the
+// witness w does not satisfy the circuit R1CS over the field of pf
+func (circ *Circuit) CheckWitness(pf r1csqap.PolynomialField, w []*big.Int) error {
+	unsatisfied, err := circ.CheckSatisfied(pf, w)
+	if err != nil {
+		return err
+	}
+	if len(unsatisfied) == 0 {
+		return nil
+	}
+	var us []string
+	for _, u := range unsatisfied {
+		us = append(us, u.String())
+	}
+	return fmt.Errorf("witness does not satisfy %d constraints of the R1CS: %s", len(unsatisfied), strings.Join(us, "; "))
 }
 
 func grabVar(signals []string, w []*big.Int, vStr string) *big.Int {
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/r1csqap"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -68,6 +70,11 @@ func TestCircuitParser(t *testing.T) {
 	assert.Equal(t, bExpected, b)
 	assert.Equal(t, cExpected, c)
 
+	// the sparse R1CS of the circuit, with the non zero terms of each constraint
+	assert.Equal(t, len(circuit.Signals), circuit.R1CS.NSignals)
+	assert.Equal(t, r1csqap.LinearCombination{{Signal: 0, Coeff: b5}, {Signal: 5, Coeff: b1}}, circuit.R1CS.A[3])
+	assert.Equal(t, r1csqap.NewSparseR1CS(a, b, c), circuit.R1CS)
+
 	b3 := big.NewInt(int64(3))
 	privateInputs := []*big.Int{b3}
 	b35 := big.NewInt(int64(35))
@@ -81,6 +88,22 @@ func TestCircuitParser(t *testing.T) {
 	wExpected := []*big.Int{b1, b35, b3, b9, b27, b30, b35, b1}
 	assert.Equal(t, wExpected, w)
 
+	// the witness satisfies the R1CS, and a wrong s4 breaks its two constraints
+	fqR, err := bn128.NewFqR()
+	assert.Nil(t, err)
+	pf := r1csqap.NewPolynomialField(fqR)
+	assert.Nil(t, circuit.CheckWitness(pf, w))
+	wrong := append([]*big.Int{}, w...)
+	wrong[5] = big.NewInt(int64(31))
+	unsatisfied, err := circuit.CheckSatisfied(pf, wrong)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(unsatisfied))
+	assert.Equal(t, 2, unsatisfied[0].Index)
+	assert.Equal(t, "s4=s3+s0", unsatisfied[0].Literal)
+	assert.Equal(t, "s5=s4+5", unsatisfied[1].Literal)
+	err = circuit.CheckWitness(pf, wrong)
+	assert.Equal(t, "witness does not satisfy 2 constraints of the R1CS: constraint 2 (s4=s3+s0): 30 * 1 != 31; constraint 3 (s5=s4+5): 36 * 1 != 35", err.Error())
+
 	// circuitJson, _ := json.Marshal(circuit)
 	// fmt.Println("circuit:", string(circuitJson))
 
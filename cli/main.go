@@ -162,6 +162,9 @@ func CompileCircuit(zcli *Zerocli) error {
 	fmt.Println("b:", b)
 	fmt.Println("c:", c)
 
+	// the witness must satisfy each constraint of the R1CS, the error has the failing ones
+	panicErr(circuit.CheckWitness(snark.Utils.PF, w))
+
 	// R1CS to QAP
 	alphas, betas, gammas, zx := snark.Utils.PF.R1CSToQAP(a, b, c)
 	fmt.Println("qap")
@@ -226,28 +229,6 @@ func CompileCircuit(zcli *Zerocli) error {
 		jsonFile.Close()
 	}
 
-	// store px
-	jsonData, err = json.Marshal(px)
-	panicErr(err)
-	// store setup into file
-	jsonFile, err = os.Create(circuitPath+"px.json")
-	panicErr(err)
-	defer jsonFile.Close()
-	jsonFile.Write(jsonData)
-	jsonFile.Close()
-	fmt.Println("Px data written to ", jsonFile.Name())
-	if wasmFlag {
-		pxString := utils.ArrayBigIntToString(px)
-		jsonData, err = json.Marshal(pxString)
-		panicErr(err)
-		// store setup into file
-		jsonFile, err = os.Create(circuitPath+"pxString.json")
-		panicErr(err)
-		defer jsonFile.Close()
-		jsonFile.Write(jsonData)
-		jsonFile.Close()
-	}
-
 	return nil
 }
 
@@ -281,15 +262,8 @@ func TrustedSetup(zcli *Zerocli) error {
 	w, err := circuit.CalculateWitness(inputs.Private, inputs.Public)
 	panicErr(err)
 
-	// R1CS to QAP
-	alphas, betas, gammas, _ := snark.Utils.PF.R1CSToQAP(circuit.R1CS.A, circuit.R1CS.B, circuit.R1CS.C)
-	fmt.Println("qap")
-	fmt.Println(alphas)
-	fmt.Println(betas)
-	fmt.Println(gammas)
-
-	// calculate trusted setup
-	setup, err := snark.GenerateTrustedSetup(len(w), circuit, alphas, betas, gammas)
+	// calculate trusted setup, evaluating the QAP of the circuit R1CS at t
+	setup, err := snark.GenerateTrustedSetup(len(w), circuit)
 	panicErr(err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
@@ -299,16 +273,10 @@ func TrustedSetup(zcli *Zerocli) error {
 	tsetup.Vk = setup.Vk
 	tsetup.Pk.G1T = setup.Pk.G1T
 
-	// store setup to json
-	jsonData, err := json.Marshal(tsetup)
-	panicErr(err)
-	// store setup into file
-	jsonFile, err := os.Create(zcli.Path+"trustedsetup.json")
+	// store setup into file, in binary format
+	err = ioutil.WriteFile(zcli.Path+"trustedsetup.bin", utils.SetupToBinary(tsetup), 0644)
 	panicErr(err)
-	defer jsonFile.Close()
-	jsonFile.Write(jsonData)
-	jsonFile.Close()
-	fmt.Println("Trusted Setup data written to ", jsonFile.Name())
+	fmt.Println("Trusted Setup data written to ", zcli.Path+"trustedsetup.bin")
 	if wasmFlag {
 		tsetupString := utils.SetupToString(tsetup)
 		jsonData, err := json.Marshal(tsetupString)
@@ -332,12 +300,10 @@ func GenerateProofs(zcli *Zerocli) error {
 	json.Unmarshal([]byte(string(compiledcircuitFile)), &circuit)
 	panicErr(err)
 
-	// open trustedsetup.json
-	trustedsetupFile, err := ioutil.ReadFile(zcli.Path+"trustedsetup.json")
+	// open trustedsetup.bin
+	trustedsetupFile, err := ioutil.ReadFile(zcli.Path+"trustedsetup.bin")
 	panicErr(err)
-
-	var trustedsetup snark.Setup
-	json.Unmarshal([]byte(string(trustedsetupFile)), &trustedsetup)
+	trustedsetup, err := utils.SetupFromBinary(trustedsetupFile)
 	panicErr(err)
 
 	// read privateInputs file
@@ -358,51 +324,37 @@ func GenerateProofs(zcli *Zerocli) error {
 	panicErr(err)
 	fmt.Println("witness", w)
 
-	// flat code to R1CS
-	a := circuit.R1CS.A
-	b := circuit.R1CS.B
-	c := circuit.R1CS.C
-	// R1CS to QAP
-	alphas, betas, gammas, _ := snark.Utils.PF.R1CSToQAP(a, b, c)
-	_, _, _, px := snark.Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
-	hx := snark.Utils.PF.DivisorPolynomial(px, trustedsetup.Pk.Z)
+	// h(x) of the QAP of the circuit R1CS, computed again inside GenerateProofs
+	hx := snark.Utils.PF.HPolynomial(circuit.R1CS, w)
 
 	fmt.Println("输出电路：",circuit)
 	fmt.Println(trustedsetup.Pk.G1T)
 	fmt.Println(hx)
 	fmt.Println("输出witness：",w)
-	proof, err := snark.GenerateProofs(circuit, trustedsetup.Pk, w, px)
+	proof, err := snark.GenerateProofs(circuit, trustedsetup.Pk, w)
 	panicErr(err)
 
 	fmt.Println("\n proofs:")
 	fmt.Println(proof)
 
-	// store proofs to json
-	jsonData, err := json.Marshal(proof)
-	panicErr(err)
-	// store proof into file
-	jsonFile, err := os.Create(zcli.Path+"proofs.json")
+	// store proof into file, in binary format
+	err = ioutil.WriteFile(zcli.Path+"proofs.bin", utils.ProofToBinary(proof), 0644)
 	panicErr(err)
-	defer jsonFile.Close()
-	jsonFile.Write(jsonData)
-	jsonFile.Close()
-	fmt.Println("Proofs data written to ：", jsonFile.Name())
+	fmt.Println("Proofs data written to ：", zcli.Path+"proofs.bin")
 	return nil
 }
 
 func VerifyProofs(zcli *Zerocli) error {
-	// open proofs.json
-	proofsFile, err := ioutil.ReadFile(zcli.Path+"proofs.json")
+	// open proofs.bin
+	proofsFile, err := ioutil.ReadFile(zcli.Path+"proofs.bin")
 	panicErr(err)
-	var proof snark.Proof
-	json.Unmarshal([]byte(string(proofsFile)), &proof)
+	proof, err := utils.ProofFromBinary(proofsFile)
 	panicErr(err)
 
-	// open trustedsetup.json
-	trustedsetupFile, err := ioutil.ReadFile(zcli.Path+"trustedsetup.json")
+	// open trustedsetup.bin
+	trustedsetupFile, err := ioutil.ReadFile(zcli.Path+"trustedsetup.bin")
 	panicErr(err)
-	var trustedsetup snark.Setup
-	json.Unmarshal([]byte(string(trustedsetupFile)), &trustedsetup)
+	trustedsetup, err := utils.SetupFromBinary(trustedsetupFile)
 	panicErr(err)
 
 	// read publicInputs file
@@ -412,7 +364,8 @@ func VerifyProofs(zcli *Zerocli) error {
 	err = json.Unmarshal([]byte(string(publicInputsFile)), &publicSignals)
 	panicErr(err)
 
-	verified := snark.VerifyProof(trustedsetup.Vk, proof, publicSignals, true)
+	verified, err := snark.VerifyProof(trustedsetup.Vk, proof, publicSignals, true)
+	panicErr(err)
 	if !verified {
 		fmt.Println("ERROR: proofs not verified")
 	} else {
@@ -449,15 +402,8 @@ func Groth16TrustedSetup(zcli *Zerocli) error {
 	w, err := circuit.CalculateWitness(inputs.Private, inputs.Public)
 	panicErr(err)
 
-	// R1CS to QAP
-	alphas, betas, gammas, _ := snark.Utils.PF.R1CSToQAP(circuit.R1CS.A, circuit.R1CS.B, circuit.R1CS.C)
-	fmt.Println("qap")
-	fmt.Println(alphas)
-	fmt.Println(betas)
-	fmt.Println(gammas)
-
-	// calculate trusted setup
-	setup, err := groth16.GenerateTrustedSetup(len(w), circuit, alphas, betas, gammas)
+	// calculate trusted setup, evaluating the QAP of the circuit R1CS at t
+	setup, err := groth16.GenerateTrustedSetup(len(w), circuit)
 	panicErr(err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
@@ -466,16 +412,10 @@ func Groth16TrustedSetup(zcli *Zerocli) error {
 	tsetup.Pk = setup.Pk
 	tsetup.Vk = setup.Vk
 
-	// store setup to json
-	jsonData, err := json.Marshal(tsetup)
-	panicErr(err)
-	// store setup into file
-	jsonFile, err := os.Create(zcli.Path+"trustedsetup.json")
+	// store setup into file, in binary format
+	err = ioutil.WriteFile(zcli.Path+"trustedsetup.bin", utils.GrothSetupToBinary(tsetup), 0644)
 	panicErr(err)
-	defer jsonFile.Close()
-	jsonFile.Write(jsonData)
-	jsonFile.Close()
-	fmt.Println("Trusted Setup data written to ", jsonFile.Name())
+	fmt.Println("Trusted Setup data written to ", zcli.Path+"trustedsetup.bin")
 	return nil
 }
 
@@ -488,11 +428,10 @@ func Groth16GenerateProofs(zcli *Zerocli) error {
 	json.Unmarshal([]byte(string(compiledcircuitFile)), &circuit)
 	panicErr(err)
 
-	// open trustedsetup.json
-	trustedsetupFile, err := ioutil.ReadFile(zcli.Path+"trustedsetup.json")
+	// open trustedsetup.bin
+	trustedsetupFile, err := ioutil.ReadFile(zcli.Path+"trustedsetup.bin")
 	panicErr(err)
-	var trustedsetup groth16.Setup
-	json.Unmarshal([]byte(string(trustedsetupFile)), &trustedsetup)
+	trustedsetup, err := utils.GrothSetupFromBinary(trustedsetupFile)
 	panicErr(err)
 
 	// read privateInputs file
@@ -513,52 +452,38 @@ func Groth16GenerateProofs(zcli *Zerocli) error {
 	panicErr(err)
 	fmt.Println("witness", w)
 
-	// flat code to R1CS
-	a := circuit.R1CS.A
-	b := circuit.R1CS.B
-	c := circuit.R1CS.C
-	// R1CS to QAP
-	alphas, betas, gammas, _ := groth16.Utils.PF.R1CSToQAP(a, b, c)
-	_, _, _, px := groth16.Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
-	hx := groth16.Utils.PF.DivisorPolynomial(px, trustedsetup.Pk.Z)
+	// h(x) of the QAP of the circuit R1CS, computed again inside GenerateProofs
+	hx := groth16.Utils.PF.HPolynomial(circuit.R1CS, w)
 
 	fmt.Println(circuit)
 	fmt.Println(trustedsetup.Pk.PowersTauDelta)
 	fmt.Println(hx)
 	fmt.Println(w)
-	proof, err := groth16.GenerateProofs(circuit, trustedsetup.Pk, w, px)
+	proof, err := groth16.GenerateProofs(circuit, trustedsetup.Pk, w)
 	panicErr(err)
 
 	fmt.Println("\n proofs:")
 	fmt.Println(proof)
 
-	// store proofs to json
-	jsonData, err := json.Marshal(proof)
-	panicErr(err)
-	// store proof into file
-	jsonFile, err := os.Create(zcli.Path+"proofs.json")
+	// store proof into file, in binary format
+	err = ioutil.WriteFile(zcli.Path+"proofs.bin", utils.GrothProofToBinary(proof), 0644)
 	panicErr(err)
-	defer jsonFile.Close()
-	jsonFile.Write(jsonData)
-	jsonFile.Close()
-	fmt.Println("Proofs data written to ", jsonFile.Name())
+	fmt.Println("Proofs data written to ", zcli.Path+"proofs.bin")
 	return nil
 }
 
 // 通过验证密钥验证证明是否正确
 func Groth16VerifyProofs(zcli *Zerocli) error {
-	// open proofs.json
-	proofsFile, err := ioutil.ReadFile(zcli.Path+"proofs.json")
+	// open trustedsetup.bin
+	trustedsetupFile, err := ioutil.ReadFile(zcli.Path+"trustedsetup.bin")
 	panicErr(err)
-	var proof groth16.Proof
-	json.Unmarshal([]byte(string(proofsFile)), &proof)
+	trustedsetup, err := utils.GrothSetupFromBinary(trustedsetupFile)
 	panicErr(err)
 
-	// open trustedsetup.json
-	trustedsetupFile, err := ioutil.ReadFile(zcli.Path+"trustedsetup.json")
+	// open proofs.bin, over the curve of the trusted setup
+	proofsFile, err := ioutil.ReadFile(zcli.Path+"proofs.bin")
 	panicErr(err)
-	var trustedsetup groth16.Setup
-	json.Unmarshal([]byte(string(trustedsetupFile)), &trustedsetup)
+	proof, err := utils.GrothProofFromBinary(trustedsetup.Vk.Curve, proofsFile)
 	panicErr(err)
 
 	// read publicInputs file
@@ -568,7 +493,8 @@ func Groth16VerifyProofs(zcli *Zerocli) error {
 	err = json.Unmarshal([]byte(string(publicInputsFile)), &publicSignals)
 	panicErr(err)
 
-	verified := groth16.VerifyProof(trustedsetup.Vk, proof, publicSignals, true)
+	verified, err := groth16.VerifyProof(trustedsetup.Vk, proof, publicSignals, true)
+	panicErr(err)
 	if !verified {
 		fmt.Println("ERROR: proofs not verified")
 	} else {
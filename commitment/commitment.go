@@ -0,0 +1,194 @@
+// Package commitment implements the Pedersen commitments over the BN128 G1.
+// A commitment to the values v_0, ..., v_{n-1} with the blinding factor r is
+// v_0*G_0 + ... + v_{n-1}*G_{n-1} + r*H, that hides the values (r is random)
+// and binds them (nobody knows the discrete logarithms between the
+// generators). The generators are hashed to G1 from a seed with
+// bn128.HashToG1, so they can be derived again by anyone, and nobody knows
+// their discrete logarithms.
+//
+// The commitments are homomorphic: the sum of two commitments is the
+// commitment to the sum of the values, with the sum of the blinding factors,
+// and a commitment multiplied by a scalar is the commitment to the values
+// multiplied by it.
+//
+// The number of values is not bound by the commitment: the values followed by
+// zeros have the same commitment than the values alone, so an opening is
+// unique only up to its trailing zeros. This is what lets AddOpenings add the
+// openings of different lengths. The protocols that need the length have to
+// commit to it as one more value, or fix it.
+package commitment
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// DefaultSeed is the seed of the generators of NewPedersen
+var DefaultSeed = []byte("go-snark pedersen generators")
+
+// dstGenerators is the domain separation tag of the hash of the generators
+var dstGenerators = []byte("GO-SNARK-PEDERSEN-V01-CS01-with-BN254G1_XMD:SHA-256_SVDW_RO_")
+
+// Pedersen is the data structure of the Pedersen commitments to up to
+// len(G) values
+type Pedersen struct {
+	Bn bn128.Bn128
+	Fr fields.Fq // the field of the values, over the order R of G1
+	G  []bn128.G1Point
+	H  bn128.G1Point
+}
+
+// Commitment is a Pedersen commitment
+type Commitment struct {
+	P bn128.G1Point
+}
+
+// Opening is the opening of a commitment: the committed values and the
+// blinding factor
+type Opening struct {
+	Values []*big.Int
+	R      *big.Int
+}
+
+// NewPedersen returns the Pedersen commitments to up to n values, with the
+// generators derived from the DefaultSeed
+func NewPedersen(n int) (Pedersen, error) {
+	return NewPedersenFromSeed(DefaultSeed, n)
+}
+
+// NewPedersenFromSeed returns the Pedersen commitments to up to n values,
+// with the generators derived from the given seed. The generators of the
+// same seed are the same, and the first ones do not depend on n
+func NewPedersenFromSeed(seed []byte, n int) (Pedersen, error) {
+	if n < 1 {
+		return Pedersen{}, errors.New("the number of generators must be at least 1")
+	}
+	bn, err := bn128.NewBn128()
+	if err != nil {
+		return Pedersen{}, err
+	}
+	fr, err := bn128.NewFqR()
+	if err != nil {
+		return Pedersen{}, err
+	}
+	gs := make([][3]*big.Int, n)
+	for i := 0; i < n; i++ {
+		gs[i] = generator(bn, seed, uint64(i))
+	}
+	// H does not depend on n, and can not collide with the G of a bigger n
+	h := bn.HashToG1(append(append([]byte{}, seed...), 'H'), dstGenerators)
+	return Pedersen{
+		Bn: bn,
+		Fr: fr,
+		G:  bn.G1.Points(bn.G1.BatchAffine(gs)),
+		H:  bn.G1.Point(h).Affine(),
+	}, nil
+}
+
+// generator returns the generator i of the seed, the hash of the seed
+// followed by i in 8 bytes big-endian
+func generator(bn bn128.Bn128, seed []byte, i uint64) [3]*big.Int {
+	msg := make([]byte, len(seed)+8)
+	copy(msg, seed)
+	binary.BigEndian.PutUint64(msg[len(seed):], i)
+	return bn.HashToG1(msg, dstGenerators)
+}
+
+// Commit returns the commitment to the value v with the blinding factor r
+func (p Pedersen) Commit(v, r *big.Int) Commitment {
+	c, _ := p.CommitVector([]*big.Int{v}, r)
+	return c
+}
+
+// CommitVector returns the commitment to the values vs with the blinding
+// factor r. It returns an error if there are more values than generators.
+// The values and r are secret, so they are multiplied with the constant-time
+// bn128.G1.MultiExpCT
+func (p Pedersen) CommitVector(vs []*big.Int, r *big.Int) (Commitment, error) {
+	if len(vs) > len(p.G) {
+		return Commitment{}, errors.New("more values than generators")
+	}
+	points := append(bn128.G1Arrays(p.G[:len(vs)]), p.H.Array())
+	scalars := make([]*big.Int, 0, len(vs)+1)
+	for i := 0; i < len(vs); i++ {
+		scalars = append(scalars, p.Fr.Affine(vs[i]))
+	}
+	scalars = append(scalars, p.Fr.Affine(r))
+	c := p.Bn.G1.Point(p.Bn.G1.MultiExpCT(points, scalars))
+	return Commitment{c.Affine()}, nil
+}
+
+// CommitRand returns the commitment to the values vs with a random blinding
+// factor, read from crypto/rand, and its opening
+func (p Pedersen) CommitRand(vs []*big.Int) (Commitment, Opening, error) {
+	r, err := p.Fr.Rand()
+	if err != nil {
+		return Commitment{}, Opening{}, err
+	}
+	c, err := p.CommitVector(vs, r)
+	if err != nil {
+		return Commitment{}, Opening{}, err
+	}
+	return c, Opening{vs, r}, nil
+}
+
+// Verify returns true if the opening o opens the commitment c. The opening
+// with trailing zero values added also opens it
+func (p Pedersen) Verify(c Commitment, o Opening) bool {
+	if o.R == nil {
+		return false
+	}
+	c2, err := p.CommitVector(o.Values, o.R)
+	if err != nil {
+		return false
+	}
+	return c.Equal(c2)
+}
+
+// Add returns the sum of the commitments, the commitment to the sum of their
+// values with the sum of their blinding factors
+func (p Pedersen) Add(c1, c2 Commitment) Commitment {
+	return Commitment{c1.P.Add(c2.P).Affine()}
+}
+
+// Scale returns the commitment multiplied by e, the commitment to its values
+// multiplied by e with its blinding factor multiplied by e
+func (p Pedersen) Scale(c Commitment, e *big.Int) Commitment {
+	return Commitment{c.P.ScalarMul(p.Fr.Affine(e)).Affine()}
+}
+
+// AddOpenings returns the opening of the sum of the commitments opened by o1
+// and o2. The missing values of the shorter opening are zero
+func (p Pedersen) AddOpenings(o1, o2 Opening) Opening {
+	if len(o1.Values) < len(o2.Values) {
+		o1, o2 = o2, o1
+	}
+	vs := make([]*big.Int, len(o1.Values))
+	for i := 0; i < len(o1.Values); i++ {
+		vs[i] = p.Fr.Affine(o1.Values[i])
+		if i < len(o2.Values) {
+			vs[i] = p.Fr.Add(vs[i], p.Fr.Affine(o2.Values[i]))
+		}
+	}
+	return Opening{vs, p.Fr.Add(p.Fr.Affine(o1.R), p.Fr.Affine(o2.R))}
+}
+
+// ScaleOpening returns the opening of the commitment opened by o multiplied
+// by e
+func (p Pedersen) ScaleOpening(o Opening, e *big.Int) Opening {
+	e = p.Fr.Affine(e)
+	vs := make([]*big.Int, len(o.Values))
+	for i := 0; i < len(o.Values); i++ {
+		vs[i] = p.Fr.Mul(p.Fr.Affine(o.Values[i]), e)
+	}
+	return Opening{vs, p.Fr.Mul(p.Fr.Affine(o.R), e)}
+}
+
+// Equal returns true if the commitments are the same
+func (c Commitment) Equal(d Commitment) bool {
+	return c.P.Equal(d.P)
+}
@@ -0,0 +1,107 @@
+package commitment
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPedersen(t *testing.T) {
+	p, err := NewPedersen(4)
+	assert.Nil(t, err)
+	g1 := p.Bn.G1
+
+	// the generators are derived deterministically, and are in G1
+	p2, err := NewPedersen(6)
+	assert.Nil(t, err)
+	for i := 0; i < len(p.G); i++ {
+		assert.Nil(t, p.G[i].Validate())
+		assert.True(t, p.G[i].Equal(p2.G[i]))
+		assert.False(t, p.G[i].Equal(p.H))
+	}
+	assert.True(t, p.H.Equal(p2.H))
+	p3, err := NewPedersenFromSeed([]byte("another seed"), 4)
+	assert.Nil(t, err)
+	assert.False(t, p.G[0].Equal(p3.G[0]))
+	_, err = NewPedersen(0)
+	assert.NotNil(t, err)
+
+	// v*G_0 + r*H
+	v := big.NewInt(42)
+	r := big.NewInt(1234)
+	c := p.Commit(v, r)
+	expected := g1.Add(g1.MulScalar(p.G[0].Array(), v), g1.MulScalar(p.H.Array(), r))
+	assert.True(t, g1.Equal(expected, c.P.Array()))
+	assert.True(t, p.Verify(c, Opening{[]*big.Int{v}, r}))
+	assert.False(t, p.Verify(c, Opening{[]*big.Int{big.NewInt(43)}, r}))
+	assert.False(t, p.Verify(c, Opening{[]*big.Int{v}, big.NewInt(1235)}))
+	assert.False(t, p.Verify(c, Opening{[]*big.Int{v}, nil}))
+
+	// values over the order of G1 are reduced
+	c2 := p.Commit(new(big.Int).Add(v, p.Bn.R), r)
+	assert.True(t, c.Equal(c2))
+}
+
+func TestPedersenVector(t *testing.T) {
+	p, err := NewPedersen(4)
+	assert.Nil(t, err)
+	g1 := p.Bn.G1
+
+	vs := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4)}
+	r := big.NewInt(5)
+	c, err := p.CommitVector(vs, r)
+	assert.Nil(t, err)
+	expected := g1.MulScalar(p.H.Array(), r)
+	for i := 0; i < len(vs); i++ {
+		expected = g1.Add(expected, g1.MulScalar(p.G[i].Array(), vs[i]))
+	}
+	assert.True(t, g1.Equal(expected, c.P.Array()))
+	assert.True(t, p.Verify(c, Opening{vs, r}))
+	assert.False(t, p.Verify(c, Opening{vs[:3], r}))
+	vs2 := []*big.Int{vs[1], vs[0], vs[2], vs[3]}
+	assert.False(t, p.Verify(c, Opening{vs2, r}))
+
+	_, err = p.CommitVector(append(vs, big.NewInt(5)), r)
+	assert.NotNil(t, err)
+	assert.False(t, p.Verify(c, Opening{append(vs, big.NewInt(0)), r}))
+
+	// the length is not bound: the trailing zeros open the same commitment
+	c3, err := p.CommitVector(vs[:3], r)
+	assert.Nil(t, err)
+	assert.True(t, p.Verify(c3, Opening{append(vs[:3:3], big.NewInt(0)), r}))
+
+	c, o, err := p.CommitRand(vs)
+	assert.Nil(t, err)
+	assert.True(t, p.Verify(c, o))
+}
+
+func TestPedersenHomomorphic(t *testing.T) {
+	p, err := NewPedersen(3)
+	assert.Nil(t, err)
+
+	c1, o1, err := p.CommitRand([]*big.Int{big.NewInt(10), big.NewInt(20), big.NewInt(30)})
+	assert.Nil(t, err)
+	c2, o2, err := p.CommitRand([]*big.Int{big.NewInt(1), big.NewInt(2)})
+	assert.Nil(t, err)
+
+	sum := p.Add(c1, c2)
+	o := p.AddOpenings(o1, o2)
+	assert.Equal(t, []*big.Int{big.NewInt(11), big.NewInt(22), big.NewInt(30)}, o.Values)
+	assert.True(t, p.Verify(sum, o))
+	assert.True(t, p.Verify(sum, p.AddOpenings(o2, o1)))
+	assert.False(t, p.Verify(sum, o1))
+
+	e := big.NewInt(7)
+	scaled := p.Scale(c1, e)
+	o = p.ScaleOpening(o1, e)
+	assert.Equal(t, []*big.Int{big.NewInt(70), big.NewInt(140), big.NewInt(210)}, o.Values)
+	assert.True(t, p.Verify(scaled, o))
+
+	// -1 gives the commitment to the negated values, that added to the
+	// commitment is the commitment to zero
+	minus1 := big.NewInt(-1)
+	zero := p.Add(c1, p.Scale(c1, minus1))
+	assert.True(t, zero.P.IsZero())
+	assert.True(t, p.Verify(zero, p.AddOpenings(o1, p.ScaleOpening(o1, minus1))))
+}
@@ -0,0 +1,189 @@
+// Package curve abstracts the pairing friendly curves over which the zkSNARKs
+// run, so the same setup, prover and verifier code can target the BN128 or the
+// BLS12-381
+package curve
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/arnaucube/go-snark/bls12381"
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// Curve is a pairing friendly curve, with its groups G1 and G2 (the
+// bn128.G1 and bn128.G2 operations work for any curve y^2 = x^3 + b), the
+// target group GT and the scalar field Fr (over the order R of the groups)
+type Curve interface {
+	Name() string
+	Fr() fields.Fq
+	G1() bn128.G1
+	G2() bn128.G2
+	GT() fields.Fq12
+	Pairing(p1 [3]*big.Int, p2 [3][2]*big.Int) [2][3][2]*big.Int
+	// PairingCheck returns true if the product of the pairings
+	// e(g1s[i], g2s[i]) is one
+	PairingCheck(g1s [][3]*big.Int, g2s [][3][2]*big.Int) bool
+	// PreComputeG2 returns the Miller loop line coefficients of the G2
+	// point p, to be used in PairingProduct
+	PreComputeG2(p [3][2]*big.Int) G2Precomp
+	// PairingProduct returns the product of the pairings e(g1s[i], q_i),
+	// where pre2s[i] = PreComputeG2(q_i)
+	PairingProduct(g1s [][3]*big.Int, pre2s []G2Precomp) [2][3][2]*big.Int
+}
+
+// G2Precomp is the precomputation of a G2 point returned by
+// Curve.PreComputeG2. Its concrete type depends on the curve, and it can only
+// be used with the curve that created it
+type G2Precomp interface{}
+
+type bn128Curve struct {
+	bn bn128.Bn128
+	fr fields.Fq
+}
+
+// NewBN128 returns the BN128 Curve
+func NewBN128() (Curve, error) {
+	bn, err := bn128.NewBn128()
+	if err != nil {
+		return nil, err
+	}
+	return FromBn128(bn), nil
+}
+
+// FromBn128 returns the Curve of an already created bn128.Bn128
+func FromBn128(bn bn128.Bn128) Curve {
+	return bn128Curve{bn, fields.NewFq(bn.R)}
+}
+
+func (c bn128Curve) Name() string    { return "bn128" }
+func (c bn128Curve) Fr() fields.Fq   { return c.fr }
+func (c bn128Curve) G1() bn128.G1    { return c.bn.G1 }
+func (c bn128Curve) G2() bn128.G2    { return c.bn.G2 }
+func (c bn128Curve) GT() fields.Fq12 { return c.bn.Fq12 }
+
+func (c bn128Curve) Pairing(p1 [3]*big.Int, p2 [3][2]*big.Int) [2][3][2]*big.Int {
+	return c.bn.Pairing(p1, p2)
+}
+
+func (c bn128Curve) PairingCheck(g1s [][3]*big.Int, g2s [][3][2]*big.Int) bool {
+	return c.bn.PairingCheck(g1s, g2s)
+}
+
+func (c bn128Curve) PreComputeG2(p [3][2]*big.Int) G2Precomp {
+	return c.bn.PreComputeG2(p)
+}
+
+func (c bn128Curve) PairingProduct(g1s [][3]*big.Int, pre2s []G2Precomp) [2][3][2]*big.Int {
+	pres := make([]bn128.AteG2Precomp, len(pre2s))
+	for i := 0; i < len(pre2s); i++ {
+		pres[i] = pre2s[i].(bn128.AteG2Precomp)
+	}
+	return c.bn.PairingProduct(g1s, pres)
+}
+
+type bls12381Curve struct {
+	bls bls12381.Bls12381
+	fr  fields.Fq
+}
+
+// NewBLS12381 returns the BLS12-381 Curve
+func NewBLS12381() (Curve, error) {
+	bls, err := bls12381.NewBls12381()
+	if err != nil {
+		return nil, err
+	}
+	return FromBls12381(bls), nil
+}
+
+// FromBls12381 returns the Curve of an already created bls12381.Bls12381
+func FromBls12381(bls bls12381.Bls12381) Curve {
+	return bls12381Curve{bls, fields.NewFq(bls.R)}
+}
+
+func (c bls12381Curve) Name() string    { return "bls12381" }
+func (c bls12381Curve) Fr() fields.Fq   { return c.fr }
+func (c bls12381Curve) G1() bn128.G1    { return c.bls.G1 }
+func (c bls12381Curve) G2() bn128.G2    { return c.bls.G2 }
+func (c bls12381Curve) GT() fields.Fq12 { return c.bls.Fq12 }
+
+func (c bls12381Curve) Pairing(p1 [3]*big.Int, p2 [3][2]*big.Int) [2][3][2]*big.Int {
+	return c.bls.Pairing(p1, p2)
+}
+
+func (c bls12381Curve) PairingCheck(g1s [][3]*big.Int, g2s [][3][2]*big.Int) bool {
+	return c.bls.PairingCheck(g1s, g2s)
+}
+
+func (c bls12381Curve) PreComputeG2(p [3][2]*big.Int) G2Precomp {
+	return c.bls.PreComputeG2(p)
+}
+
+func (c bls12381Curve) PairingProduct(g1s [][3]*big.Int, pre2s []G2Precomp) [2][3][2]*big.Int {
+	pres := make([]bls12381.AteG2Precomp, len(pre2s))
+	for i := 0; i < len(pre2s); i++ {
+		pres[i] = pre2s[i].(bls12381.AteG2Precomp)
+	}
+	return c.bls.PairingProduct(g1s, pres)
+}
+
+// The curves are identified by their Name in the text encodings, and by their
+// ID in the binary encodings
+const (
+	IDBN128    byte = 1
+	IDBLS12381 byte = 2
+)
+
+var constructors = map[string]struct {
+	id  byte
+	new func() (Curve, error)
+}{
+	"bn128":    {IDBN128, NewBN128},
+	"bls12381": {IDBLS12381, NewBLS12381},
+}
+
+var (
+	byNameMu sync.Mutex
+	byName   = map[string]Curve{}
+)
+
+// ByName returns the Curve with the given Name. The curves are created once,
+// and shared by the callers
+func ByName(name string) (Curve, error) {
+	cons, ok := constructors[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown curve %q", name)
+	}
+	byNameMu.Lock()
+	defer byNameMu.Unlock()
+	if c, ok := byName[name]; ok {
+		return c, nil
+	}
+	c, err := cons.new()
+	if err != nil {
+		return nil, err
+	}
+	byName[name] = c
+	return c, nil
+}
+
+// ID returns the identifier of the curve c in the binary encodings
+func ID(c Curve) (byte, error) {
+	cons, ok := constructors[c.Name()]
+	if !ok {
+		return 0, fmt.Errorf("unknown curve %q", c.Name())
+	}
+	return cons.id, nil
+}
+
+// ByID returns the Curve with the identifier id, as ByName
+func ByID(id byte) (Curve, error) {
+	for name, cons := range constructors {
+		if cons.id == id {
+			return ByName(name)
+		}
+	}
+	return nil, fmt.Errorf("unknown curve identifier %d", id)
+}
@@ -0,0 +1,52 @@
+package curve
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurves(t *testing.T) {
+	bn, err := NewBN128()
+	assert.Nil(t, err)
+	bls, err := NewBLS12381()
+	assert.Nil(t, err)
+
+	for _, c := range []Curve{bn, bls} {
+		g1, g2 := c.G1(), c.G2()
+		assert.True(t, g1.IsZero(g1.MulScalar(g1.G, c.Fr().Q)))
+		assert.True(t, g2.IsZero(g2.MulScalar(g2.G, c.Fr().Q)))
+
+		a := big.NewInt(int64(7))
+		g1a := g1.MulScalar(g1.G, a)
+		g2a := g2.MulScalar(g2.G, a)
+		assert.True(t, c.GT().Equal(c.Pairing(g1a, g2.G), c.Pairing(g1.G, g2a)), c.Name())
+
+		// e(a*G1, G2) * e(G1, -a*G2) == 1
+		assert.True(t, c.PairingCheck(
+			[][3]*big.Int{g1a, g1.G},
+			[][3][2]*big.Int{g2.G, g2.Neg(g2a)}), c.Name())
+		product := c.PairingProduct(
+			[][3]*big.Int{g1a, g1.G},
+			[]G2Precomp{c.PreComputeG2(g2.G), c.PreComputeG2(g2a)})
+		assert.True(t, c.GT().Equal(product, c.GT().Square(c.Pairing(g1a, g2.G))), c.Name())
+	}
+}
+
+func TestByName(t *testing.T) {
+	for _, name := range []string{"bn128", "bls12381"} {
+		c, err := ByName(name)
+		assert.Nil(t, err)
+		assert.Equal(t, name, c.Name())
+		id, err := ID(c)
+		assert.Nil(t, err)
+		c2, err := ByID(id)
+		assert.Nil(t, err)
+		assert.Equal(t, name, c2.Name())
+	}
+	_, err := ByName("secp256k1")
+	assert.NotNil(t, err)
+	_, err = ByID(0)
+	assert.NotNil(t, err)
+}
@@ -85,6 +85,5 @@ func VerifyFromCircom(vkPath, proofPath, publicSignalsPath string) (bool, error)
 	}
 	fmt.Println("publicSignals parsed:", publicSignals)
 
-	verified := groth16.VerifyProof(vk, proof, publicSignals, true)
-	return verified, nil
+	return groth16.VerifyProof(vk, proof, publicSignals, true)
 }
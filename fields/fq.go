@@ -3,21 +3,36 @@ package fields
 import (
 	"bytes"
 	"crypto/rand"
+	"errors"
+	"io"
 	"math/big"
 )
 
 // Fq is the Z field over modulus Q
 type Fq struct {
-	Q *big.Int // Q
+	Q    *big.Int // Q
+	mont *Montgomery
 }
 
-// NewFq generates a new Fq
+// NewFq generates a new Fq. When Q is odd and fits in 255 bits (as the BN128
+// Q and R do) the operations run over the fixed size Montgomery backend,
+// otherwise (or when Fq is declared as Fq{Q: q}) they run over big.Int
 func NewFq(q *big.Int) Fq {
+	mont, err := NewMontgomery(q)
+	if err != nil {
+		mont = nil
+	}
 	return Fq{
 		q,
+		mont,
 	}
 }
 
+// Montgomery returns the Montgomery backend of the Fq, or nil if the Fq runs over big.Int
+func (fq Fq) Montgomery() *Montgomery {
+	return fq.mont
+}
+
 // Zero returns a Zero value on the Fq
 func (fq Fq) Zero() *big.Int {
 	return big.NewInt(int64(0))
@@ -30,30 +45,45 @@ func (fq Fq) One() *big.Int {
 
 // Add performs an addition on the Fq
 func (fq Fq) Add(a, b *big.Int) *big.Int {
+	if fq.mont != nil {
+		return fq.mont.addBig(a, b)
+	}
 	r := new(big.Int).Add(a, b)
 	return new(big.Int).Mod(r, fq.Q)
 }
 
 // Double performs a doubling on the Fq
 func (fq Fq) Double(a *big.Int) *big.Int {
+	if fq.mont != nil {
+		return fq.mont.addBig(a, a)
+	}
 	r := new(big.Int).Add(a, a)
 	return new(big.Int).Mod(r, fq.Q)
 }
 
 // Sub performs a subtraction on the Fq
 func (fq Fq) Sub(a, b *big.Int) *big.Int {
+	if fq.mont != nil {
+		return fq.mont.subBig(a, b)
+	}
 	r := new(big.Int).Sub(a, b)
 	return new(big.Int).Mod(r, fq.Q)
 }
 
 // Neg performs a negation on the Fq
 func (fq Fq) Neg(a *big.Int) *big.Int {
+	if fq.mont != nil {
+		return fq.mont.subBig(fq.Zero(), a)
+	}
 	m := new(big.Int).Neg(a)
 	return new(big.Int).Mod(m, fq.Q)
 }
 
 // Mul performs a multiplication on the Fq
 func (fq Fq) Mul(a, b *big.Int) *big.Int {
+	if fq.mont != nil {
+		return fq.mont.mulBig(a, b)
+	}
 	m := new(big.Int).Mul(a, b)
 	return new(big.Int).Mod(m, fq.Q)
 }
@@ -62,6 +92,14 @@ func (fq Fq) MulScalar(base, e *big.Int) *big.Int {
 	return fq.Mul(base, e)
 }
 
+// InverseCT returns the inverse on the Fq as a^(q-2), for a prime q. With the
+// Montgomery backend the exponentiation runs in a time that does not depend
+// on the value of a, but the conversions of a and of the result between
+// *big.Int and Montgomery form do. The inverse of zero returns zero
+func (fq Fq) InverseCT(a *big.Int) *big.Int {
+	return fq.Exp(a, new(big.Int).Sub(fq.Q, big.NewInt(int64(2))))
+}
+
 // Inverse returns the inverse on the Fq
 func (fq Fq) Inverse(a *big.Int) *big.Int {
 	return new(big.Int).ModInverse(a, fq.Q)
@@ -85,20 +123,59 @@ func (fq Fq) Inverse(a *big.Int) *big.Int {
 	// return t
 }
 
+// BatchInverse returns the inverses of all the given values doing a single
+// inversion (Montgomery's trick). The zero values, which have no inverse, are
+// returned as zero
+func (fq Fq) BatchInverse(a []*big.Int) []*big.Int {
+	res := make([]*big.Int, len(a))
+	if len(a) == 0 {
+		return res
+	}
+	// prods[i] = a[0] * a[1] * ... * a[i-1], skipping the zeros
+	prods := make([]*big.Int, len(a))
+	acc := fq.One()
+	for i := 0; i < len(a); i++ {
+		prods[i] = acc
+		if !fq.IsZero(fq.Affine(a[i])) {
+			acc = fq.Mul(acc, a[i])
+		}
+	}
+	inv := fq.Inverse(acc)
+	for i := len(a) - 1; i >= 0; i-- {
+		if fq.IsZero(fq.Affine(a[i])) {
+			res[i] = fq.Zero()
+			continue
+		}
+		res[i] = fq.Mul(inv, prods[i])
+		inv = fq.Mul(inv, a[i])
+	}
+	return res
+}
+
 // Div performs the division over the finite field
 func (fq Fq) Div(a, b *big.Int) *big.Int {
 	d := fq.Mul(a, fq.Inverse(b))
+	if fq.mont != nil {
+		return d
+	}
 	return new(big.Int).Mod(d, fq.Q)
 }
 
 // Square performs a square operation on the Fq
 func (fq Fq) Square(a *big.Int) *big.Int {
+	if fq.mont != nil {
+		return fq.mont.mulBig(a, a)
+	}
 	m := new(big.Int).Mul(a, a)
 	return new(big.Int).Mod(m, fq.Q)
 }
 
 // Exp performs the exponential over Fq
 func (fq Fq) Exp(base *big.Int, e *big.Int) *big.Int {
+	if fq.mont != nil {
+		// as in the big.Int path, the sign of e is ignored
+		return fq.mont.expBig(base, new(big.Int).Abs(e))
+	}
 	res := fq.One()
 	rem := fq.Copy(e)
 	exp := base
@@ -113,22 +190,109 @@ func (fq Fq) Exp(base *big.Int, e *big.Int) *big.Int {
 	return res
 }
 
-func (fq Fq) Rand() (*big.Int, error) {
+// Legendre returns the Legendre symbol of a over the Fq: 1 if a is a non zero
+// quadratic residue, -1 if it is a quadratic non residue, and 0 if a is zero
+func (fq Fq) Legendre(a *big.Int) int {
+	aff := fq.Affine(a)
+	if fq.IsZero(aff) {
+		return 0
+	}
+	// Euler's criterion, a^((q-1)/2)
+	e := new(big.Int).Rsh(new(big.Int).Sub(fq.Q, fq.One()), 1)
+	if fq.Equal(fq.Exp(aff, e), fq.One()) {
+		return 1
+	}
+	return -1
+}
 
-	// twoexp := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(maxbits)), nil)
-	// max := new(big.Int).Sub(twoexp, big.NewInt(1))
+// IsSquare returns true if a is a quadratic residue (or zero) over the Fq
+func (fq Fq) IsSquare(a *big.Int) bool {
+	return fq.Legendre(a) >= 0
+}
 
-	maxbits := fq.Q.BitLen()
-	b := make([]byte, (maxbits/8)-1)
-	_, err := rand.Read(b)
-	if err != nil {
-		return nil, err
+// Sqrt returns a square root of a over the Fq, and false if a is not a quadratic residue
+func (fq Fq) Sqrt(a *big.Int) (*big.Int, bool) {
+	aff := fq.Affine(a)
+	switch fq.Legendre(aff) {
+	case 0:
+		return fq.Zero(), true
+	case -1:
+		return nil, false
+	}
+
+	one := fq.One()
+	if new(big.Int).And(fq.Q, big.NewInt(int64(3))).Int64() == 3 {
+		// q = 3 mod 4, sqrt(a) = a^((q+1)/4)
+		e := new(big.Int).Rsh(new(big.Int).Add(fq.Q, one), 2)
+		return fq.Exp(aff, e), true
+	}
+
+	// Tonelli-Shanks
+	// https://en.wikipedia.org/wiki/Tonelli%E2%80%93Shanks_algorithm
+	// q-1 = t * 2^s, with t odd
+	t := new(big.Int).Sub(fq.Q, one)
+	s := 0
+	for !BigIsOdd(t) {
+		t.Rsh(t, 1)
+		s++
+	}
+	// z is a quadratic non residue
+	z := big.NewInt(int64(2))
+	for fq.Legendre(z) != -1 {
+		z = new(big.Int).Add(z, one)
+	}
+
+	m := s
+	c := fq.Exp(z, t)
+	x := fq.Exp(aff, new(big.Int).Rsh(new(big.Int).Add(t, one), 1))
+	b := fq.Exp(aff, t)
+	for !fq.Equal(b, one) {
+		// find the lowest i such that b^(2^i) == 1
+		i := 0
+		b2i := b
+		for !fq.Equal(b2i, one) {
+			b2i = fq.Square(b2i)
+			i++
+		}
+		g := c
+		for j := 0; j < m-i-1; j++ {
+			g = fq.Square(g)
+		}
+		x = fq.Mul(x, g)
+		c = fq.Square(g)
+		b = fq.Mul(b, c)
+		m = i
 	}
-	r := new(big.Int).SetBytes(b)
-	rq := new(big.Int).Mod(r, fq.Q)
+	return x, true
+}
+
+// Rand returns a uniformly random element of Fq, read from crypto/rand
+func (fq Fq) Rand() (*big.Int, error) {
+	return fq.RandFrom(rand.Reader)
+}
 
-	// r over q, nil
-	return rq, nil
+// RandFrom returns a uniformly random element of Fq, read from the given
+// io.Reader. Values over the modulus are rejected and sampled again, so the
+// result is not biased. With a deterministic reader the results can be reproduced
+func (fq Fq) RandFrom(rnd io.Reader) (*big.Int, error) {
+	maxbits := fq.Q.BitLen()
+	b := make([]byte, (maxbits+7)/8)
+	// mask of the bits of the most significant byte that fit in maxbits
+	mask := byte(0xff)
+	if maxbits%8 != 0 {
+		mask = byte(1<<uint(maxbits%8)) - 1
+	}
+	for {
+		_, err := io.ReadFull(rnd, b)
+		if err != nil {
+			return nil, err
+		}
+		b[0] &= mask
+		r := new(big.Int).SetBytes(b)
+		if r.Cmp(fq.Q) < 0 {
+			return r, nil
+		}
+	}
 }
 
 func (fq Fq) IsZero(a *big.Int) bool {
@@ -169,3 +333,32 @@ func BigIsOdd(n *big.Int) bool {
 	and := new(big.Int).And(n, one)
 	return bytes.Equal(and.Bytes(), big.NewInt(int64(1)).Bytes())
 }
+
+// ByteLen returns the number of bytes of the binary encoding of an element of the Fq
+func (fq Fq) ByteLen() int {
+	return (fq.Q.BitLen() + 7) / 8
+}
+
+// MarshalBinary returns the canonical encoding of a: a mod Q in big-endian,
+// padded to ByteLen bytes (32 bytes for the BN128 fields)
+func (fq Fq) MarshalBinary(a *big.Int) []byte {
+	b := make([]byte, fq.ByteLen())
+	new(big.Int).Mod(a, fq.Q).FillBytes(b)
+	return b
+}
+
+// UnmarshalBinary decodes an element encoded with MarshalBinary. It rejects
+// the inputs with a wrong length and the non canonical ones (>= Q)
+func (fq Fq) UnmarshalBinary(b []byte) (*big.Int, error) {
+	if len(b) != fq.ByteLen() {
+		return nil, errors.New("invalid field element length")
+	}
+	a := new(big.Int).SetBytes(b)
+	if a.Cmp(fq.Q) >= 0 {
+		return nil, errors.New("field element not lower than the modulus")
+	}
+	if a.Sign() == 0 {
+		return fq.Zero(), nil
+	}
+	return a, nil
+}
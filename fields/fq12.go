@@ -12,15 +12,19 @@ type Fq12 struct {
 	F          Fq6
 	Fq2        Fq2
 	NonResidue [2]*big.Int
+	// frobenius is NonResidue^((q-1)/6), the coefficient of Frobenius.
+	// NewFq12 sets it, the Fq12 built without it compute it on each call
+	frobenius [2]*big.Int
 }
 
 // NewFq12 generates a new Fq12
 func NewFq12(f Fq6, fq2 Fq2, nonResidue [2]*big.Int) Fq12 {
 	fq12 := Fq12{
-		f,
-		fq2,
-		nonResidue,
+		F:          f,
+		Fq2:        fq2,
+		NonResidue: nonResidue,
 	}
+	fq12.frobenius = frobeniusCoeff(fq2, nonResidue, 6)
 	return fq12
 }
 
@@ -101,6 +105,84 @@ func (fq12 Fq12) MulScalar(base [2][3][2]*big.Int, e *big.Int) [2][3][2]*big.Int
 	return res
 }
 
+// Conjugate returns a^(q^6), which for the elements of the cyclotomic subgroup is also the inverse
+func (fq12 Fq12) Conjugate(a [2][3][2]*big.Int) [2][3][2]*big.Int {
+	return [2][3][2]*big.Int{
+		a[0],
+		fq12.F.Neg(a[1]),
+	}
+}
+
+// Frobenius returns a^q
+func (fq12 Fq12) Frobenius(a [2][3][2]*big.Int) [2][3][2]*big.Int {
+	// w^q = w * NonResidue^((q-1)/6)
+	c := fq12.frobenius
+	if c[0] == nil {
+		c = frobeniusCoeff(fq12.Fq2, fq12.NonResidue, 6)
+	}
+	c1 := fq12.F.Frobenius(a[1])
+	return [2][3][2]*big.Int{
+		fq12.F.Frobenius(a[0]),
+		{
+			fq12.Fq2.Mul(c1[0], c),
+			fq12.Fq2.Mul(c1[1], c),
+			fq12.Fq2.Mul(c1[2], c),
+		},
+	}
+}
+
+// CyclotomicSquare performs the square of an element of the cyclotomic
+// subgroup (the elements with a^(q^6-1)(q^2+1) = 1, as the ones after the
+// easy part of the final exponentiation). For other values the result is wrong
+func (fq12 Fq12) CyclotomicSquare(a [2][3][2]*big.Int) [2][3][2]*big.Int {
+	// Granger-Scott, Faster Squaring in the Cyclotomic Subgroup of Sixth Degree Extensions
+	// https://eprint.iacr.org/2009/565.pdf , section 3.2
+	fq2 := fq12.Fq2
+	fq6 := fq12.F
+
+	t0 := fq2.Square(a[1][1])
+	t1 := fq2.Square(a[0][0])
+	// 2 * a[1][1] * a[0][0]
+	t6 := fq2.Sub(fq2.Sub(fq2.Square(fq2.Add(a[1][1], a[0][0])), t0), t1)
+	t2 := fq2.Square(a[0][2])
+	t3 := fq2.Square(a[1][0])
+	// 2 * a[0][2] * a[1][0]
+	t7 := fq2.Sub(fq2.Sub(fq2.Square(fq2.Add(a[0][2], a[1][0])), t2), t3)
+	t4 := fq2.Square(a[1][2])
+	t5 := fq2.Square(a[0][1])
+	// 2 * a[1][2] * a[0][1] * NonResidue
+	t8 := fq6.mulByNonResidue(fq2.Sub(fq2.Sub(fq2.Square(fq2.Add(a[1][2], a[0][1])), t4), t5))
+
+	t0 = fq2.Add(fq6.mulByNonResidue(t0), t1)
+	t2 = fq2.Add(fq6.mulByNonResidue(t2), t3)
+	t4 = fq2.Add(fq6.mulByNonResidue(t4), t5)
+
+	return [2][3][2]*big.Int{
+		{
+			fq2.Add(fq2.Double(fq2.Sub(t0, a[0][0])), t0),
+			fq2.Add(fq2.Double(fq2.Sub(t2, a[0][1])), t2),
+			fq2.Add(fq2.Double(fq2.Sub(t4, a[0][2])), t4),
+		},
+		{
+			fq2.Add(fq2.Double(fq2.Add(t8, a[1][0])), t8),
+			fq2.Add(fq2.Double(fq2.Add(t6, a[1][1])), t6),
+			fq2.Add(fq2.Double(fq2.Add(t7, a[1][2])), t7),
+		},
+	}
+}
+
+// CyclotomicExp performs the exponential of an element of the cyclotomic subgroup, using CyclotomicSquare
+func (fq12 Fq12) CyclotomicExp(base [2][3][2]*big.Int, e *big.Int) [2][3][2]*big.Int {
+	res := fq12.One()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = fq12.CyclotomicSquare(res)
+		if e.Bit(i) == 1 {
+			res = fq12.Mul(res, base)
+		}
+	}
+	return res
+}
+
 // Inverse returns the inverse on the Fq12
 func (fq12 Fq12) Inverse(a [2][3][2]*big.Int) [2][3][2]*big.Int {
 	t0 := fq12.F.Square(a[0])
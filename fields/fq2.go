@@ -1,6 +1,7 @@
 package fields
 
 import (
+	"errors"
 	"math/big"
 )
 
@@ -63,6 +64,9 @@ func (fq2 Fq2) Neg(a [2]*big.Int) [2]*big.Int {
 func (fq2 Fq2) Mul(a, b [2]*big.Int) [2]*big.Int {
 	// Multiplication and Squaring on Pairing-Friendly.pdf; Section 3 (Karatsuba)
 	// https://pdfs.semanticscholar.org/3e01/de88d7428076b2547b60072088507d881bf1.pdf
+	if m := fq2.F.mont; m != nil {
+		return m.mulFq2Big(a, b, fq2.NonResidue)
+	}
 	v0 := fq2.F.Mul(a[0], b[0])
 	v1 := fq2.F.Mul(a[1], b[1])
 	return [2]*big.Int{
@@ -95,6 +99,30 @@ func (fq2 Fq2) MulScalar(p [2]*big.Int, e *big.Int) [2]*big.Int {
 	return q
 }
 
+// Exp performs the exponential over Fq2
+func (fq2 Fq2) Exp(base [2]*big.Int, e *big.Int) [2]*big.Int {
+	res := fq2.One()
+	rem := fq2.F.Copy(e)
+	exp := base
+
+	for !fq2.F.IsZero(rem) {
+		if BigIsOdd(rem) {
+			res = fq2.Mul(res, exp)
+		}
+		exp = fq2.Square(exp)
+		rem = new(big.Int).Rsh(rem, 1)
+	}
+	return res
+}
+
+// Frobenius returns a^q. As the NonResidue is not a square, u^q = -u, so it is the conjugate of a
+func (fq2 Fq2) Frobenius(a [2]*big.Int) [2]*big.Int {
+	return [2]*big.Int{
+		a[0],
+		fq2.F.Neg(a[1]),
+	}
+}
+
 // Inverse returns the inverse on the Fq2
 func (fq2 Fq2) Inverse(a [2]*big.Int) [2]*big.Int {
 	// High-Speed Software Implementation of the Optimal Ate Pairing over Barreto–Naehrig Curves .pdf
@@ -109,6 +137,27 @@ func (fq2 Fq2) Inverse(a [2]*big.Int) [2]*big.Int {
 	}
 }
 
+// BatchInverse returns the inverses of all the given values doing a single
+// inversion over Fq. The zero values are returned as zero
+func (fq2 Fq2) BatchInverse(a [][2]*big.Int) [][2]*big.Int {
+	// as in Inverse, 1/a = (a0 - a1*u) / (a0² - NonResidue*a1²)
+	norms := make([]*big.Int, len(a))
+	for i := 0; i < len(a); i++ {
+		norms[i] = fq2.F.Sub(
+			fq2.F.Square(a[i][0]),
+			fq2.mulByNonResidue(fq2.F.Square(a[i][1])))
+	}
+	invNorms := fq2.F.BatchInverse(norms)
+	res := make([][2]*big.Int, len(a))
+	for i := 0; i < len(a); i++ {
+		res[i] = [2]*big.Int{
+			fq2.F.Mul(a[i][0], invNorms[i]),
+			fq2.F.Neg(fq2.F.Mul(a[i][1], invNorms[i])),
+		}
+	}
+	return res
+}
+
 // Div performs a division on the Fq2
 func (fq2 Fq2) Div(a, b [2]*big.Int) [2]*big.Int {
 	return fq2.Mul(a, fq2.Inverse(b))
@@ -117,6 +166,9 @@ func (fq2 Fq2) Div(a, b [2]*big.Int) [2]*big.Int {
 // Square performs a square operation on the Fq2
 func (fq2 Fq2) Square(a [2]*big.Int) [2]*big.Int {
 	// https://pdfs.semanticscholar.org/3e01/de88d7428076b2547b60072088507d881bf1.pdf , complex squaring
+	if m := fq2.F.mont; m != nil {
+		return m.mulFq2Big(a, a, fq2.NonResidue)
+	}
 	ab := fq2.F.Mul(a[0], a[1])
 	return [2]*big.Int{
 		fq2.F.Sub(
@@ -132,6 +184,42 @@ func (fq2 Fq2) Square(a [2]*big.Int) [2]*big.Int {
 	}
 }
 
+// Sqrt returns a square root of a over the Fq2, and false if a is not a quadratic residue
+func (fq2 Fq2) Sqrt(a [2]*big.Int) ([2]*big.Int, bool) {
+	// a = x² with x = x0 + x1*u, u² = NonResidue, gives
+	// a0 = x0² + NonResidue*x1², a1 = 2*x0*x1
+	a = fq2.Affine(a)
+	if fq2.F.IsZero(a[1]) {
+		if x0, ok := fq2.F.Sqrt(a[0]); ok {
+			return [2]*big.Int{x0, fq2.F.Zero()}, true
+		}
+		// a0 is not a square in Fq, so a0/NonResidue is
+		x1, ok := fq2.F.Sqrt(fq2.F.Div(a[0], fq2.NonResidue))
+		if !ok {
+			return [2]*big.Int{}, false
+		}
+		return [2]*big.Int{fq2.F.Zero(), x1}, true
+	}
+
+	// a is a square in Fq2 iff its norm a0² - NonResidue*a1² is a square in Fq
+	norm := fq2.F.Sub(fq2.F.Square(a[0]), fq2.mulByNonResidue(fq2.F.Square(a[1])))
+	lambda, ok := fq2.F.Sqrt(norm)
+	if !ok {
+		return [2]*big.Int{}, false
+	}
+	two := big.NewInt(int64(2))
+	// x0² = (a0 ± lambda) / 2
+	x0, ok := fq2.F.Sqrt(fq2.F.Div(fq2.F.Add(a[0], lambda), two))
+	if !ok {
+		x0, ok = fq2.F.Sqrt(fq2.F.Div(fq2.F.Sub(a[0], lambda), two))
+		if !ok {
+			return [2]*big.Int{}, false
+		}
+	}
+	x1 := fq2.F.Div(a[1], fq2.F.Double(x0))
+	return [2]*big.Int{x0, x1}, true
+}
+
 func (fq2 Fq2) IsZero(a [2]*big.Int) bool {
 	return fq2.F.IsZero(a[0]) && fq2.F.IsZero(a[1])
 }
@@ -152,3 +240,26 @@ func (fq2 Fq2) Copy(a [2]*big.Int) [2]*big.Int {
 		fq2.F.Copy(a[1]),
 	}
 }
+
+// MarshalBinary returns the canonical encoding of a: the encoding of a[1]
+// followed by the one of a[0], as in the Ethereum precompiles (64 bytes for the BN128 Fq2)
+func (fq2 Fq2) MarshalBinary(a [2]*big.Int) []byte {
+	return append(fq2.F.MarshalBinary(a[1]), fq2.F.MarshalBinary(a[0])...)
+}
+
+// UnmarshalBinary decodes an element encoded with MarshalBinary, rejecting the non canonical inputs
+func (fq2 Fq2) UnmarshalBinary(b []byte) ([2]*big.Int, error) {
+	n := fq2.F.ByteLen()
+	if len(b) != 2*n {
+		return [2]*big.Int{}, errors.New("invalid field element length")
+	}
+	a1, err := fq2.F.UnmarshalBinary(b[:n])
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	a0, err := fq2.F.UnmarshalBinary(b[n:])
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	return [2]*big.Int{a0, a1}, nil
+}
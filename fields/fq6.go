@@ -9,14 +9,20 @@ import (
 type Fq6 struct {
 	F          Fq2
 	NonResidue [2]*big.Int
+	// frobenius holds NonResidue^((q-1)/3) and its square, the coefficients
+	// of Frobenius. NewFq6 sets it, the Fq6 built without it compute them on
+	// each call
+	frobenius [2][2]*big.Int
 }
 
 // NewFq6 generates a new Fq6
 func NewFq6(f Fq2, nonResidue [2]*big.Int) Fq6 {
 	fq6 := Fq6{
-		f,
-		nonResidue,
+		F:          f,
+		NonResidue: nonResidue,
 	}
+	c := frobeniusCoeff(f, nonResidue, 3)
+	fq6.frobenius = [2][2]*big.Int{c, f.Square(c)}
 	return fq6
 }
 
@@ -112,6 +118,27 @@ func (fq6 Fq6) MulScalar(base [3][2]*big.Int, e *big.Int) [3][2]*big.Int {
 	return res
 }
 
+// frobeniusCoeff returns nonResidue^((q-1)/d) over the Fq2
+func frobeniusCoeff(f Fq2, nonResidue [2]*big.Int, d int64) [2]*big.Int {
+	e := new(big.Int).Div(new(big.Int).Sub(f.F.Q, f.F.One()), big.NewInt(d))
+	return f.Exp(nonResidue, e)
+}
+
+// Frobenius returns a^q
+func (fq6 Fq6) Frobenius(a [3][2]*big.Int) [3][2]*big.Int {
+	// v^q = v * NonResidue^((q-1)/3)
+	c := fq6.frobenius
+	if c[0][0] == nil {
+		c[0] = frobeniusCoeff(fq6.F, fq6.NonResidue, 3)
+		c[1] = fq6.F.Square(c[0])
+	}
+	return [3][2]*big.Int{
+		fq6.F.Frobenius(a[0]),
+		fq6.F.Mul(fq6.F.Frobenius(a[1]), c[0]),
+		fq6.F.Mul(fq6.F.Frobenius(a[2]), c[1]),
+	}
+}
+
 // Inverse returns the inverse on the Fq6
 func (fq6 Fq6) Inverse(a [3][2]*big.Int) [3][2]*big.Int {
 	t0 := fq6.F.Square(a[0])
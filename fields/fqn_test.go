@@ -1,7 +1,9 @@
 package fields
 
 import (
+	"bytes"
 	"math/big"
+	mrand "math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -94,7 +96,7 @@ func TestFq6(t *testing.T) {
 	nonResidueFq6 := iiToBig(9, 1)
 
 	fq2 := Fq2{fq1, nonResidueFq2}
-	fq6 := Fq6{fq2, nonResidueFq6}
+	fq6 := Fq6{F: fq2, NonResidue: nonResidueFq6}
 
 	a := [3][2]*big.Int{
 		iiToBig(1, 2),
@@ -119,8 +121,8 @@ func TestFq12(t *testing.T) {
 	nonResidueFq6 := iiToBig(9, 1)
 
 	fq2 := Fq2{fq1, nonResidueFq2}
-	fq6 := Fq6{fq2, nonResidueFq6}
-	fq12 := Fq12{fq6, fq2, nonResidueFq6}
+	fq6 := Fq6{F: fq2, NonResidue: nonResidueFq6}
+	fq12 := Fq12{F: fq6, Fq2: fq2, NonResidue: nonResidueFq6}
 
 	a := [2][3][2]*big.Int{
 		{
@@ -167,3 +169,163 @@ func TestFq12(t *testing.T) {
 	divRes := fq12.Div(mulRes, b)
 	assert.Equal(t, fq12.Affine(a), fq12.Affine(divRes))
 }
+
+func TestFqSqrt(t *testing.T) {
+	// 7 = 3 mod 4, and 17 = 1 mod 4 (Tonelli-Shanks)
+	for _, q := range []int{7, 17} {
+		fq := NewFq(iToBig(q))
+		squares := 0
+		for i := 0; i < q; i++ {
+			a := iToBig(i)
+			r, ok := fq.Sqrt(a)
+			assert.Equal(t, fq.IsSquare(a), ok)
+			if ok {
+				squares++
+				assert.Equal(t, a, fq.Square(r))
+			}
+		}
+		// zero and (q-1)/2 quadratic residues
+		assert.Equal(t, 1+(q-1)/2, squares)
+	}
+	fq := NewFq(iToBig(7))
+	assert.Equal(t, 0, fq.Legendre(iToBig(0)))
+	assert.Equal(t, 1, fq.Legendre(iToBig(2)))
+	assert.Equal(t, -1, fq.Legendre(iToBig(3)))
+	assert.Equal(t, 1, fq.Legendre(iToBig(-5)))
+}
+
+func TestFq2Sqrt(t *testing.T) {
+	fq2 := NewFq2(NewFq(iToBig(7)), iToBig(-1))
+	squares := 0
+	for i := 0; i < 7; i++ {
+		for j := 0; j < 7; j++ {
+			a := iiToBig(i, j)
+			r, ok := fq2.Sqrt(a)
+			if ok {
+				squares++
+				assert.True(t, fq2.Equal(a, fq2.Square(r)))
+			}
+		}
+	}
+	// zero and (49-1)/2 quadratic residues
+	assert.Equal(t, 1+48/2, squares)
+}
+
+func TestBatchInverse(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	fq1 := NewFq(q)
+	nonResidueFq2, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208582", 10)
+	assert.True(t, ok)
+	fq2 := NewFq2(fq1, nonResidueFq2)
+
+	a := []*big.Int{iToBig(3), iToBig(0), iToBig(123456), iToBig(-5)}
+	inv := fq1.BatchInverse(a)
+	assert.Equal(t, len(a), len(inv))
+	assert.Equal(t, fq1.Inverse(a[0]), inv[0])
+	assert.True(t, fq1.IsZero(inv[1]))
+	assert.Equal(t, fq1.Inverse(a[2]), inv[2])
+	assert.True(t, fq1.Equal(fq1.Inverse(a[3]), inv[3]))
+	assert.Equal(t, 0, len(fq1.BatchInverse(nil)))
+
+	a2 := [][2]*big.Int{iiToBig(1, 2), iiToBig(0, 0), iiToBig(7, 0), iiToBig(0, 9)}
+	inv2 := fq2.BatchInverse(a2)
+	assert.Equal(t, len(a2), len(inv2))
+	assert.Equal(t, fq2.Inverse(a2[0]), inv2[0])
+	assert.True(t, fq2.IsZero(inv2[1]))
+	assert.Equal(t, fq2.Inverse(a2[2]), inv2[2])
+	assert.Equal(t, fq2.Inverse(a2[3]), inv2[3])
+}
+
+func TestFqRandFrom(t *testing.T) {
+	fq := NewFq(iToBig(17))
+	// 0xff & 0x1f = 31 and 0x11 = 17 are over the modulus and rejected, 0x05 is taken
+	r, err := fq.RandFrom(bytes.NewReader([]byte{0xff, 0x11, 0x05}))
+	assert.Nil(t, err)
+	assert.Equal(t, iToBig(5), r)
+
+	// not enough randomness
+	_, err = fq.RandFrom(bytes.NewReader([]byte{0xff}))
+	assert.NotNil(t, err)
+
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(t, ok)
+	fqR := NewFq(q)
+	rnd1 := mrand.New(mrand.NewSource(1))
+	rnd2 := mrand.New(mrand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		a, err := fqR.RandFrom(rnd1)
+		assert.Nil(t, err)
+		b, err := fqR.RandFrom(rnd2)
+		assert.Nil(t, err)
+		assert.Equal(t, a, b)
+		assert.True(t, a.Cmp(q) < 0)
+	}
+}
+
+func TestFqInverseCT(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(t, ok)
+	for _, fq := range []Fq{NewFq(q), {Q: q}} {
+		for i := 0; i < 10; i++ {
+			a, err := fq.Rand()
+			assert.Nil(t, err)
+			assert.Equal(t, fq.Inverse(a), fq.InverseCT(a))
+		}
+		assert.Equal(t, fq.Zero(), fq.InverseCT(fq.Zero()))
+	}
+}
+
+func TestFqMarshalBinary(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	fq := NewFq(q)
+	assert.Equal(t, 32, fq.ByteLen())
+
+	for _, a := range []*big.Int{iToBig(0), iToBig(1), new(big.Int).Sub(q, iToBig(1))} {
+		b := fq.MarshalBinary(a)
+		assert.Equal(t, 32, len(b))
+		a2, err := fq.UnmarshalBinary(b)
+		assert.Nil(t, err)
+		assert.Equal(t, a, a2)
+	}
+	// values out of range are encoded reduced
+	assert.Equal(t, fq.MarshalBinary(iToBig(-1)), fq.MarshalBinary(new(big.Int).Sub(q, iToBig(1))))
+
+	b := make([]byte, 32)
+	q.FillBytes(b)
+	_, err := fq.UnmarshalBinary(b)
+	assert.NotNil(t, err)
+	_, err = fq.UnmarshalBinary(b[1:])
+	assert.NotNil(t, err)
+
+	fq2 := NewFq2(fq, new(big.Int).Sub(q, iToBig(1)))
+	a := iiToBig(5, 7)
+	b = fq2.MarshalBinary(a)
+	assert.Equal(t, 64, len(b))
+	assert.Equal(t, byte(7), b[31])
+	assert.Equal(t, byte(5), b[63])
+	a2, err := fq2.UnmarshalBinary(b)
+	assert.Nil(t, err)
+	assert.Equal(t, a, a2)
+}
+
+func TestFq12Frobenius(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	nonResidueFq2, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208582", 10)
+	assert.True(t, ok)
+	nonResidueFq6 := iiToBig(9, 1)
+
+	// the towers declared without NewFq6 and NewFq12 also have the Frobenius map
+	fq2 := Fq2{NewFq(q), nonResidueFq2}
+	fq6 := Fq6{F: fq2, NonResidue: nonResidueFq6}
+	fq12 := Fq12{F: fq6, Fq2: fq2, NonResidue: nonResidueFq6}
+
+	a := [2][3][2]*big.Int{
+		{iiToBig(1, 2), iiToBig(3, 4), iiToBig(5, 6)},
+		{iiToBig(7, 8), iiToBig(9, 10), iiToBig(11, 12)},
+	}
+	assert.Equal(t, fq12.Exp(a, q), fq12.Frobenius(a))
+	assert.Equal(t, fq12.Frobenius(a), NewFq12(NewFq6(fq2, nonResidueFq6), fq2, nonResidueFq6).Frobenius(a))
+}
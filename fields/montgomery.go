@@ -0,0 +1,307 @@
+package fields
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+)
+
+// Element is a field element stored as 4 little-endian 64-bit limbs. When
+// used with a Montgomery backend it holds the value in Montgomery form
+// (a*R mod q, with R = 2^256)
+type Element [4]uint64
+
+// Montgomery holds the precomputed constants to perform the arithmetic over
+// a modulus of up to 255 bits with fixed size Elements, without allocations
+type Montgomery struct {
+	Q    *big.Int
+	q    Element
+	qInv uint64  // -q^-1 mod 2^64
+	r2   Element // R^2 mod q
+	one  Element // R mod q
+}
+
+// NewMontgomery generates the Montgomery constants for the given modulus,
+// which must be odd and fit in 255 bits
+func NewMontgomery(q *big.Int) (*Montgomery, error) {
+	if q.Sign() <= 0 || q.Bit(0) == 0 {
+		return nil, errors.New("montgomery modulus must be odd and positive")
+	}
+	if q.BitLen() > 255 {
+		return nil, errors.New("montgomery modulus does not fit in 255 bits")
+	}
+	m := &Montgomery{Q: new(big.Int).Set(q)}
+	setBigInt(&m.q, q)
+
+	// -q^-1 mod 2^64, by Newton iteration over the lowest limb
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - m.q[0]*inv
+	}
+	m.qInv = -inv
+
+	r := new(big.Int).Lsh(big.NewInt(1), 256)
+	setBigInt(&m.one, new(big.Int).Mod(r, q))
+	setBigInt(&m.r2, new(big.Int).Mod(new(big.Int).Mul(r, r), q))
+	return m, nil
+}
+
+// setBigInt copies the limbs of a (0 <= a < 2^256) into z
+func setBigInt(z *Element, a *big.Int) {
+	*z = Element{}
+	words := a.Bits()
+	if bits.UintSize == 64 {
+		for i := 0; i < len(words) && i < 4; i++ {
+			z[i] = uint64(words[i])
+		}
+		return
+	}
+	for i := 0; i < len(words) && i < 8; i++ {
+		z[i/2] |= uint64(words[i]) << (32 * uint(i%2))
+	}
+}
+
+// bigInt returns the limbs of x as a *big.Int
+func bigInt(x *Element) *big.Int {
+	if x.IsZero() {
+		// same representation than big.NewInt(0)
+		return new(big.Int)
+	}
+	if bits.UintSize == 64 {
+		words := make([]big.Word, 4)
+		for i := 0; i < 4; i++ {
+			words[i] = big.Word(x[i])
+		}
+		return new(big.Int).SetBits(words)
+	}
+	words := make([]big.Word, 8)
+	for i := 0; i < 8; i++ {
+		words[i] = big.Word(x[i/2] >> (32 * uint(i%2)))
+	}
+	return new(big.Int).SetBits(words)
+}
+
+// IsZero returns true if all the limbs of the Element are zero
+func (x *Element) IsZero() bool {
+	return (x[0] | x[1] | x[2] | x[3]) == 0
+}
+
+// Equal compares two Elements limb by limb
+func (x *Element) Equal(y *Element) bool {
+	return ((x[0] ^ y[0]) | (x[1] ^ y[1]) | (x[2] ^ y[2]) | (x[3] ^ y[3])) == 0
+}
+
+// inRange returns true if 0 <= a < q, that is, if a can be loaded into an Element without reducing it
+func (m *Montgomery) inRange(a *big.Int) bool {
+	return a.Sign() >= 0 && a.Cmp(m.Q) < 0
+}
+
+// Load returns the Element (not in Montgomery form) with the value of a mod q
+func (m *Montgomery) Load(a *big.Int) Element {
+	var z Element
+	if !m.inRange(a) {
+		a = new(big.Int).Mod(a, m.Q)
+	}
+	setBigInt(&z, a)
+	return z
+}
+
+// ToMont returns the Montgomery form of a mod q
+func (m *Montgomery) ToMont(a *big.Int) Element {
+	z := m.Load(a)
+	m.Mul(&z, &z, &m.r2)
+	return z
+}
+
+// FromMont converts back a Montgomery form Element into a *big.Int
+func (m *Montgomery) FromMont(x *Element) *big.Int {
+	z := Element{1}
+	m.Mul(&z, x, &z)
+	return bigInt(&z)
+}
+
+// One returns the One value in Montgomery form
+func (m *Montgomery) One() Element {
+	return m.one
+}
+
+// Add sets z = x + y mod q
+func (m *Montgomery) Add(z, x, y *Element) {
+	var t Element
+	var c uint64
+	t[0], c = bits.Add64(x[0], y[0], 0)
+	t[1], c = bits.Add64(x[1], y[1], c)
+	t[2], c = bits.Add64(x[2], y[2], c)
+	t[3], c = bits.Add64(x[3], y[3], c)
+	m.reduce(z, &t, c)
+}
+
+// Double sets z = 2x mod q
+func (m *Montgomery) Double(z, x *Element) {
+	m.Add(z, x, x)
+}
+
+// Sub sets z = x - y mod q
+func (m *Montgomery) Sub(z, x, y *Element) {
+	var b, c uint64
+	z[0], b = bits.Sub64(x[0], y[0], 0)
+	z[1], b = bits.Sub64(x[1], y[1], b)
+	z[2], b = bits.Sub64(x[2], y[2], b)
+	z[3], b = bits.Sub64(x[3], y[3], b)
+	// if there was a borrow, add q back
+	mask := -b
+	z[0], c = bits.Add64(z[0], m.q[0]&mask, 0)
+	z[1], c = bits.Add64(z[1], m.q[1]&mask, c)
+	z[2], c = bits.Add64(z[2], m.q[2]&mask, c)
+	z[3], _ = bits.Add64(z[3], m.q[3]&mask, c)
+}
+
+// Neg sets z = -x mod q
+func (m *Montgomery) Neg(z, x *Element) {
+	var zero Element
+	m.Sub(z, &zero, x)
+}
+
+// reduce sets z = t - q if the 257 bit value (hi, t) is >= q, or z = t otherwise.
+// The selection is done without branching on the values
+func (m *Montgomery) reduce(z, t *Element, hi uint64) {
+	var d Element
+	var b uint64
+	d[0], b = bits.Sub64(t[0], m.q[0], 0)
+	d[1], b = bits.Sub64(t[1], m.q[1], b)
+	d[2], b = bits.Sub64(t[2], m.q[2], b)
+	d[3], b = bits.Sub64(t[3], m.q[3], b)
+	_, b = bits.Sub64(hi, 0, b)
+	// b == 1 means t < q, so t is kept
+	mask := -b
+	z[0] = (t[0] & mask) | (d[0] &^ mask)
+	z[1] = (t[1] & mask) | (d[1] &^ mask)
+	z[2] = (t[2] & mask) | (d[2] &^ mask)
+	z[3] = (t[3] & mask) | (d[3] &^ mask)
+}
+
+// Mul sets z = x * y * R^-1 mod q, which is the Montgomery multiplication.
+// When x and y are in Montgomery form, the result is also in Montgomery form
+func (m *Montgomery) Mul(z, x, y *Element) {
+	// Coarsely Integrated Operand Scanning (CIOS)
+	// https://www.microsoft.com/en-us/research/wp-content/uploads/1996/01/j37acmon.pdf
+	var t [6]uint64
+	var c, c0, hi, lo uint64
+	for i := 0; i < 4; i++ {
+		// t += x * y[i]
+		c = 0
+		for j := 0; j < 4; j++ {
+			hi, lo = bits.Mul64(x[j], y[i])
+			lo, c0 = bits.Add64(lo, t[j], 0)
+			hi += c0
+			lo, c0 = bits.Add64(lo, c, 0)
+			hi += c0
+			t[j] = lo
+			c = hi
+		}
+		t[4], c0 = bits.Add64(t[4], c, 0)
+		t[5] = c0
+
+		// t = (t + k*q) / 2^64, where k makes the lowest limb zero
+		k := t[0] * m.qInv
+		hi, lo = bits.Mul64(k, m.q[0])
+		_, c0 = bits.Add64(lo, t[0], 0)
+		c = hi + c0
+		for j := 1; j < 4; j++ {
+			hi, lo = bits.Mul64(k, m.q[j])
+			lo, c0 = bits.Add64(lo, t[j], 0)
+			hi += c0
+			lo, c0 = bits.Add64(lo, c, 0)
+			hi += c0
+			t[j-1] = lo
+			c = hi
+		}
+		t[3], c0 = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + c0
+	}
+	m.reduce(z, &Element{t[0], t[1], t[2], t[3]}, t[4])
+}
+
+// Square sets z = x * x * R^-1 mod q
+func (m *Montgomery) Square(z, x *Element) {
+	m.Mul(z, x, x)
+}
+
+// Exp sets z = x^e, with x and z in Montgomery form. The running time depends only on the bit length of e
+func (m *Montgomery) Exp(z, x *Element, e *big.Int) {
+	res := m.one
+	base := *x
+	var prod Element
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		m.Square(&res, &res)
+		m.Mul(&prod, &res, &base)
+		m.Select(&res, &prod, &res, e.Bit(i))
+	}
+	*z = res
+}
+
+// Select sets z = x if cond == 1, or z = y if cond == 0, without branching
+func (m *Montgomery) Select(z, x, y *Element, cond uint) {
+	mask := -uint64(cond & 1)
+	z[0] = (x[0] & mask) | (y[0] &^ mask)
+	z[1] = (x[1] & mask) | (y[1] &^ mask)
+	z[2] = (x[2] & mask) | (y[2] &^ mask)
+	z[3] = (x[3] & mask) | (y[3] &^ mask)
+}
+
+// mulBig computes a*b mod q for 0 <= a, b < q (not in Montgomery form):
+// (a*b*R^-1) * R^2 * R^-1 = a*b
+func (m *Montgomery) mulBig(a, b *big.Int) *big.Int {
+	x := m.Load(a)
+	y := m.Load(b)
+	m.Mul(&x, &x, &y)
+	m.Mul(&x, &x, &m.r2)
+	return bigInt(&x)
+}
+
+func (m *Montgomery) addBig(a, b *big.Int) *big.Int {
+	x := m.Load(a)
+	y := m.Load(b)
+	m.Add(&x, &x, &y)
+	return bigInt(&x)
+}
+
+func (m *Montgomery) subBig(a, b *big.Int) *big.Int {
+	x := m.Load(a)
+	y := m.Load(b)
+	m.Sub(&x, &x, &y)
+	return bigInt(&x)
+}
+
+func (m *Montgomery) expBig(a, e *big.Int) *big.Int {
+	x := m.ToMont(a)
+	m.Exp(&x, &x, e)
+	return m.FromMont(&x)
+}
+
+// mulFq2Big computes (a0 + a1*u) * (b0 + b1*u) with u^2 = nonResidue, for
+// coordinates not in Montgomery form. The coordinates are loaded once and
+// chained without converting them: each Mul of two loaded values gives the
+// product times R^-1, which the additions keep, and only the two results are
+// brought back with a Mul by R^2
+func (m *Montgomery) mulFq2Big(a, b [2]*big.Int, nonResidue *big.Int) [2]*big.Int {
+	a0, a1 := m.Load(a[0]), m.Load(a[1])
+	b0, b1 := m.Load(b[0]), m.Load(b[1])
+	nr := m.ToMont(nonResidue)
+
+	// Karatsuba, as in Fq2.Mul
+	var v0, v1, s0, s1 Element
+	m.Mul(&v0, &a0, &b0)
+	m.Mul(&v1, &a1, &b1)
+	m.Add(&s0, &a0, &a1)
+	m.Add(&s1, &b0, &b1)
+	m.Mul(&s0, &s0, &s1)
+	m.Sub(&s0, &s0, &v0)
+	m.Sub(&s0, &s0, &v1)
+	m.Mul(&v1, &v1, &nr)
+	m.Add(&v0, &v0, &v1)
+
+	m.Mul(&v0, &v0, &m.r2)
+	m.Mul(&s0, &s0, &m.r2)
+	return [2]*big.Int{bigInt(&v0), bigInt(&s0)}
+}
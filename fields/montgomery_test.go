@@ -0,0 +1,87 @@
+package fields
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMontgomeryMatchesBigInt(t *testing.T) {
+	for _, qStr := range []string{
+		"21888242871839275222246405745257275088696311157297823662689037894645226208583", // BN128 Q
+		"21888242871839275222246405745257275088548364400416034343698204186575808495617", // BN128 R
+	} {
+		q, ok := new(big.Int).SetString(qStr, 10)
+		assert.True(t, ok)
+		fqMont := NewFq(q)
+		fqBig := Fq{Q: q}
+		assert.NotNil(t, fqMont.Montgomery())
+		assert.Nil(t, fqBig.Montgomery())
+
+		for i := 0; i < 100; i++ {
+			a, err := fqBig.Rand()
+			assert.Nil(t, err)
+			b, err := fqBig.Rand()
+			assert.Nil(t, err)
+
+			assert.Equal(t, fqBig.Add(a, b), fqMont.Add(a, b))
+			assert.Equal(t, fqBig.Double(a), fqMont.Double(a))
+			assert.Equal(t, fqBig.Sub(a, b), fqMont.Sub(a, b))
+			assert.Equal(t, fqBig.Neg(a), fqMont.Neg(a))
+			assert.Equal(t, fqBig.Mul(a, b), fqMont.Mul(a, b))
+			assert.Equal(t, fqBig.Square(a), fqMont.Square(a))
+			assert.Equal(t, fqBig.Div(a, b), fqMont.Div(a, b))
+			assert.Equal(t, fqBig.Exp(a, b), fqMont.Exp(a, b))
+		}
+
+		// values out of the [0, q) range are reduced as in the big.Int path
+		neg := big.NewInt(-5)
+		over := new(big.Int).Add(q, big.NewInt(3))
+		assert.Equal(t, fqBig.Mul(neg, over), fqMont.Mul(neg, over))
+		assert.Equal(t, fqBig.Add(neg, over), fqMont.Add(neg, over))
+		assert.Equal(t, fqBig.Sub(neg, over), fqMont.Sub(neg, over))
+		assert.Equal(t, fqBig.Exp(over, neg), fqMont.Exp(over, neg))
+		assert.Equal(t, fqBig.Sub(over, over), fqMont.Sub(over, over))
+	}
+}
+
+func TestMontgomeryTower(t *testing.T) {
+	q, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+	assert.True(t, ok)
+	nonResidueFq2, ok := new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208582", 10)
+	assert.True(t, ok)
+	nonResidueFq6 := iiToBig(9, 1)
+
+	fq2Mont := NewFq2(NewFq(q), nonResidueFq2)
+	fq6Mont := NewFq6(fq2Mont, nonResidueFq6)
+	fq12Mont := NewFq12(fq6Mont, fq2Mont, nonResidueFq6)
+	fq2Big := NewFq2(Fq{Q: q}, nonResidueFq2)
+	fq6Big := NewFq6(fq2Big, nonResidueFq6)
+	fq12Big := NewFq12(fq6Big, fq2Big, nonResidueFq6)
+
+	var a, b [2][3][2]*big.Int
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 2; k++ {
+				var err error
+				a[i][j][k], err = fq2Big.F.Rand()
+				assert.Nil(t, err)
+				b[i][j][k], err = fq2Big.F.Rand()
+				assert.Nil(t, err)
+			}
+		}
+	}
+
+	assert.Equal(t, fq2Big.Mul(a[0][0], b[0][0]), fq2Mont.Mul(a[0][0], b[0][0]))
+	assert.Equal(t, fq2Big.Square(a[0][0]), fq2Mont.Square(a[0][0]))
+	over := [2]*big.Int{new(big.Int).Add(q, big.NewInt(3)), big.NewInt(-5)}
+	assert.Equal(t, fq2Big.Mul(over, b[0][0]), fq2Mont.Mul(over, b[0][0]))
+	assert.Equal(t, fq2Big.Inverse(a[0][0]), fq2Mont.Inverse(a[0][0]))
+	assert.Equal(t, fq6Big.Mul(a[0], b[0]), fq6Mont.Mul(a[0], b[0]))
+	assert.Equal(t, fq6Big.Inverse(a[0]), fq6Mont.Inverse(a[0]))
+	assert.Equal(t, fq12Big.Mul(a, b), fq12Mont.Mul(a, b))
+	assert.Equal(t, fq12Big.Square(a), fq12Mont.Square(a))
+	assert.Equal(t, fq12Big.Inverse(a), fq12Mont.Inverse(a))
+	assert.Equal(t, fq12Big.Exp(a, big.NewInt(1234567)), fq12Mont.Exp(a, big.NewInt(1234567)))
+}
@@ -3,43 +3,49 @@
 package groth16
 
 import (
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/arnaucube/go-snark/bn128"
 	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/curve"
 	"github.com/arnaucube/go-snark/fields"
 	"github.com/arnaucube/go-snark/r1csqap"
 )
 
 type Pk struct { // Proving Key
-	BACDelta [][3]*big.Int // {( βui(x)+αvi(x)+wi(x) ) / δ } from l+1 to m
+	BACDelta []bn128.G1Point // {( βui(x)+αvi(x)+wi(x) ) / δ } from l+1 to m
 	Z        []*big.Int
 	G1       struct {
-		Alpha    [3]*big.Int
-		Beta     [3]*big.Int
-		Delta    [3]*big.Int
-		At       [][3]*big.Int // {a(τ)} from 0 to m
-		BACGamma [][3]*big.Int // {( βui(x)+αvi(x)+wi(x) ) / γ } from 0 to m
+		Alpha    bn128.G1Point
+		Beta     bn128.G1Point
+		Delta    bn128.G1Point
+		At       []bn128.G1Point // {a(τ)} from 0 to m
+		BACGamma []bn128.G1Point // {( βui(x)+αvi(x)+wi(x) ) / γ } from 0 to m
 	}
 	G2 struct {
-		Beta     [3][2]*big.Int
-		Gamma    [3][2]*big.Int
-		Delta    [3][2]*big.Int
-		BACGamma [][3][2]*big.Int // {( βui(x)+αvi(x)+wi(x) ) / γ } from 0 to m
+		Beta     bn128.G2Point
+		Gamma    bn128.G2Point
+		Delta    bn128.G2Point
+		BACGamma []bn128.G2Point // {( βui(x)+αvi(x)+wi(x) ) / γ } from 0 to m
 	}
-	PowersTauDelta [][3]*big.Int // powers of τ encrypted in G1 curve, divided by δ
+	PowersTauDelta []bn128.G1Point // powers of τ encrypted in G1 curve, divided by δ
+	Curve          curve.Curve     `json:"-"` // curve of the points, nil is Utils.Curve
 }
 type Vk struct {
-	IC [][3]*big.Int
+	IC []bn128.G1Point
 	G1 struct {
-		Alpha [3]*big.Int
+		Alpha bn128.G1Point
 	}
 	G2 struct {
-		Beta  [3][2]*big.Int
-		Gamma [3][2]*big.Int
-		Delta [3][2]*big.Int
+		Beta  bn128.G2Point
+		Gamma bn128.G2Point
+		Delta bn128.G2Point
 	}
+	Curve curve.Curve `json:"-"` // curve of the points, nil is Utils.Curve
 }
 
 // Setup is the data structure holding the Trusted Setup data. The Setup.Toxic sub struct must be destroyed after the GenerateTrustedSetup function is completed
@@ -59,18 +65,33 @@ type Setup struct {
 
 // Proof contains the parameters to proof the zkSNARK
 type Proof struct {
-	PiA [3]*big.Int
-	PiB [3][2]*big.Int
-	PiC [3]*big.Int
+	PiA   bn128.G1Point
+	PiB   bn128.G2Point
+	PiC   bn128.G1Point
+	Curve curve.Curve `json:"-"` // curve of the points, nil is Utils.Curve
 }
 
 type utils struct {
 	Bn  bn128.Bn128
 	FqR fields.Fq
 	PF  r1csqap.PolynomialField
+	// Curve is the curve of the Pk, Vk and Proof that do not set one, the
+	// BN128 of Bn
+	Curve curve.Curve
+	// ConstantTime enables the constant-time scalar multiplications and
+	// inversions for the values that depend on the secrets (the toxic values,
+	// the witness and the blinding factors). It is slower, so it is opt-in.
+	// The setup and the prover return an error if it is set over a curve
+	// without constant-time arithmetic, as the BLS12-381.
+	// Only the scalar ladder (bn128.MulScalarCT and MultiExpCT) and the
+	// exponentiation of fields.Fq.InverseCT run in constant time: the secrets
+	// are *big.Int, and their conversions to and from the Montgomery form and
+	// the rest of the FqR arithmetic over them (as the QAP evaluations) do
+	// branch and allocate depending on their values
+	ConstantTime bool
 }
 
-// Utils is the data structure holding the BN128, FqR Finite Field over R, PolynomialField, that will be used inside the snarks operations
+// Utils is the data structure holding the BN128, FqR Finite Field over R, PolynomialField, that will be used inside the snarks operations over the default curve
 var Utils = prepareUtils()
 
 func prepareUtils() utils {
@@ -84,222 +105,443 @@ func prepareUtils() utils {
 	pf := r1csqap.NewPolynomialField(fqR)
 
 	return utils{
-		Bn:  bn,
-		FqR: fqR,
-		PF:  pf,
+		Bn:    bn,
+		FqR:   fqR,
+		PF:    pf,
+		Curve: curve.FromBn128(bn),
 	}
 }
 
+// curveOps holds the groups and fields of a curve used by the snark operations
+type curveOps struct {
+	c   curve.Curve
+	g1  bn128.G1
+	g2  bn128.G2
+	fqR fields.Fq
+	pf  r1csqap.PolynomialField
+}
+
+// opsOf returns the curveOps of the curve c, or of Utils.Curve if c is nil
+func opsOf(c curve.Curve) curveOps {
+	if c == nil {
+		c = Utils.Curve
+	}
+	fqR := c.Fr()
+	return curveOps{
+		c:   c,
+		g1:  c.G1(),
+		g2:  c.G2(),
+		fqR: fqR,
+		pf:  r1csqap.NewPolynomialField(fqR),
+	}
+}
+
+// checkConstantTime returns an error if Utils.ConstantTime is set and the
+// curve has no constant-time arithmetic (the fields without the Montgomery
+// backend, as the Fq of the BLS12-381)
+func (o curveOps) checkConstantTime() error {
+	if !Utils.ConstantTime {
+		return nil
+	}
+	if !o.g1.HasConstantTime() || !o.g2.HasConstantTime() || o.fqR.Montgomery() == nil {
+		return fmt.Errorf("constant time is not supported over the %s curve", o.c.Name())
+	}
+	return nil
+}
+
+// inverseSecret returns the inverse over FqR of a secret value, in constant time if Utils.ConstantTime is set
+func (o curveOps) inverseSecret(a *big.Int) *big.Int {
+	if Utils.ConstantTime {
+		return o.fqR.InverseCT(a)
+	}
+	return o.fqR.Inverse(a)
+}
+
 // GenerateTrustedSetup generates the Trusted Setup from a compiled Circuit. The Setup.Toxic sub data structure must be destroyed
-func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, alphas, betas, gammas [][]*big.Int) (Setup, error) {
+func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit) (Setup, error) {
+	return GenerateTrustedSetupWithReader(rand.Reader, witnessLength, circuit)
+}
+
+// GenerateTrustedSetupWithReader generates the Trusted Setup as GenerateTrustedSetup, reading the toxic values from the given randomness source
+func GenerateTrustedSetupWithReader(rnd io.Reader, witnessLength int, circuit circuitcompiler.Circuit) (Setup, error) {
+	return GenerateTrustedSetupWithCurve(Utils.Curve, rnd, witnessLength, circuit)
+}
+
+// GenerateTrustedSetupWithCurve generates the Trusted Setup as
+// GenerateTrustedSetupWithReader over the curve c. The QAP polynomials are
+// evaluated at τ over the scalar field of c (c.Fr()), directly from the sparse
+// R1CS of the circuit
+func GenerateTrustedSetupWithCurve(c curve.Curve, rnd io.Reader, witnessLength int, circuit circuitcompiler.Circuit) (Setup, error) {
 	var setup Setup
 	var err error
+	if circuit.R1CS.NConstraints() == 0 {
+		return Setup{}, errors.New("circuit without R1CS")
+	}
+	if circuit.R1CS.NSignals != len(circuit.Signals) {
+		return Setup{}, errors.New("R1CS with a different number of signals than the circuit")
+	}
+	o := opsOf(c)
+	if err := o.checkConstantTime(); err != nil {
+		return Setup{}, err
+	}
+	setup.Pk.Curve = o.c
+	setup.Vk.Curve = o.c
+	gm := bn128.NewGeneratorMul(o.g1, o.g2, Utils.ConstantTime)
 
 	// generate random t value
-	setup.Toxic.T, err = Utils.FqR.Rand()
+	setup.Toxic.T, err = o.fqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
 
-	setup.Toxic.Kalpha, err = Utils.FqR.Rand()
+	setup.Toxic.Kalpha, err = o.fqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
-	setup.Toxic.Kbeta, err = Utils.FqR.Rand()
+	setup.Toxic.Kbeta, err = o.fqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
-	setup.Toxic.Kgamma, err = Utils.FqR.Rand()
+	setup.Toxic.Kgamma, err = o.fqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
-	setup.Toxic.Kdelta, err = Utils.FqR.Rand()
+	setup.Toxic.Kdelta, err = o.fqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
 
-	// z pol
-	zpol := []*big.Int{big.NewInt(int64(1))}
-	for i := 1; i < len(alphas)-1; i++ {
-		zpol = Utils.PF.Mul(
-			zpol,
-			[]*big.Int{
-				Utils.FqR.Neg(
-					big.NewInt(int64(i))),
-				big.NewInt(int64(1)),
-			})
+	// z pol, the vanishing polynomial of the domain of the QAP
+	domain, err := o.pf.NewEvaluationDomain(circuit.R1CS.NConstraints())
+	if err != nil {
+		return Setup{}, err
 	}
+	zpol := domain.VanishingPolynomial()
 	setup.Pk.Z = zpol
-	zt := Utils.PF.Eval(zpol, setup.Toxic.T)
-	invDelta := Utils.FqR.Inverse(setup.Toxic.Kdelta)
-	ztinvDelta := Utils.FqR.Mul(invDelta, zt)
+	// the QAP polynomials of each signal and z(x) evaluated at τ
+	ats, bts, cts, zt := o.pf.EvalQAP(circuit.R1CS, setup.Toxic.T)
+	invDelta := o.inverseSecret(setup.Toxic.Kdelta)
+	ztinvDelta := o.fqR.Mul(invDelta, zt)
 
 	// encrypt t values with curve generators
 	// powers of tau divided by delta
 	var ptd [][3]*big.Int
-	ini := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, ztinvDelta)
+	ini := gm.G1(ztinvDelta)
 	ptd = append(ptd, ini)
 	tEncr := setup.Toxic.T
 	for i := 1; i < len(zpol); i++ {
-		ptd = append(ptd, Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, Utils.FqR.Mul(tEncr, ztinvDelta)))
-		tEncr = Utils.FqR.Mul(tEncr, setup.Toxic.T)
+		ptd = append(ptd, gm.G1(o.fqR.Mul(tEncr, ztinvDelta)))
+		tEncr = o.fqR.Mul(tEncr, setup.Toxic.T)
 	}
 	// powers of τ encrypted in G1 curve, divided by δ
 	// (G1 * τ) / δ
-	setup.Pk.PowersTauDelta = ptd
-
-	setup.Pk.G1.Alpha = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, setup.Toxic.Kalpha)
-	setup.Pk.G1.Beta = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, setup.Toxic.Kbeta)
-	setup.Pk.G1.Delta = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, setup.Toxic.Kdelta)
-	setup.Pk.G2.Beta = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kbeta)
-	setup.Pk.G2.Delta = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kdelta)
-
-	setup.Vk.G1.Alpha = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, setup.Toxic.Kalpha)
-	setup.Vk.G2.Beta = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kbeta)
-	setup.Vk.G2.Gamma = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kgamma)
-	setup.Vk.G2.Delta = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kdelta)
-
+	powersTauDelta := ptd
+
+	setup.Pk.G1.Alpha = o.g1.Point(gm.G1(setup.Toxic.Kalpha))
+	setup.Pk.G1.Beta = o.g1.Point(gm.G1(setup.Toxic.Kbeta))
+	setup.Pk.G1.Delta = o.g1.Point(gm.G1(setup.Toxic.Kdelta))
+	setup.Pk.G2.Beta = o.g2.Point(gm.G2(setup.Toxic.Kbeta))
+	setup.Pk.G2.Gamma = o.g2.Point(gm.G2(setup.Toxic.Kgamma))
+	setup.Pk.G2.Delta = o.g2.Point(gm.G2(setup.Toxic.Kdelta))
+
+	setup.Vk.G1.Alpha = o.g1.Point(gm.G1(setup.Toxic.Kalpha))
+	setup.Vk.G2.Beta = o.g2.Point(gm.G2(setup.Toxic.Kbeta))
+	setup.Vk.G2.Gamma = o.g2.Point(gm.G2(setup.Toxic.Kgamma))
+	setup.Vk.G2.Delta = o.g2.Point(gm.G2(setup.Toxic.Kdelta))
+
+	var at1, bacGamma1, bacDelta, icArr [][3]*big.Int
+	var bacGamma2 [][3][2]*big.Int
 	for i := 0; i < len(circuit.Signals); i++ {
 		// Pk.G1.At: {a(τ)} from 0 to m
-		at := Utils.PF.Eval(alphas[i], setup.Toxic.T)
-		a := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, at)
-		setup.Pk.G1.At = append(setup.Pk.G1.At, a)
+		a := gm.G1(ats[i])
+		at1 = append(at1, a)
 
-		bt := Utils.PF.Eval(betas[i], setup.Toxic.T)
-		g1bt := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, bt)
-		g2bt := Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, bt)
+		bt := bts[i]
+		g1bt := gm.G1(bt)
+		g2bt := gm.G2(bt)
 		// G1.BACGamma: {( βui(x)+αvi(x)+wi(x) ) / γ } from 0 to m in G1
-		setup.Pk.G1.BACGamma = append(setup.Pk.G1.BACGamma, g1bt)
+		bacGamma1 = append(bacGamma1, g1bt)
 		// G2.BACGamma: {( βui(x)+αvi(x)+wi(x) ) / γ } from 0 to m in G2
-		setup.Pk.G2.BACGamma = append(setup.Pk.G2.BACGamma, g2bt)
+		bacGamma2 = append(bacGamma2, g2bt)
 	}
 
-	zero3 := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	zero3 := [3]*big.Int{o.g1.F.Zero(), o.g1.F.Zero(), o.g1.F.Zero()}
 	for i := 0; i < circuit.NPublic+1; i++ {
-		setup.Pk.BACDelta = append(setup.Pk.BACDelta, zero3)
+		bacDelta = append(bacDelta, zero3)
 	}
 	for i := circuit.NPublic + 1; i < circuit.NVars; i++ {
-		// TODO calculate all at, bt, ct outside, to avoid repeating calculations
-		at := Utils.PF.Eval(alphas[i], setup.Toxic.T)
-		bt := Utils.PF.Eval(betas[i], setup.Toxic.T)
-		ct := Utils.PF.Eval(gammas[i], setup.Toxic.T)
-		c := Utils.FqR.Mul(
+		at, bt, ct := ats[i], bts[i], cts[i]
+		c := o.fqR.Mul(
 			invDelta,
-			Utils.FqR.Add(
-				Utils.FqR.Add(
-					Utils.FqR.Mul(at, setup.Toxic.Kbeta),
-					Utils.FqR.Mul(bt, setup.Toxic.Kalpha),
+			o.fqR.Add(
+				o.fqR.Add(
+					o.fqR.Mul(at, setup.Toxic.Kbeta),
+					o.fqR.Mul(bt, setup.Toxic.Kalpha),
 				),
 				ct,
 			),
 		)
-		g1c := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, c)
+		g1c := gm.G1(c)
 
 		// Pk.BACDelta: {( βui(x)+αvi(x)+wi(x) ) / δ } from l+1 to m
-		setup.Pk.BACDelta = append(setup.Pk.BACDelta, g1c)
+		bacDelta = append(bacDelta, g1c)
 	}
 
 	for i := 0; i <= circuit.NPublic; i++ {
-		at := Utils.PF.Eval(alphas[i], setup.Toxic.T)
-		bt := Utils.PF.Eval(betas[i], setup.Toxic.T)
-		ct := Utils.PF.Eval(gammas[i], setup.Toxic.T)
-		ic := Utils.FqR.Mul(
-			Utils.FqR.Inverse(setup.Toxic.Kgamma),
-			Utils.FqR.Add(
-				Utils.FqR.Add(
-					Utils.FqR.Mul(at, setup.Toxic.Kbeta),
-					Utils.FqR.Mul(bt, setup.Toxic.Kalpha),
+		at, bt, ct := ats[i], bts[i], cts[i]
+		ic := o.fqR.Mul(
+			o.inverseSecret(setup.Toxic.Kgamma),
+			o.fqR.Add(
+				o.fqR.Add(
+					o.fqR.Mul(at, setup.Toxic.Kbeta),
+					o.fqR.Mul(bt, setup.Toxic.Kalpha),
 				),
 				ct,
 			),
 		)
-		g1ic := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, ic)
+		g1ic := gm.G1(ic)
 		// used in verifier
-		setup.Vk.IC = append(setup.Vk.IC, g1ic)
+		icArr = append(icArr, g1ic)
 	}
 
+	// normalize the points to affine coordinates, with one inversion per array
+	setup.Pk.BACDelta = o.g1.Points(o.g1.BatchAffine(bacDelta))
+	setup.Pk.G1.At = o.g1.Points(o.g1.BatchAffine(at1))
+	setup.Pk.G1.BACGamma = o.g1.Points(o.g1.BatchAffine(bacGamma1))
+	setup.Pk.G2.BACGamma = o.g2.Points(o.g2.BatchAffine(bacGamma2))
+	setup.Pk.PowersTauDelta = o.g1.Points(o.g1.BatchAffine(powersTauDelta))
+	setup.Vk.IC = o.g1.Points(o.g1.BatchAffine(icArr))
+
 	return setup, nil
 }
 
-// GenerateProofs generates all the parameters to proof the zkSNARK from the Circuit, Setup and the Witness
-func GenerateProofs(circuit circuitcompiler.Circuit, pk Pk, w []*big.Int, px []*big.Int) (Proof, error) {
+// GenerateProofs generates all the parameters to proof the zkSNARK from the
+// Circuit (with its R1CS), the proving key and the Witness. The h(x) of the
+// QAP is computed from the R1CS and the Witness, with r1csqap.HPolynomial. It
+// returns an error if the Witness does not satisfy the R1CS
+func GenerateProofs(circuit circuitcompiler.Circuit, pk Pk, w []*big.Int) (Proof, error) {
+	return GenerateProofsWithReader(rand.Reader, circuit, pk, w)
+}
+
+// GenerateProofsWithReader generates the proof as GenerateProofs, reading the blinding values r and s from the given randomness source
+func GenerateProofsWithReader(rnd io.Reader, circuit circuitcompiler.Circuit, pk Pk, w []*big.Int) (Proof, error) {
 	var proof Proof
-	proof.PiA = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiB = Utils.Bn.Fq6.Zero()
-	proof.PiC = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
+	o := opsOf(pk.Curve)
+	if err := o.checkConstantTime(); err != nil {
+		return Proof{}, err
+	}
+	if circuit.R1CS.NConstraints() == 0 {
+		return Proof{}, errors.New("circuit without R1CS")
+	}
+	// a witness that does not satisfy the R1CS gives a proof that does not
+	// verify, so it is refused with the failing constraints
+	if err := circuit.CheckWitness(o.pf, w); err != nil {
+		return Proof{}, err
+	}
+	proof.Curve = o.c
 
-	r, err := Utils.FqR.Rand()
+	r, err := o.fqR.RandFrom(rnd)
 	if err != nil {
 		return Proof{}, err
 	}
-	s, err := Utils.FqR.Rand()
+	s, err := o.fqR.RandFrom(rnd)
 	if err != nil {
 		return Proof{}, err
 	}
 
+	piA := o.g1.MultiExpSecret(bn128.G1Arrays(pk.G1.At[:circuit.NVars]), w[:circuit.NVars], Utils.ConstantTime)
 	// piBG1 will hold all the same than proof.PiB but in G1 curve
-	piBG1 := [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-
-	for i := 0; i < circuit.NVars; i++ {
-		proof.PiA = Utils.Bn.G1.Add(proof.PiA, Utils.Bn.G1.MulScalar(pk.G1.At[i], w[i]))
-		piBG1 = Utils.Bn.G1.Add(piBG1, Utils.Bn.G1.MulScalar(pk.G1.BACGamma[i], w[i]))
-		proof.PiB = Utils.Bn.G2.Add(proof.PiB, Utils.Bn.G2.MulScalar(pk.G2.BACGamma[i], w[i]))
-	}
-	for i := circuit.NPublic + 1; i < circuit.NVars; i++ {
-		proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(pk.BACDelta[i], w[i]))
-	}
+	piBG1 := o.g1.MultiExpSecret(bn128.G1Arrays(pk.G1.BACGamma[:circuit.NVars]), w[:circuit.NVars], Utils.ConstantTime)
+	piB := o.g2.MultiExpSecret(bn128.G2Arrays(pk.G2.BACGamma[:circuit.NVars]), w[:circuit.NVars], Utils.ConstantTime)
+	piC := o.g1.MultiExpSecret(bn128.G1Arrays(pk.BACDelta[circuit.NPublic+1:circuit.NVars]), w[circuit.NPublic+1:circuit.NVars], Utils.ConstantTime)
+	delta1 := pk.G1.Delta.Array()
 
 	// piA = (Σ from 0 to m (pk.A * w[i])) + pk.Alpha1 + r * δ
-	proof.PiA = Utils.Bn.G1.Add(proof.PiA, pk.G1.Alpha)
-	deltaR := Utils.Bn.G1.MulScalar(pk.G1.Delta, r)
-	proof.PiA = Utils.Bn.G1.Add(proof.PiA, deltaR)
+	piA = o.g1.Add(piA, pk.G1.Alpha.Array())
+	deltaR := o.g1.MulScalarSecret(delta1, r, Utils.ConstantTime)
+	piA = o.g1.Add(piA, deltaR)
 
 	// piBG1 = (Σ from 0 to m (pk.B1 * w[i])) + pk.g1.Beta + s * δ
 	// piB = piB2 = (Σ from 0 to m (pk.B2 * w[i])) + pk.g2.Beta + s * δ
-	piBG1 = Utils.Bn.G1.Add(piBG1, pk.G1.Beta)
-	proof.PiB = Utils.Bn.G2.Add(proof.PiB, pk.G2.Beta)
-	deltaSG1 := Utils.Bn.G1.MulScalar(pk.G1.Delta, s)
-	piBG1 = Utils.Bn.G1.Add(piBG1, deltaSG1)
-	deltaSG2 := Utils.Bn.G2.MulScalar(pk.G2.Delta, s)
-	proof.PiB = Utils.Bn.G2.Add(proof.PiB, deltaSG2)
-
-	hx := Utils.PF.DivisorPolynomial(px, pk.Z) // maybe move this calculation to a previous step
+	piBG1 = o.g1.Add(piBG1, pk.G1.Beta.Array())
+	piB = o.g2.Add(piB, pk.G2.Beta.Array())
+	deltaSG1 := o.g1.MulScalarSecret(delta1, s, Utils.ConstantTime)
+	piBG1 = o.g1.Add(piBG1, deltaSG1)
+	deltaSG2 := o.g2.MulScalarSecret(pk.G2.Delta.Array(), s, Utils.ConstantTime)
+	piB = o.g2.Add(piB, deltaSG2)
+
+	hx := o.pf.HPolynomial(circuit.R1CS, w)
+	if len(hx) > len(pk.PowersTauDelta) {
+		return Proof{}, errors.New("the R1CS has more constraints than the proving key")
+	}
 
 	// piC = (Σ from l+1 to m (w[i] * (pk.g1.Beta + pk.g1.Alpha + pk.C)) + h(tau)) / δ) + piA*s + r*piB - r*s*δ
-	for i := 0; i < len(hx); i++ {
-		proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(pk.PowersTauDelta[i], hx[i]))
+	piC = o.g1.Add(piC, o.g1.MultiExpSecret(bn128.G1Arrays(pk.PowersTauDelta[:len(hx)]), hx, Utils.ConstantTime))
+	piC = o.g1.Add(piC, o.g1.MulScalarSecret(piA, s, Utils.ConstantTime))
+	piC = o.g1.Add(piC, o.g1.MulScalarSecret(piBG1, r, Utils.ConstantTime))
+	negRS := o.fqR.Neg(o.fqR.Mul(r, s))
+	piC = o.g1.Add(piC, o.g1.MulScalarSecret(delta1, negRS, Utils.ConstantTime))
+
+	proof.PiA = o.g1.Point(piA)
+	proof.PiB = o.g2.Point(piB)
+	proof.PiC = o.g1.Point(piC)
+	return proof, nil
+}
+
+// ValidateVk returns a bn128.InvalidPointError if any point of the Vk is not in its group
+func ValidateVk(vk Vk) error {
+	o := opsOf(vk.Curve)
+	for i := 0; i < len(vk.IC); i++ {
+		if err := o.g1.Validate(vk.IC[i].Array()); err != nil {
+			return fmt.Errorf("Vk.IC[%d]: %w", i, err)
+		}
+	}
+	if err := o.g1.Validate(vk.G1.Alpha.Array()); err != nil {
+		return fmt.Errorf("Vk.G1.Alpha: %w", err)
+	}
+	if err := o.g2.Validate(vk.G2.Beta.Array()); err != nil {
+		return fmt.Errorf("Vk.G2.Beta: %w", err)
+	}
+	if err := o.g2.Validate(vk.G2.Gamma.Array()); err != nil {
+		return fmt.Errorf("Vk.G2.Gamma: %w", err)
 	}
-	proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(proof.PiA, s))
-	proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(piBG1, r))
-	negRS := Utils.FqR.Neg(Utils.FqR.Mul(r, s))
-	proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(pk.G1.Delta, negRS))
+	if err := o.g2.Validate(vk.G2.Delta.Array()); err != nil {
+		return fmt.Errorf("Vk.G2.Delta: %w", err)
+	}
+	return nil
+}
 
-	return proof, nil
+// ValidateProof returns a bn128.InvalidPointError if any point of the Proof is not in its group
+func ValidateProof(proof Proof) error {
+	o := opsOf(proof.Curve)
+	if err := o.g1.Validate(proof.PiA.Array()); err != nil {
+		return fmt.Errorf("Proof.PiA: %w", err)
+	}
+	if err := o.g2.Validate(proof.PiB.Array()); err != nil {
+		return fmt.Errorf("Proof.PiB: %w", err)
+	}
+	if err := o.g1.Validate(proof.PiC.Array()); err != nil {
+		return fmt.Errorf("Proof.PiC: %w", err)
+	}
+	return nil
 }
 
-// VerifyProof verifies over the BN128 the Pairings of the Proof
-func VerifyProof(vk Vk, proof Proof, publicSignals []*big.Int, debug bool) bool {
+// checkSameCurve returns an error if the Vk and the Proof are over different curves
+func checkSameCurve(vk Vk, proof Proof) error {
+	vkCurve, proofCurve := opsOf(vk.Curve).c, opsOf(proof.Curve).c
+	if vkCurve.Name() != proofCurve.Name() {
+		return fmt.Errorf("Vk over %s and Proof over %s", vkCurve.Name(), proofCurve.Name())
+	}
+	return nil
+}
+
+// checkPublicSignals returns an error if the number of public signals is not
+// the one of the Vk, len(vk.IC) - 1
+func checkPublicSignals(vk Vk, publicSignals []*big.Int) error {
+	if len(publicSignals)+1 != len(vk.IC) {
+		return fmt.Errorf("%d public signals for a Vk with %d", len(publicSignals), len(vk.IC)-1)
+	}
+	return nil
+}
 
-	icPubl := vk.IC[0]
-	for i := 0; i < len(publicSignals); i++ {
-		icPubl = Utils.Bn.G1.Add(icPubl, Utils.Bn.G1.MulScalar(vk.IC[i+1], publicSignals[i]))
+// VerifyProof verifies the Pairings of the Proof over the curve of the Vk. It
+// returns an error (wrapping a bn128.InvalidPointError) without verifying when
+// a point of the Vk or of the Proof is not in its group, when they are over
+// different curves, or when the number of public signals is not the one of
+// the Vk
+func VerifyProof(vk Vk, proof Proof, publicSignals []*big.Int, debug bool) (bool, error) {
+	if err := checkSameCurve(vk, proof); err != nil {
+		return false, err
+	}
+	if err := checkPublicSignals(vk, publicSignals); err != nil {
+		return false, err
+	}
+	if err := ValidateVk(vk); err != nil {
+		return false, err
 	}
+	if err := ValidateProof(proof); err != nil {
+		return false, err
+	}
+	o := opsOf(vk.Curve)
+
+	ic := bn128.G1Arrays(vk.IC)
+	icPubl := o.g1.Add(ic[0], o.g1.MultiExp(ic[1:], publicSignals))
+
+	// e(piA, piB) == e(α, β) * e(icPubl, γ) * e(piC, δ), checked as
+	// e(-piA, piB) * e(α, β) * e(icPubl, γ) * e(piC, δ) == 1
+	if !o.c.PairingCheck(
+		[][3]*big.Int{proof.PiA.Neg().Array(), vk.G1.Alpha.Array(), icPubl, proof.PiC.Array()},
+		[][3][2]*big.Int{proof.PiB.Array(), vk.G2.Beta.Array(), vk.G2.Gamma.Array(), vk.G2.Delta.Array()}) {
+		if debug {
+			fmt.Println("❌ groth16 verification not passed")
+		}
+		return false, nil
+	}
+	if debug {
+		fmt.Println("✓ groth16 verification passed")
+	}
+
+	return true, nil
+}
+
+// PreparedVk is a Vk with the parts of the verification that only depend on
+// the Vk already computed, for the verifiers that check many proofs with the
+// same Vk. It is created with PrepareVk
+type PreparedVk struct {
+	Vk        Vk
+	AlphaBeta bn128.GTElement // e(α, β)
+	GammaNeg  curve.G2Precomp // Miller loop line coefficients of -γ
+	DeltaNeg  curve.G2Precomp // Miller loop line coefficients of -δ
+}
+
+// PrepareVk computes the PreparedVk of the vk. The points of the vk are not
+// checked again when verifying, so it has to be valid (see ValidateVk, the
+// decoders of utils already check it)
+func PrepareVk(vk Vk) PreparedVk {
+	o := opsOf(vk.Curve)
+	return PreparedVk{
+		Vk:        vk,
+		AlphaBeta: bn128.NewGTElement(o.c.GT(), o.c.Pairing(vk.G1.Alpha.Array(), vk.G2.Beta.Array())),
+		GammaNeg:  o.c.PreComputeG2(vk.G2.Gamma.Neg().Array()),
+		DeltaNeg:  o.c.PreComputeG2(vk.G2.Delta.Neg().Array()),
+	}
+}
+
+// VerifyProofPrepared verifies the Proof as VerifyProof, with the
+// precomputations of the PreparedVk, so only the pairing with piB needs its
+// G2 line coefficients. It returns an error (wrapping a
+// bn128.InvalidPointError) without verifying when a point of the Proof is not
+// in its group, when the Proof is over a different curve than the Vk, or when
+// the number of public signals is not the one of the Vk
+func VerifyProofPrepared(pvk PreparedVk, proof Proof, publicSignals []*big.Int, debug bool) (bool, error) {
+	if err := checkSameCurve(pvk.Vk, proof); err != nil {
+		return false, err
+	}
+	if err := checkPublicSignals(pvk.Vk, publicSignals); err != nil {
+		return false, err
+	}
+	if err := ValidateProof(proof); err != nil {
+		return false, err
+	}
+	o := opsOf(pvk.Vk.Curve)
+
+	ic := bn128.G1Arrays(pvk.Vk.IC)
+	icPubl := o.g1.Add(ic[0], o.g1.MultiExp(ic[1:], publicSignals))
 
-	if !Utils.Bn.Fq12.Equal(
-		Utils.Bn.Pairing(proof.PiA, proof.PiB),
-		Utils.Bn.Fq12.Mul(
-			Utils.Bn.Pairing(vk.G1.Alpha, vk.G2.Beta),
-			Utils.Bn.Fq12.Mul(
-				Utils.Bn.Pairing(icPubl, vk.G2.Gamma),
-				Utils.Bn.Pairing(proof.PiC, vk.G2.Delta)))) {
+	// e(piA, piB) == e(α, β) * e(icPubl, γ) * e(piC, δ), checked as
+	// e(piA, piB) * e(icPubl, -γ) * e(piC, -δ) == e(α, β)
+	product := o.c.PairingProduct(
+		[][3]*big.Int{proof.PiA.Array(), icPubl, proof.PiC.Array()},
+		[]curve.G2Precomp{o.c.PreComputeG2(proof.PiB.Array()), pvk.GammaNeg, pvk.DeltaNeg})
+	if !pvk.AlphaBeta.Equal(bn128.NewGTElement(o.c.GT(), product)) {
 		if debug {
 			fmt.Println("❌ groth16 verification not passed")
 		}
-		return false
+		return false, nil
 	}
 	if debug {
 		fmt.Println("✓ groth16 verification passed")
 	}
 
-	return true
+	return true, nil
 }
@@ -2,13 +2,18 @@ package groth16
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	mrand "math/rand"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/arnaucube/go-snark/bn128"
 	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/curve"
 	"github.com/arnaucube/go-snark/r1csqap"
 	"github.com/stretchr/testify/assert"
 )
@@ -60,24 +65,24 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	assert.True(t, !bytes.Equal(alphas[1][1].Bytes(), big.NewInt(int64(0)).Bytes()))
 
 	ax, bx, cx, px := Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
-	assert.Equal(t, 7, len(ax))
-	assert.Equal(t, 7, len(bx))
-	assert.Equal(t, 7, len(cx))
-	assert.Equal(t, 13, len(px))
+	assert.Equal(t, 8, len(ax))
+	assert.Equal(t, 8, len(bx))
+	assert.Equal(t, 8, len(cx))
+	assert.Equal(t, 15, len(px))
 
 	// ---
 	// from here is the GROTH16
 	// ---
 	// calculate trusted setup
 	fmt.Println("groth")
-	setup, err := GenerateTrustedSetup(len(w), *circuit, alphas, betas, gammas)
+	setup, err := GenerateTrustedSetup(len(w), *circuit)
 	assert.Nil(t, err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
 	hx := Utils.PF.DivisorPolynomial(px, setup.Pk.Z)
 	div, rem := Utils.PF.Div(px, setup.Pk.Z)
 	assert.Equal(t, hx, div)
-	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(6))
+	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(8))
 
 	// hx==px/zx so px==hx*zx
 	assert.Equal(t, px, Utils.PF.Mul(hx, setup.Pk.Z))
@@ -85,7 +90,10 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	// check length of polynomials H(x) and Z(x)
 	assert.Equal(t, len(hx), len(px)-len(setup.Pk.Z)+1)
 
-	proof, err := GenerateProofs(*circuit, setup.Pk, w, px)
+	// the prover computes the same h(x) from the R1CS, without px
+	assert.True(t, r1csqap.BigArraysEqual(hx, Utils.PF.HPolynomial(circuit.R1CS, w)))
+
+	proof, err := GenerateProofs(*circuit, setup.Pk, w)
 	assert.Nil(t, err)
 
 	// fmt.Println("\n proofs:")
@@ -97,11 +105,282 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	b35Verif := big.NewInt(int64(35))
 	publicSignalsVerif := []*big.Int{b35Verif}
 	before := time.Now()
-	assert.True(t, VerifyProof(setup.Vk, proof, publicSignalsVerif, true))
+	verified, err := VerifyProof(setup.Vk, proof, publicSignalsVerif, true)
+	assert.Nil(t, err)
+	assert.True(t, verified)
 	fmt.Println("verify proof time elapsed:", time.Since(before))
 
 	// check that with another public input the verification returns false
 	bOtherWrongPublic := big.NewInt(int64(34))
 	wrongPublicSignalsVerif := []*big.Int{bOtherWrongPublic}
-	assert.True(t, !VerifyProof(setup.Vk, proof, wrongPublicSignalsVerif, false))
+	verified, err = VerifyProof(setup.Vk, proof, wrongPublicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	// a witness that does not satisfy the R1CS is refused, with the failing
+	// constraints
+	wrongW := append([]*big.Int{}, w...)
+	wrongW[1] = big.NewInt(int64(34))
+	_, err = GenerateProofs(*circuit, setup.Pk, wrongW)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "constraint 4 (equals(s1, s5): s1==s5 * 1): 35 * 1 != 34")
+	_, err = GenerateProofs(*circuit, setup.Pk, w[:len(w)-1])
+	assert.NotNil(t, err)
+
+	// the points are encoded to JSON in affine coordinates
+	vkJSON, err := json.Marshal(setup.Vk)
+	assert.Nil(t, err)
+	proofJSON, err := json.Marshal(proof)
+	assert.Nil(t, err)
+	var vk2 Vk
+	assert.Nil(t, json.Unmarshal(vkJSON, &vk2))
+	var proof2 Proof
+	assert.Nil(t, json.Unmarshal(proofJSON, &proof2))
+	assert.True(t, proof.PiB.Equal(proof2.PiB))
+	verified, err = VerifyProof(vk2, proof2, publicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+}
+
+func TestGroth16DeterministicReader(t *testing.T) {
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	publicSignals := []*big.Int{big.NewInt(int64(35))}
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, publicSignals)
+	assert.Nil(t, err)
+	circuit.GenerateSparseR1CS()
+
+	// the same seed gives the same setup and proof
+	setup1, err := GenerateTrustedSetupWithReader(mrand.New(mrand.NewSource(1)), len(w), *circuit)
+	assert.Nil(t, err)
+	setup2, err := GenerateTrustedSetupWithReader(mrand.New(mrand.NewSource(1)), len(w), *circuit)
+	assert.Nil(t, err)
+	assert.Equal(t, setup1, setup2)
+
+	proof1, err := GenerateProofsWithReader(mrand.New(mrand.NewSource(2)), *circuit, setup1.Pk, w)
+	assert.Nil(t, err)
+	proof2, err := GenerateProofsWithReader(mrand.New(mrand.NewSource(2)), *circuit, setup1.Pk, w)
+	assert.Nil(t, err)
+	assert.Equal(t, proof1, proof2)
+	verified, err := VerifyProof(setup1.Vk, proof1, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+
+	// another seed gives another proof, which also verifies
+	proof3, err := GenerateProofsWithReader(mrand.New(mrand.NewSource(3)), *circuit, setup1.Pk, w)
+	assert.Nil(t, err)
+	assert.NotEqual(t, proof1, proof3)
+	verified, err = VerifyProof(setup1.Vk, proof3, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+
+	// the points that are not in their groups are rejected before the pairings
+	offCurve := proof1
+	offCurve.PiA = Utils.Bn.G1.Point([3]*big.Int{big.NewInt(int64(1)), big.NewInt(int64(3)), big.NewInt(int64(1))})
+	verified, err = VerifyProof(setup1.Vk, offCurve, publicSignals, false)
+	assert.False(t, verified)
+	var invalid *bn128.InvalidPointError
+	assert.True(t, errors.As(err, &invalid))
+	assert.True(t, errors.Is(err, bn128.ErrNotOnCurve))
+	badVk := setup1.Vk
+	badDelta := Utils.Bn.G2.Double(Utils.Bn.G2.G)
+	badDelta[1] = Utils.Bn.Fq2.Add(badDelta[1], Utils.Bn.Fq2.One())
+	badVk.G2.Delta = Utils.Bn.G2.Point(badDelta)
+	verified, err = VerifyProof(badVk, proof1, publicSignals, false)
+	assert.False(t, verified)
+	assert.True(t, errors.Is(err, bn128.ErrNotOnCurve))
+
+	// the public signals must be as many as in the Vk
+	verified, err = VerifyProof(setup1.Vk, proof1, []*big.Int{}, false)
+	assert.False(t, verified)
+	assert.NotNil(t, err)
+	verified, err = VerifyProof(setup1.Vk, proof1, append(publicSignals, big.NewInt(int64(1))), false)
+	assert.False(t, verified)
+	assert.NotNil(t, err)
+}
+
+func TestGroth16PreparedVk(t *testing.T) {
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	publicSignals := []*big.Int{big.NewInt(int64(35))}
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, publicSignals)
+	assert.Nil(t, err)
+	circuit.GenerateSparseR1CS()
+	setup, err := GenerateTrustedSetupWithReader(mrand.New(mrand.NewSource(1)), len(w), *circuit)
+	assert.Nil(t, err)
+
+	pvk := PrepareVk(setup.Vk)
+	for i := int64(2); i < 4; i++ {
+		proof, err := GenerateProofsWithReader(mrand.New(mrand.NewSource(i)), *circuit, setup.Pk, w)
+		assert.Nil(t, err)
+		verified, err := VerifyProofPrepared(pvk, proof, publicSignals, false)
+		assert.Nil(t, err)
+		assert.True(t, verified)
+
+		verified, err = VerifyProofPrepared(pvk, proof, []*big.Int{big.NewInt(int64(34))}, false)
+		assert.Nil(t, err)
+		assert.False(t, verified)
+
+		offCurve := proof
+		offCurve.PiA = Utils.Bn.G1.Point([3]*big.Int{big.NewInt(int64(1)), big.NewInt(int64(3)), big.NewInt(int64(1))})
+		verified, err = VerifyProofPrepared(pvk, offCurve, publicSignals, false)
+		assert.False(t, verified)
+		assert.True(t, errors.Is(err, bn128.ErrNotOnCurve))
+
+		verified, err = VerifyProofPrepared(pvk, proof, []*big.Int{}, false)
+		assert.False(t, verified)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestGroth16ConstantTime(t *testing.T) {
+	Utils.ConstantTime = true
+	defer func() { Utils.ConstantTime = false }()
+
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	publicSignals := []*big.Int{big.NewInt(int64(35))}
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, publicSignals)
+	assert.Nil(t, err)
+	circuit.GenerateSparseR1CS()
+
+	// the constant-time mode gives the same keys than the default one (the
+	// arrays are normalized to affine, the single points can have other coordinates)
+	setupCT, err := GenerateTrustedSetupWithReader(mrand.New(mrand.NewSource(1)), len(w), *circuit)
+	assert.Nil(t, err)
+	Utils.ConstantTime = false
+	setup, err := GenerateTrustedSetupWithReader(mrand.New(mrand.NewSource(1)), len(w), *circuit)
+	assert.Nil(t, err)
+	Utils.ConstantTime = true
+	assert.Equal(t, setup.Pk.G1.At, setupCT.Pk.G1.At)
+	assert.Equal(t, setup.Pk.G2.BACGamma, setupCT.Pk.G2.BACGamma)
+	assert.Equal(t, setup.Pk.BACDelta, setupCT.Pk.BACDelta)
+	assert.Equal(t, setup.Pk.PowersTauDelta, setupCT.Pk.PowersTauDelta)
+	assert.Equal(t, setup.Vk.IC, setupCT.Vk.IC)
+	assert.True(t, setup.Vk.G1.Alpha.Equal(setupCT.Vk.G1.Alpha))
+	assert.True(t, setup.Vk.G2.Delta.Equal(setupCT.Vk.G2.Delta))
+
+	proof, err := GenerateProofs(*circuit, setupCT.Pk, w)
+	assert.Nil(t, err)
+	verified, err := VerifyProof(setupCT.Vk, proof, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+	verified, err = VerifyProof(setupCT.Vk, proof, []*big.Int{big.NewInt(int64(34))}, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+}
+
+func TestGroth16BLS12381(t *testing.T) {
+	bls, err := curve.NewBLS12381()
+	assert.Nil(t, err)
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+
+	publicSignals := []*big.Int{big.NewInt(int64(35))}
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, publicSignals)
+	assert.Nil(t, err)
+	circuit.GenerateSparseR1CS()
+
+	// the QAP is evaluated over the scalar field of the BLS12-381
+	setup, err := GenerateTrustedSetupWithCurve(bls, mrand.New(mrand.NewSource(1)), len(w), *circuit)
+	assert.Nil(t, err)
+	assert.Equal(t, "bls12381", setup.Vk.Curve.Name())
+	proof, err := GenerateProofsWithReader(mrand.New(mrand.NewSource(2)), *circuit, setup.Pk, w)
+	assert.Nil(t, err)
+	assert.Equal(t, "bls12381", proof.Curve.Name())
+
+	verified, err := VerifyProof(setup.Vk, proof, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+	verified, err = VerifyProof(setup.Vk, proof, []*big.Int{big.NewInt(int64(34))}, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	verified, err = VerifyProofPrepared(PrepareVk(setup.Vk), proof, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+
+	// a proof over the BLS12-381 is not accepted with a Vk over the BN128
+	bnVk := setup.Vk
+	bnVk.Curve = nil
+	verified, err = VerifyProof(bnVk, proof, publicSignals, false)
+	assert.NotNil(t, err)
+	assert.False(t, verified)
+
+	// the JSON encodings hold the curve, and the points are decoded in its groups
+	pkJSON, err := json.Marshal(setup.Pk)
+	assert.Nil(t, err)
+	vkJSON, err := json.Marshal(setup.Vk)
+	assert.Nil(t, err)
+	proofJSON, err := json.Marshal(proof)
+	assert.Nil(t, err)
+	assert.Contains(t, string(proofJSON), `"Curve":"bls12381"`)
+	var pk2 Pk
+	assert.Nil(t, json.Unmarshal(pkJSON, &pk2))
+	var vk2 Vk
+	assert.Nil(t, json.Unmarshal(vkJSON, &vk2))
+	var proof2 Proof
+	assert.Nil(t, json.Unmarshal(proofJSON, &proof2))
+	assert.Equal(t, "bls12381", vk2.Curve.Name())
+	proof3, err := GenerateProofsWithReader(mrand.New(mrand.NewSource(2)), *circuit, pk2, w)
+	assert.Nil(t, err)
+	assert.True(t, proof.PiC.Equal(proof3.PiC))
+	verified, err = VerifyProof(vk2, proof2, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+	// without the curve the points are decoded in the BN128, where they are not
+	var bnProof Proof
+	assert.NotNil(t, json.Unmarshal([]byte(strings.Replace(string(proofJSON), `,"Curve":"bls12381"`, "", 1)), &bnProof))
+	assert.NotNil(t, json.Unmarshal([]byte(strings.Replace(string(proofJSON), "bls12381", "secp256k1", 1)), &bnProof))
+
+	// the Fq of the BLS12-381 has no constant-time backend
+	Utils.ConstantTime = true
+	defer func() { Utils.ConstantTime = false }()
+	_, err = GenerateTrustedSetupWithCurve(bls, mrand.New(mrand.NewSource(1)), len(w), *circuit)
+	assert.NotNil(t, err)
+	_, err = GenerateProofsWithReader(mrand.New(mrand.NewSource(2)), *circuit, setup.Pk, w)
+	assert.NotNil(t, err)
 }
@@ -0,0 +1,221 @@
+package groth16
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/curve"
+)
+
+// The JSON encodings of the Pk, Vk and Proof hold the Name of their curve, and
+// the points are decoded in the groups of that curve. The encodings without
+// a Curve are of Utils.Curve.
+
+// curveByName returns the curve with the given name, or nil (Utils.Curve) for
+// the empty name
+func curveByName(name string) (curve.Curve, error) {
+	if name == "" {
+		return nil, nil
+	}
+	return curve.ByName(name)
+}
+
+// jsonReader decodes the points of a curve. After the first error the rest
+// of the reads return zero values, and the error is kept in err
+type jsonReader struct {
+	group1 bn128.G1
+	group2 bn128.G2
+	err    error
+}
+
+func newJSONReader(c curve.Curve) *jsonReader {
+	o := opsOf(c)
+	return &jsonReader{group1: o.g1, group2: o.g2}
+}
+
+func (r *jsonReader) g1(b json.RawMessage) bn128.G1Point {
+	if r.err != nil {
+		return bn128.G1Point{}
+	}
+	if len(b) == 0 {
+		return r.group1.Point([3]*big.Int{})
+	}
+	p, err := r.group1.PointFromJSON(b)
+	r.err = err
+	return p
+}
+
+func (r *jsonReader) g1Array(bs []json.RawMessage) []bn128.G1Point {
+	if bs == nil {
+		return nil
+	}
+	ps := make([]bn128.G1Point, len(bs))
+	for i := 0; i < len(bs); i++ {
+		ps[i] = r.g1(bs[i])
+	}
+	return ps
+}
+
+func (r *jsonReader) g2(b json.RawMessage) bn128.G2Point {
+	if r.err != nil {
+		return bn128.G2Point{}
+	}
+	if len(b) == 0 {
+		return r.group2.Point([3][2]*big.Int{})
+	}
+	p, err := r.group2.PointFromJSON(b)
+	r.err = err
+	return p
+}
+
+func (r *jsonReader) g2Array(bs []json.RawMessage) []bn128.G2Point {
+	if bs == nil {
+		return nil
+	}
+	ps := make([]bn128.G2Point, len(bs))
+	for i := 0; i < len(bs); i++ {
+		ps[i] = r.g2(bs[i])
+	}
+	return ps
+}
+
+// MarshalJSON encodes the Pk with the Name of its curve
+func (pk Pk) MarshalJSON() ([]byte, error) {
+	type plain Pk
+	return json.Marshal(struct {
+		plain
+		Curve string
+	}{plain(pk), opsOf(pk.Curve).c.Name()})
+}
+
+// UnmarshalJSON decodes a Pk encoded with MarshalJSON. It returns a
+// bn128.InvalidPointError if a point is not in its group
+func (pk *Pk) UnmarshalJSON(b []byte) error {
+	var s struct {
+		BACDelta []json.RawMessage
+		Z        []*big.Int
+		G1       struct {
+			Alpha    json.RawMessage
+			Beta     json.RawMessage
+			Delta    json.RawMessage
+			At       []json.RawMessage
+			BACGamma []json.RawMessage
+		}
+		G2 struct {
+			Beta     json.RawMessage
+			Gamma    json.RawMessage
+			Delta    json.RawMessage
+			BACGamma []json.RawMessage
+		}
+		PowersTauDelta []json.RawMessage
+		Curve          string
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	c, err := curveByName(s.Curve)
+	if err != nil {
+		return err
+	}
+	r := newJSONReader(c)
+	var o Pk
+	o.BACDelta = r.g1Array(s.BACDelta)
+	o.Z = s.Z
+	o.G1.Alpha = r.g1(s.G1.Alpha)
+	o.G1.Beta = r.g1(s.G1.Beta)
+	o.G1.Delta = r.g1(s.G1.Delta)
+	o.G1.At = r.g1Array(s.G1.At)
+	o.G1.BACGamma = r.g1Array(s.G1.BACGamma)
+	o.G2.Beta = r.g2(s.G2.Beta)
+	o.G2.Gamma = r.g2(s.G2.Gamma)
+	o.G2.Delta = r.g2(s.G2.Delta)
+	o.G2.BACGamma = r.g2Array(s.G2.BACGamma)
+	o.PowersTauDelta = r.g1Array(s.PowersTauDelta)
+	o.Curve = c
+	if r.err != nil {
+		return r.err
+	}
+	*pk = o
+	return nil
+}
+
+// MarshalJSON encodes the Vk with the Name of its curve
+func (vk Vk) MarshalJSON() ([]byte, error) {
+	type plain Vk
+	return json.Marshal(struct {
+		plain
+		Curve string
+	}{plain(vk), opsOf(vk.Curve).c.Name()})
+}
+
+// UnmarshalJSON decodes a Vk encoded with MarshalJSON. It returns a
+// bn128.InvalidPointError if a point is not in its group
+func (vk *Vk) UnmarshalJSON(b []byte) error {
+	var s struct {
+		IC []json.RawMessage
+		G1 struct {
+			Alpha json.RawMessage
+		}
+		G2 struct {
+			Beta  json.RawMessage
+			Gamma json.RawMessage
+			Delta json.RawMessage
+		}
+		Curve string
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	c, err := curveByName(s.Curve)
+	if err != nil {
+		return err
+	}
+	r := newJSONReader(c)
+	var o Vk
+	o.IC = r.g1Array(s.IC)
+	o.G1.Alpha = r.g1(s.G1.Alpha)
+	o.G2.Beta = r.g2(s.G2.Beta)
+	o.G2.Gamma = r.g2(s.G2.Gamma)
+	o.G2.Delta = r.g2(s.G2.Delta)
+	o.Curve = c
+	if r.err != nil {
+		return r.err
+	}
+	*vk = o
+	return nil
+}
+
+// MarshalJSON encodes the Proof with the Name of its curve
+func (proof Proof) MarshalJSON() ([]byte, error) {
+	type plain Proof
+	return json.Marshal(struct {
+		plain
+		Curve string
+	}{plain(proof), opsOf(proof.Curve).c.Name()})
+}
+
+// UnmarshalJSON decodes a Proof encoded with MarshalJSON. It returns a
+// bn128.InvalidPointError if a point is not in its group
+func (proof *Proof) UnmarshalJSON(b []byte) error {
+	var s struct {
+		PiA   json.RawMessage
+		PiB   json.RawMessage
+		PiC   json.RawMessage
+		Curve string
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	c, err := curveByName(s.Curve)
+	if err != nil {
+		return err
+	}
+	r := newJSONReader(c)
+	o := Proof{PiA: r.g1(s.PiA), PiB: r.g2(s.PiB), PiC: r.g1(s.PiC), Curve: c}
+	if r.err != nil {
+		return r.err
+	}
+	*proof = o
+	return nil
+}
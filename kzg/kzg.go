@@ -0,0 +1,324 @@
+// Package kzg implements the KZG polynomial commitments over the BN128
+// (https://www.iacr.org/archive/asiacrypt2010/6477178/6477178.pdf), with the
+// polynomials of r1csqap.PolynomialField over FqR.
+//
+// The structured reference string (SRS) has the powers of a secret τ in G1
+// and in G2. The commitment to a polynomial p is [p(τ)]_1, and the proof of
+// its evaluation y = p(z) is the commitment to the quotient
+// q(x) = (p(x) - y) / (x - z), that is verified with the pairing check
+// e(C - [y]_1 + z*π, g2) == e(π, [τ]_2).
+//
+// The proof of the evaluations of p at a set of points S is a single G1
+// point: the commitment to q(x) = (p(x) - I(x)) / Z(x), where I interpolates
+// the evaluations and Z(x) = Π (x - z) over S is the vanishing polynomial of
+// S. It is verified with e(C - [I(τ)]_1, g2) == e(π, [Z(τ)]_2), which needs
+// the powers of τ in G2 up to the number of points.
+package kzg
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/arnaucube/go-snark/r1csqap"
+)
+
+// KZG is the data structure of the KZG commitments over the BN128
+type KZG struct {
+	Bn bn128.Bn128
+	Fr fields.Fq // the field of the polynomial coefficients, over the order R of G1 and G2
+	Pf r1csqap.PolynomialField
+}
+
+// SRS is the structured reference string of the commitments to the
+// polynomials of up to len(G1) coefficients, and of the batch proofs of up to
+// len(G2)-1 points
+type SRS struct {
+	G1 []bn128.G1Point // [τ^i]_1, for i in 0..len(G1)-1
+	G2 []bn128.G2Point // [τ^i]_2, for i in 0..len(G2)-1
+}
+
+// Proof is the proof of the evaluation Y of a polynomial at Z
+type Proof struct {
+	Z  *big.Int
+	Y  *big.Int
+	Pi bn128.G1Point // the commitment to (p(x) - Y) / (x - Z)
+}
+
+// BatchProof is the proof of the evaluations Ys of a polynomial at the
+// points Zs
+type BatchProof struct {
+	Zs []*big.Int
+	Ys []*big.Int
+	Pi bn128.G1Point // the commitment to (p(x) - I(x)) / Z(x)
+}
+
+// NewKZG returns the KZG commitments over the BN128
+func NewKZG() (KZG, error) {
+	bn, err := bn128.NewBn128()
+	if err != nil {
+		return KZG{}, err
+	}
+	fr, err := bn128.NewFqR()
+	if err != nil {
+		return KZG{}, err
+	}
+	return KZG{bn, fr, r1csqap.NewPolynomialField(fr)}, nil
+}
+
+// NewSRS generates the SRS of the polynomials of up to maxDegree, and of the
+// batch proofs of up to maxPoints points, with a random τ read from
+// crypto/rand. τ is the toxic waste of the setup: it is not returned, and
+// whoever knows it can forge proofs
+func (k KZG) NewSRS(maxDegree, maxPoints int) (SRS, error) {
+	return k.NewSRSWithReader(rand.Reader, maxDegree, maxPoints)
+}
+
+// NewSRSWithReader generates the SRS as NewSRS, reading τ from the given
+// randomness source
+func (k KZG) NewSRSWithReader(rnd io.Reader, maxDegree, maxPoints int) (SRS, error) {
+	if maxDegree < 0 {
+		return SRS{}, errors.New("negative max degree")
+	}
+	if maxPoints < 1 {
+		return SRS{}, errors.New("max points under one")
+	}
+	tau, err := k.Fr.RandFrom(rnd)
+	if err != nil {
+		return SRS{}, err
+	}
+	return k.newSRS(tau, maxDegree, maxPoints), nil
+}
+
+// newSRS returns the SRS of the given τ
+func (k KZG) newSRS(tau *big.Int, maxDegree, maxPoints int) SRS {
+	g1Table := k.Bn.G1.NewFixedBaseTable(k.Bn.G1.G)
+	g2Table := k.Bn.G2.NewFixedBaseTable(k.Bn.G2.G)
+	powers1 := make([][3]*big.Int, maxDegree+1)
+	powers2 := make([][3][2]*big.Int, maxPoints+1)
+	tauI := k.Fr.One()
+	for i := 0; i <= maxDegree || i <= maxPoints; i++ {
+		if i <= maxDegree {
+			powers1[i] = g1Table.Mul(tauI)
+		}
+		if i <= maxPoints {
+			powers2[i] = g2Table.Mul(tauI)
+		}
+		tauI = k.Fr.Mul(tauI, tau)
+	}
+	var srs SRS
+	srs.G1 = k.Bn.G1.Points(k.Bn.G1.BatchAffine(powers1))
+	srs.G2 = k.Bn.G2.Points(k.Bn.G2.BatchAffine(powers2))
+	return srs
+}
+
+// reduce returns the coefficients of p reduced in Fr, without the zero
+// coefficients of the highest degrees
+func (k KZG) reduce(p []*big.Int) []*big.Int {
+	r := make([]*big.Int, len(p))
+	for i := 0; i < len(p); i++ {
+		r[i] = k.Fr.Affine(p[i])
+	}
+	for len(r) > 0 && k.Fr.IsZero(r[len(r)-1]) {
+		r = r[:len(r)-1]
+	}
+	return r
+}
+
+// commit returns the commitment to the reduced polynomial p
+func (k KZG) commit(srs SRS, p []*big.Int) (bn128.G1Point, error) {
+	if len(p) > len(srs.G1) {
+		return bn128.G1Point{}, errors.New("polynomial degree over the SRS max degree")
+	}
+	if len(p) == 0 {
+		return k.Bn.G1.Point(k.zeroG1()), nil
+	}
+	c := k.Bn.G1.MultiExp(bn128.G1Arrays(srs.G1[:len(p)]), p)
+	return k.Bn.G1.Point(c).Affine(), nil
+}
+
+func (k KZG) zeroG1() [3]*big.Int {
+	return [3]*big.Int{k.Bn.Fq1.Zero(), k.Bn.Fq1.Zero(), k.Bn.Fq1.Zero()}
+}
+
+// Commit returns the commitment to the polynomial p. It returns an error if
+// the degree of p is over the max degree of the SRS
+func (k KZG) Commit(srs SRS, p []*big.Int) (bn128.G1Point, error) {
+	return k.commit(srs, k.reduce(p))
+}
+
+// Open returns the proof of the evaluation of the polynomial p at z
+func (k KZG) Open(srs SRS, p []*big.Int, z *big.Int) (Proof, error) {
+	p = k.reduce(p)
+	if len(p) > len(srs.G1) {
+		return Proof{}, errors.New("polynomial degree over the SRS max degree")
+	}
+	z = k.Fr.Affine(z)
+	y := k.Pf.Eval(p, z)
+	// (p(x) - y) / (x - z), the remainder is zero as p(z) - y = 0
+	q, _ := k.Pf.Div(k.Pf.Sub(p, []*big.Int{y}), []*big.Int{k.Fr.Neg(z), k.Fr.One()})
+	pi, err := k.commit(srs, k.reduce(q))
+	if err != nil {
+		return Proof{}, err
+	}
+	return Proof{z, y, pi}, nil
+}
+
+// vanishing returns the polynomial Π (x - z) over the points zs
+func (k KZG) vanishing(zs []*big.Int) []*big.Int {
+	v := []*big.Int{k.Fr.One()}
+	for i := 0; i < len(zs); i++ {
+		v = k.Pf.Mul(v, []*big.Int{k.Fr.Neg(zs[i]), k.Fr.One()})
+	}
+	return v
+}
+
+// interpolate returns the polynomial of degree under len(zs) that takes the
+// values ys at the points zs, given their vanishing polynomial v. It returns
+// an error if a point is repeated
+func (k KZG) interpolate(zs, ys, v []*big.Int) ([]*big.Int, error) {
+	var p []*big.Int
+	for i := 0; i < len(zs); i++ {
+		// the Lagrange basis polynomial v(x) / (x - z_i), over its value at z_i
+		l, _ := k.Pf.Div(v, []*big.Int{k.Fr.Neg(zs[i]), k.Fr.One()})
+		d := k.Pf.Eval(l, zs[i])
+		if k.Fr.IsZero(d) {
+			return nil, errors.New("repeated evaluation point")
+		}
+		c := k.Fr.Div(ys[i], d)
+		for j := 0; j < len(l); j++ {
+			l[j] = k.Fr.Mul(l[j], c)
+		}
+		p = k.Pf.Add(p, l)
+	}
+	return k.reduce(p), nil
+}
+
+// OpenBatch returns the proof of the evaluations of the polynomial p at the
+// points zs, a single G1 point for all the points. It returns an error if zs
+// is empty, has repeated points or has more points than the SRS supports
+func (k KZG) OpenBatch(srs SRS, p []*big.Int, zs []*big.Int) (BatchProof, error) {
+	p = k.reduce(p)
+	if len(p) > len(srs.G1) {
+		return BatchProof{}, errors.New("polynomial degree over the SRS max degree")
+	}
+	if len(zs) == 0 || len(zs) >= len(srs.G2) {
+		return BatchProof{}, errors.New("number of points out of the SRS range")
+	}
+	proof := BatchProof{Zs: make([]*big.Int, len(zs)), Ys: make([]*big.Int, len(zs))}
+	for i := 0; i < len(zs); i++ {
+		proof.Zs[i] = k.Fr.Affine(zs[i])
+		proof.Ys[i] = k.Pf.Eval(p, proof.Zs[i])
+	}
+	v := k.vanishing(proof.Zs)
+	ip, err := k.interpolate(proof.Zs, proof.Ys, v)
+	if err != nil {
+		return BatchProof{}, err
+	}
+	// (p(x) - I(x)) / Z(x), the remainder is zero as p - I is zero at the points
+	q, _ := k.Pf.Div(k.Pf.Sub(p, ip), v)
+	proof.Pi, err = k.commit(srs, k.reduce(q))
+	if err != nil {
+		return BatchProof{}, err
+	}
+	return proof, nil
+}
+
+// VerifyBatchProof returns true if the proof proves the evaluations of the
+// polynomial of the commitment c, checking
+// e(C - [I(τ)]_1, g2) == e(π, [Z(τ)]_2)
+func (k KZG) VerifyBatchProof(srs SRS, c bn128.G1Point, proof BatchProof) bool {
+	n := len(proof.Zs)
+	if n == 0 || n != len(proof.Ys) || n >= len(srs.G2) || n > len(srs.G1) {
+		return false
+	}
+	zs := make([]*big.Int, n)
+	ys := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		if proof.Zs[i] == nil || proof.Ys[i] == nil {
+			return false
+		}
+		zs[i] = k.Fr.Affine(proof.Zs[i])
+		ys[i] = k.Fr.Affine(proof.Ys[i])
+	}
+	g1 := k.Bn.G1
+	if g1.Validate(c.Array()) != nil || g1.Validate(proof.Pi.Array()) != nil {
+		return false
+	}
+	v := k.vanishing(zs)
+	ip, err := k.interpolate(zs, ys, v)
+	if err != nil {
+		return false
+	}
+	ipTau := g1.MultiExp(bn128.G1Arrays(srs.G1[:len(ip)]), ip)
+	vTau := k.Bn.G2.MultiExp(bn128.G2Arrays(srs.G2[:len(v)]), v)
+	return k.Bn.PairingCheck(
+		[][3]*big.Int{g1.Sub(c.Array(), ipTau), g1.Neg(proof.Pi.Array())},
+		[][3][2]*big.Int{srs.G2[0].Array(), vTau})
+}
+
+// Verify returns true if the proof proves the evaluation of the polynomial of
+// the commitment c
+func (k KZG) Verify(srs SRS, c bn128.G1Point, proof Proof) bool {
+	return k.batchVerify(srs, []bn128.G1Point{c}, []Proof{proof}, []*big.Int{k.Fr.One()})
+}
+
+// BatchVerify returns true if each proofs[i] proves an evaluation of the
+// polynomial of the commitment cs[i], with a single pairing check. The proofs
+// can be of different points and of different polynomials. The checks of the
+// proofs are combined with random coefficients read from crypto/rand, so a
+// wrong proof can not be compensated by the others
+func (k KZG) BatchVerify(srs SRS, cs []bn128.G1Point, proofs []Proof) (bool, error) {
+	return k.BatchVerifyWithReader(rand.Reader, srs, cs, proofs)
+}
+
+// BatchVerifyWithReader verifies the proofs as BatchVerify, reading the
+// random coefficients from the given randomness source
+func (k KZG) BatchVerifyWithReader(rnd io.Reader, srs SRS, cs []bn128.G1Point, proofs []Proof) (bool, error) {
+	if len(cs) != len(proofs) {
+		return false, errors.New("different number of commitments and proofs")
+	}
+	rs := make([]*big.Int, len(proofs))
+	for i := 0; i < len(proofs); i++ {
+		var err error
+		rs[i], err = k.Fr.RandFrom(rnd)
+		if err != nil {
+			return false, err
+		}
+	}
+	return k.batchVerify(srs, cs, proofs, rs), nil
+}
+
+// batchVerify checks e(Σ r_i (C_i - [y_i]_1 + z_i π_i), g2) == e(Σ r_i π_i, [τ]_2)
+func (k KZG) batchVerify(srs SRS, cs []bn128.G1Point, proofs []Proof, rs []*big.Int) bool {
+	if len(cs) == 0 || len(srs.G1) == 0 || len(srs.G2) < 2 {
+		return false
+	}
+	g1 := k.Bn.G1
+	var points [][3]*big.Int
+	var scalars []*big.Int
+	var pis [][3]*big.Int
+	ySum := k.Fr.Zero()
+	for i := 0; i < len(proofs); i++ {
+		if proofs[i].Z == nil || proofs[i].Y == nil {
+			return false
+		}
+		if g1.Validate(cs[i].Array()) != nil || g1.Validate(proofs[i].Pi.Array()) != nil {
+			return false
+		}
+		points = append(points, cs[i].Array(), proofs[i].Pi.Array())
+		scalars = append(scalars, rs[i], k.Fr.Mul(rs[i], k.Fr.Affine(proofs[i].Z)))
+		ySum = k.Fr.Add(ySum, k.Fr.Mul(rs[i], k.Fr.Affine(proofs[i].Y)))
+		pis = append(pis, proofs[i].Pi.Array())
+	}
+	points = append(points, srs.G1[0].Array())
+	scalars = append(scalars, k.Fr.Neg(ySum))
+	lhs := g1.MultiExp(points, scalars)
+	rhs := g1.MultiExp(pis, rs)
+	return k.Bn.PairingCheck(
+		[][3]*big.Int{lhs, g1.Neg(rhs)},
+		[][3][2]*big.Int{srs.G2[0].Array(), srs.G2[1].Array()})
+}
@@ -0,0 +1,181 @@
+package kzg
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitOpenVerify(t *testing.T) {
+	k, err := NewKZG()
+	assert.Nil(t, err)
+
+	// with a known τ, the commitment is p(τ) * g1
+	tau := big.NewInt(987654321)
+	srs := k.newSRS(tau, 8, 1)
+	assert.Equal(t, 9, len(srs.G1))
+	assert.Equal(t, 2, len(srs.G2))
+	p := []*big.Int{big.NewInt(3), big.NewInt(0), big.NewInt(5), big.NewInt(7), big.NewInt(0)}
+	c, err := k.Commit(srs, p)
+	assert.Nil(t, err)
+	assert.True(t, k.Bn.G1.Equal(k.Bn.G1.MulScalar(k.Bn.G1.G, k.Pf.Eval(p, tau)), c.Array()))
+
+	z := big.NewInt(11)
+	proof, err := k.Open(srs, p, z)
+	assert.Nil(t, err)
+	// 3 + 5*11^2 + 7*11^3
+	assert.Equal(t, big.NewInt(9925), proof.Y)
+	assert.True(t, k.Verify(srs, c, proof))
+
+	wrong := proof
+	wrong.Y = big.NewInt(9926)
+	assert.False(t, k.Verify(srs, c, wrong))
+	wrong = proof
+	wrong.Z = big.NewInt(12)
+	assert.False(t, k.Verify(srs, c, wrong))
+	c2, err := k.Commit(srs, []*big.Int{big.NewInt(4)})
+	assert.Nil(t, err)
+	assert.False(t, k.Verify(srs, c2, proof))
+
+	// the constant polynomials have the point at infinity as proof
+	proof, err = k.Open(srs, []*big.Int{big.NewInt(4)}, z)
+	assert.Nil(t, err)
+	assert.True(t, proof.Pi.IsZero())
+	assert.True(t, k.Verify(srs, c2, proof))
+
+	// degree over the SRS max degree
+	big9 := make([]*big.Int, 10)
+	for i := 0; i < len(big9); i++ {
+		big9[i] = big.NewInt(int64(i + 1))
+	}
+	_, err = k.Commit(srs, big9)
+	assert.NotNil(t, err)
+	_, err = k.Open(srs, big9, z)
+	assert.NotNil(t, err)
+}
+
+func TestBatch(t *testing.T) {
+	k, err := NewKZG()
+	assert.Nil(t, err)
+	rnd := mrand.New(mrand.NewSource(1))
+	srs, err := k.NewSRSWithReader(rnd, 16, 4)
+	assert.Nil(t, err)
+
+	p1 := make([]*big.Int, 17)
+	p2 := make([]*big.Int, 5)
+	for i := 0; i < len(p1); i++ {
+		p1[i], err = k.Fr.RandFrom(rnd)
+		assert.Nil(t, err)
+	}
+	for i := 0; i < len(p2); i++ {
+		p2[i], err = k.Fr.RandFrom(rnd)
+		assert.Nil(t, err)
+	}
+	c1, err := k.Commit(srs, p1)
+	assert.Nil(t, err)
+	c2, err := k.Commit(srs, p2)
+	assert.Nil(t, err)
+
+	zs := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+	proofs1 := make([]Proof, len(zs))
+	proofs2 := make([]Proof, 2)
+	for i := 0; i < len(zs); i++ {
+		proofs1[i], err = k.Open(srs, p1, zs[i])
+		assert.Nil(t, err)
+		if i < len(proofs2) {
+			proofs2[i], err = k.Open(srs, p2, zs[i])
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, k.Pf.Eval(p1, zs[i]), proofs1[i].Y)
+		assert.True(t, k.Verify(srs, c1, proofs1[i]))
+	}
+
+	cs := []bn128.G1Point{c1, c1, c1, c2, c2}
+	proofs := append(append([]Proof{}, proofs1...), proofs2...)
+	ok, err := k.BatchVerify(srs, cs, proofs)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = k.BatchVerify(srs, cs[:4], proofs)
+	assert.NotNil(t, err)
+	assert.False(t, ok)
+	ok, err = k.BatchVerify(srs, nil, nil)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// a wrong evaluation makes the batch fail
+	proofs[1].Y = k.Fr.Add(proofs[1].Y, big.NewInt(1))
+	ok, err = k.BatchVerify(srs, cs, proofs)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// two wrong proofs that would compensate each other without the random
+	// coefficients
+	proofs[1].Y = k.Fr.Sub(proofs[1].Y, big.NewInt(1))
+	proofs[3].Pi, proofs[4].Pi = proofs[4].Pi, proofs[3].Pi
+	ok, err = k.BatchVerify(srs, cs, proofs)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestOpenBatch(t *testing.T) {
+	k, err := NewKZG()
+	assert.Nil(t, err)
+	rnd := mrand.New(mrand.NewSource(2))
+	srs, err := k.NewSRSWithReader(rnd, 16, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(srs.G2))
+
+	p := make([]*big.Int, 17)
+	for i := 0; i < len(p); i++ {
+		p[i], err = k.Fr.RandFrom(rnd)
+		assert.Nil(t, err)
+	}
+	c, err := k.Commit(srs, p)
+	assert.Nil(t, err)
+
+	zs := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(-4)}
+	for n := 1; n <= len(zs); n++ {
+		proof, err := k.OpenBatch(srs, p, zs[:n])
+		assert.Nil(t, err)
+		for i := 0; i < n; i++ {
+			assert.Equal(t, k.Pf.Eval(p, zs[i]), proof.Ys[i])
+		}
+		assert.True(t, k.VerifyBatchProof(srs, c, proof), n)
+
+		// a wrong evaluation, point or commitment is refused
+		wrong := proof
+		wrong.Ys = append([]*big.Int{}, proof.Ys...)
+		wrong.Ys[n-1] = k.Fr.Add(wrong.Ys[n-1], big.NewInt(1))
+		assert.False(t, k.VerifyBatchProof(srs, c, wrong))
+		wrong = proof
+		wrong.Zs = append([]*big.Int{}, proof.Zs...)
+		wrong.Zs[0] = big.NewInt(5)
+		assert.False(t, k.VerifyBatchProof(srs, c, wrong))
+		c2, err := k.Commit(srs, p[:16])
+		assert.Nil(t, err)
+		assert.False(t, k.VerifyBatchProof(srs, c2, proof))
+	}
+
+	// the polynomials of degree under the number of points have the point
+	// at infinity as proof
+	low := p[:3]
+	cLow, err := k.Commit(srs, low)
+	assert.Nil(t, err)
+	proof, err := k.OpenBatch(srs, low, zs[:3])
+	assert.Nil(t, err)
+	assert.True(t, proof.Pi.IsZero())
+	assert.True(t, k.VerifyBatchProof(srs, cLow, proof))
+
+	// the number of points is bounded by the SRS, and the points can not repeat
+	_, err = k.OpenBatch(srs, p, append(zs, big.NewInt(6)))
+	assert.NotNil(t, err)
+	_, err = k.OpenBatch(srs, p, nil)
+	assert.NotNil(t, err)
+	_, err = k.OpenBatch(srs, p, []*big.Int{big.NewInt(1), k.Fr.Add(k.Fr.Q, big.NewInt(1))})
+	assert.NotNil(t, err)
+	assert.False(t, k.VerifyBatchProof(srs, c, BatchProof{}))
+}
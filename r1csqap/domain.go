@@ -0,0 +1,191 @@
+package r1csqap
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// EvaluationDomain is the subgroup H of the Size-th roots of unity of a field,
+// with Size a power of two, where the polynomials are interpolated and
+// evaluated with the FFT in O(n log n). Its vanishing polynomial, zero at all
+// the points of H, is x^Size - 1. The coset FFT evaluates the polynomials over
+// the coset g*H, where the vanishing polynomial is the constant g^Size - 1, so
+// it can be used to divide by it
+type EvaluationDomain struct {
+	F           fields.Fq
+	Size        int
+	Omega       *big.Int // primitive Size-th root of unity, the generator of H
+	OmegaInv    *big.Int
+	SizeInv     *big.Int
+	CosetGen    *big.Int // g, with g^Size != 1, so g is not in H and g*H does not intersect H
+	CosetGenInv *big.Int
+}
+
+// NewEvaluationDomain returns the EvaluationDomain of the smallest power of
+// two not lower than n. It returns an error if the field has no roots of
+// unity of that order (its modulus minus one is not divisible by it)
+func NewEvaluationDomain(f fields.Fq, n int) (EvaluationDomain, error) {
+	size := 1
+	logSize := 0
+	for size < n {
+		size <<= 1
+		logSize++
+	}
+	// Q - 1 = 2^s * t, with t odd
+	qMinus1 := new(big.Int).Sub(f.Q, big.NewInt(1))
+	s := int(qMinus1.TrailingZeroBits())
+	if logSize > s {
+		return EvaluationDomain{}, errors.New("the field has no evaluation domain of that size")
+	}
+	// g^t, for a non residue g, is a primitive 2^s-th root of unity, and its
+	// 2^(s-logSize) power is a primitive root of order size. The same g is
+	// the generator of the coset, so it must not be in H: g^size != 1. Being
+	// a non residue does not ensure it, as the generator of the 2^s-th roots
+	// of unity is a non residue too, so it is checked
+	sizeBig := big.NewInt(int64(size))
+	g := big.NewInt(2)
+	for f.Legendre(g) != -1 || f.Equal(f.Exp(g, sizeBig), f.One()) {
+		g = new(big.Int).Add(g, big.NewInt(1))
+		if g.Cmp(f.Q) >= 0 {
+			// H holds all the non zero elements of the field
+			return EvaluationDomain{}, errors.New("the field has no coset of the evaluation domain of that size")
+		}
+	}
+	e := new(big.Int).Rsh(qMinus1, uint(logSize))
+	omega := f.Exp(g, e)
+	return EvaluationDomain{
+		F:           f,
+		Size:        size,
+		Omega:       omega,
+		OmegaInv:    f.Inverse(omega),
+		SizeInv:     f.Inverse(big.NewInt(int64(size))),
+		CosetGen:    g,
+		CosetGenInv: f.Inverse(g),
+	}, nil
+}
+
+// NewEvaluationDomain returns the EvaluationDomain of the PolynomialField
+// field of at least n points, as the NewEvaluationDomain function
+func (pf PolynomialField) NewEvaluationDomain(n int) (EvaluationDomain, error) {
+	return NewEvaluationDomain(pf.F, n)
+}
+
+// mustDomain returns the EvaluationDomain of at least n points, and panics if
+// the field has none (for the BN128 FqR, more than 2^28 points)
+func (pf PolynomialField) mustDomain(n int) EvaluationDomain {
+	d, err := pf.NewEvaluationDomain(n)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Element returns the point i of the domain, ω^i
+func (d EvaluationDomain) Element(i int) *big.Int {
+	return d.F.Exp(d.Omega, big.NewInt(int64(i)))
+}
+
+// VanishingPolynomial returns the vanishing polynomial of the domain,
+// x^Size - 1
+func (d EvaluationDomain) VanishingPolynomial() []*big.Int {
+	z := ArrayOfBigZeros(d.Size + 1)
+	z[0] = d.F.Neg(big.NewInt(1))
+	z[d.Size] = big.NewInt(1)
+	return z
+}
+
+// EvalVanishing returns the evaluation of the vanishing polynomial at x,
+// x^Size - 1
+func (d EvaluationDomain) EvalVanishing(x *big.Int) *big.Int {
+	return d.F.Sub(d.F.Exp(x, big.NewInt(int64(d.Size))), big.NewInt(1))
+}
+
+// fft returns the evaluations of the polynomial a (of up to Size
+// coefficients) at the powers of the given root of unity of order Size, with
+// the iterative radix-2 Cooley-Tukey FFT
+func (d EvaluationDomain) fft(a []*big.Int, root *big.Int) []*big.Int {
+	if len(a) > d.Size {
+		panic("polynomial with more coefficients than the domain size")
+	}
+	n := d.Size
+	r := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		if i < len(a) {
+			r[i] = d.F.Affine(a[i])
+		} else {
+			r[i] = d.F.Zero()
+		}
+	}
+	// bit reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			r[i], r[j] = r[j], r[i]
+		}
+	}
+	for length := 2; length <= n; length <<= 1 {
+		wLen := d.F.Exp(root, big.NewInt(int64(n/length)))
+		half := length >> 1
+		ws := make([]*big.Int, half)
+		ws[0] = d.F.One()
+		for k := 1; k < half; k++ {
+			ws[k] = d.F.Mul(ws[k-1], wLen)
+		}
+		for i := 0; i < n; i += length {
+			for k := 0; k < half; k++ {
+				u := r[i+k]
+				v := d.F.Mul(r[i+k+half], ws[k])
+				r[i+k] = d.F.Add(u, v)
+				r[i+k+half] = d.F.Sub(u, v)
+			}
+		}
+	}
+	return r
+}
+
+// FFT returns the evaluations of the polynomial a at the points of the
+// domain, ω^0, ..., ω^(Size-1). It panics if a has more than Size coefficients
+func (d EvaluationDomain) FFT(a []*big.Int) []*big.Int {
+	return d.fft(a, d.Omega)
+}
+
+// IFFT returns the polynomial of Size coefficients that takes the given
+// values at the points of the domain (the missing values are zero), the
+// inverse of FFT
+func (d EvaluationDomain) IFFT(evals []*big.Int) []*big.Int {
+	r := d.fft(evals, d.OmegaInv)
+	for i := 0; i < len(r); i++ {
+		r[i] = d.F.Mul(r[i], d.SizeInv)
+	}
+	return r
+}
+
+// distribute returns the coefficients a_i multiplied by c^i, the polynomial
+// a(c*x)
+func (d EvaluationDomain) distribute(a []*big.Int, c *big.Int) []*big.Int {
+	r := make([]*big.Int, len(a))
+	ci := d.F.One()
+	for i := 0; i < len(a); i++ {
+		r[i] = d.F.Mul(a[i], ci)
+		ci = d.F.Mul(ci, c)
+	}
+	return r
+}
+
+// CosetFFT returns the evaluations of the polynomial a at the points of the
+// coset g*H, g*ω^0, ..., g*ω^(Size-1)
+func (d EvaluationDomain) CosetFFT(a []*big.Int) []*big.Int {
+	return d.FFT(d.distribute(a, d.CosetGen))
+}
+
+// CosetIFFT returns the polynomial of Size coefficients that takes the given
+// values at the points of the coset g*H, the inverse of CosetFFT
+func (d EvaluationDomain) CosetIFFT(evals []*big.Int) []*big.Int {
+	return d.distribute(d.IFFT(evals), d.CosetGenInv)
+}
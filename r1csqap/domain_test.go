@@ -0,0 +1,125 @@
+package r1csqap
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPolynomialField(t *testing.T) PolynomialField {
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(t, ok)
+	return NewPolynomialField(fields.NewFq(r))
+}
+
+func randPolynomial(rnd *mrand.Rand, f fields.Fq, n int) []*big.Int {
+	p := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		p[i], _ = f.RandFrom(rnd)
+	}
+	return p
+}
+
+func TestEvaluationDomain(t *testing.T) {
+	pf := newTestPolynomialField(t)
+	f := pf.F
+
+	d, err := pf.NewEvaluationDomain(5)
+	assert.Nil(t, err)
+	assert.Equal(t, 8, d.Size)
+	assert.True(t, f.Equal(f.One(), d.Element(8)))
+	assert.False(t, f.Equal(f.One(), d.Element(4)))
+	assert.True(t, f.Equal(f.One(), f.Mul(d.Omega, d.OmegaInv)))
+	for i := 0; i < d.Size; i++ {
+		assert.True(t, f.IsZero(d.EvalVanishing(d.Element(i))))
+		assert.True(t, f.IsZero(pf.Eval(d.VanishingPolynomial(), d.Element(i))))
+	}
+	assert.False(t, f.IsZero(d.EvalVanishing(d.CosetGen)))
+
+	d, err = pf.NewEvaluationDomain(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, d.Size)
+
+	// 7 - 1 = 2 * 3, so Fq7 only has the domains of 1 and 2 points
+	small := NewPolynomialField(fields.Fq{Q: big.NewInt(7)})
+	d, err = small.NewEvaluationDomain(2)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(6), d.Omega)
+	_, err = small.NewEvaluationDomain(3)
+	assert.NotNil(t, err)
+
+	// in Fq41 the first non residue, 3, is an 8-th root of unity, so it can
+	// not be the generator of the coset of the domain of 8 points
+	fq41 := fields.Fq{Q: big.NewInt(41)}
+	d, err = NewPolynomialField(fq41).NewEvaluationDomain(8)
+	assert.Nil(t, err)
+	assert.False(t, fq41.IsZero(d.EvalVanishing(d.CosetGen)))
+	assert.Equal(t, -1, fq41.Legendre(d.CosetGen))
+	// in Fq17 the domain of 16 points holds all the non zero elements
+	_, err = NewPolynomialField(fields.Fq{Q: big.NewInt(17)}).NewEvaluationDomain(16)
+	assert.NotNil(t, err)
+
+	// the biggest domain of the BN128 FqR, where H holds non residues
+	d, err = pf.NewEvaluationDomain(1 << 28)
+	assert.Nil(t, err)
+	assert.False(t, f.IsZero(d.EvalVanishing(d.CosetGen)))
+}
+
+func TestFFT(t *testing.T) {
+	pf := newTestPolynomialField(t)
+	f := pf.F
+	rnd := mrand.New(mrand.NewSource(1))
+
+	d, err := pf.NewEvaluationDomain(16)
+	assert.Nil(t, err)
+	a := randPolynomial(rnd, f, 13)
+
+	evals := d.FFT(a)
+	assert.Equal(t, d.Size, len(evals))
+	for i := 0; i < d.Size; i++ {
+		assert.True(t, f.Equal(pf.Eval(a, d.Element(i)), evals[i]))
+	}
+	coefs := d.IFFT(evals)
+	assert.True(t, BigArraysEqual(append(a, ArrayOfBigZeros(3)...), coefs))
+
+	evals = d.CosetFFT(a)
+	for i := 0; i < d.Size; i++ {
+		x := f.Mul(d.CosetGen, d.Element(i))
+		assert.True(t, f.Equal(pf.Eval(a, x), evals[i]))
+	}
+	coefs = d.CosetIFFT(evals)
+	assert.True(t, BigArraysEqual(append(a, ArrayOfBigZeros(3)...), coefs))
+
+	assert.Panics(t, func() { d.FFT(randPolynomial(rnd, f, 17)) })
+
+	b := randPolynomial(rnd, f, 6)
+	assert.True(t, BigArraysEqual(pf.Mul(a, b), pf.MulFFT(a, b)))
+}
+
+func TestR1CSToQAPDomain(t *testing.T) {
+	pf := newTestPolynomialField(t)
+	b0 := big.NewInt(int64(0))
+	b1 := big.NewInt(int64(1))
+	b5 := big.NewInt(int64(5))
+	// 3 constraints, padded to a domain of 4
+	a := [][]*big.Int{
+		[]*big.Int{b0, b1, b0, b0},
+		[]*big.Int{b0, b0, b0, b1},
+		[]*big.Int{b5, b1, b0, b0},
+	}
+	alphas, _, _, z := pf.R1CSToQAP(a, a, a)
+	d, err := pf.NewEvaluationDomain(3)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(alphas))
+	assert.True(t, BigArraysEqual(d.VanishingPolynomial(), z))
+	for i := 0; i < len(alphas); i++ {
+		assert.Equal(t, d.Size, len(alphas[i]))
+		for j := 0; j < len(a); j++ {
+			assert.True(t, pf.F.Equal(a[j][i], pf.Eval(alphas[i], d.Element(j))))
+		}
+		assert.True(t, pf.F.IsZero(pf.Eval(alphas[i], d.Element(3))))
+	}
+}
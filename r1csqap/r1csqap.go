@@ -157,37 +157,29 @@ func (pf PolynomialField) LagrangeInterpolation(v []*big.Int) []*big.Int {
 	return r
 }
 
-// R1CSToQAP converts the R1CS values to the QAP values
+// R1CSToQAP converts the R1CS values to the QAP values. The constraints are
+// assigned to the points of the EvaluationDomain of the smallest power of two
+// not lower than the number of constraints, with the missing constraints
+// padded with zeros (0 * 0 = 0), and each signal polynomial is interpolated
+// from its column with the inverse FFT, so all of them have the domain size
+// coefficients. The returned z(x) is the vanishing polynomial of the domain,
+// x^n - 1, zero at all the constraints. It panics if the field has no domain
+// of that size
 func (pf PolynomialField) R1CSToQAP(a, b, c [][]*big.Int) ([][]*big.Int, [][]*big.Int, [][]*big.Int, []*big.Int) {
-	aT := Transpose(a)
-	bT := Transpose(b)
-	cT := Transpose(c)
-	var alphas [][]*big.Int
-	for i := 0; i < len(aT); i++ {
-		alphas = append(alphas, pf.LagrangeInterpolation(aT[i]))
-	}
-	var betas [][]*big.Int
-	for i := 0; i < len(bT); i++ {
-		betas = append(betas, pf.LagrangeInterpolation(bT[i]))
-	}
-	var gammas [][]*big.Int
-	for i := 0; i < len(cT); i++ {
-		gammas = append(gammas, pf.LagrangeInterpolation(cT[i]))
-	}
-	z := []*big.Int{big.NewInt(int64(1))}
-	for i := 1; i < len(alphas)-1; i++ {
-		z = pf.Mul(
-			z,
-			[]*big.Int{
-				pf.F.Neg(
-					big.NewInt(int64(i))),
-				big.NewInt(int64(1)),
-			})
-	}
-	return alphas, betas, gammas, z
-}
-
-// CombinePolynomials combine the given polynomials arrays into one, also returns the P(x)
+	return pf.SparseR1CSToQAP(NewSparseR1CS(a, b, c))
+}
+
+// CheckSatisfied returns the constraints of the R1CS that are not satisfied by
+// the witness w, with the values A·w, B·w and C·w of each one, as
+// CheckSparseSatisfied. A witness satisfies the R1CS if the returned list is
+// empty
+func (pf PolynomialField) CheckSatisfied(a, b, c [][]*big.Int, w []*big.Int) ([]UnsatisfiedConstraint, error) {
+	return pf.CheckSparseSatisfied(NewSparseR1CS(a, b, c), w)
+}
+
+// CombinePolynomials combine the given polynomials arrays into one, also
+// returns the P(x) = A(x) * B(x) - C(x). The product is computed with the FFT,
+// over the EvaluationDomain of the size of its coefficients
 func (pf PolynomialField) CombinePolynomials(r []*big.Int, ap, bp, cp [][]*big.Int) ([]*big.Int, []*big.Int, []*big.Int, []*big.Int) {
 	var ax []*big.Int
 	for i := 0; i < len(r); i++ {
@@ -205,12 +197,68 @@ func (pf PolynomialField) CombinePolynomials(r []*big.Int, ap, bp, cp [][]*big.I
 		cx = pf.Add(cx, m)
 	}
 
-	px := pf.Sub(pf.Mul(ax, bx), cx)
+	px := pf.Sub(pf.MulFFT(ax, bx), cx)
 	return ax, bx, cx, px
 }
 
+// MulFFT multiplies two polynomials as Mul, with the FFT over the
+// EvaluationDomain of the size of the result, in O(n log n) instead of O(n^2).
+// It panics if the field has no domain of that size
+func (pf PolynomialField) MulFFT(a, b []*big.Int) []*big.Int {
+	if len(a) == 0 || len(b) == 0 {
+		return pf.Mul(a, b)
+	}
+	n := len(a) + len(b) - 1
+	d := pf.mustDomain(n)
+	ea := d.FFT(a)
+	eb := d.FFT(b)
+	for i := 0; i < d.Size; i++ {
+		ea[i] = pf.F.Mul(ea[i], eb[i])
+	}
+	return d.IFFT(ea)[:n]
+}
+
 // DivisorPolynomial returns the divisor polynomial given two polynomials
 func (pf PolynomialField) DivisorPolynomial(px, z []*big.Int) []*big.Int {
 	quo, _ := pf.Div(px, z)
 	return quo
 }
+
+// HPolynomial returns the h(x) = (A(x) * B(x) - C(x)) / z(x) of the QAP of
+// SparseR1CSToQAP for the witness w, without computing the polynomials of each
+// signal nor P(x). A(x), B(x) and C(x) are interpolated from their values at
+// the constraints (A[i]·w, B[i]·w and C[i]·w) with the inverse FFT, and evaluated at
+// the coset g*H with the coset FFT, where z(x) is the non zero constant
+// g^n - 1. The quotient of the evaluations is interpolated back with the
+// inverse coset FFT. If the witness does not satisfy the R1CS, the result is
+// not a quotient of P(x). It panics if the field has no domain of the number
+// of constraints
+func (pf PolynomialField) HPolynomial(r SparseR1CS, w []*big.Int) []*big.Int {
+	d := pf.mustDomain(r.NConstraints())
+	ax := d.IFFT(pf.constraintsValues(r.A, w))
+	bx := d.IFFT(pf.constraintsValues(r.B, w))
+	cx := d.IFFT(pf.constraintsValues(r.C, w))
+	ea := d.CosetFFT(ax)
+	eb := d.CosetFFT(bx)
+	ec := d.CosetFFT(cx)
+	zInv := pf.F.Inverse(d.EvalVanishing(d.CosetGen))
+	for i := 0; i < d.Size; i++ {
+		ea[i] = pf.F.Mul(pf.F.Sub(pf.F.Mul(ea[i], eb[i]), ec[i]), zInv)
+	}
+	hx := d.CosetIFFT(ea)
+	// h(x) has degree n-2, as P(x) has degree 2n-2 and z(x) degree n
+	if len(hx) > 1 {
+		hx = hx[:len(hx)-1]
+	}
+	return hx
+}
+
+// constraintsValues returns the values m[j]·w of the linear combinations of
+// the constraints
+func (pf PolynomialField) constraintsValues(m []LinearCombination, w []*big.Int) []*big.Int {
+	r := make([]*big.Int, len(m))
+	for j := 0; j < len(m); j++ {
+		r[j] = m[j].Eval(pf.F, w)
+	}
+	return r
+}
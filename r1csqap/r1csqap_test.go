@@ -164,6 +164,9 @@ func TestR1CSToQAP(t *testing.T) {
 	hx := pf.DivisorPolynomial(px, zx)
 	// fmt.Println(hx)
 
+	// the same h(x) from the R1CS and the witness, with the coset FFT
+	assert.True(t, BigArraysEqual(hx, pf.HPolynomial(NewSparseR1CS(a, b, c), w)))
+
 	// hx==px/zx so px==hx*zx
 	assert.Equal(t, px, pf.Mul(hx, zx))
 
@@ -174,3 +177,25 @@ func TestR1CSToQAP(t *testing.T) {
 	assert.Equal(t, abc, hz)
 
 }
+
+func TestPolynomialFieldMontgomeryMatchesBigInt(t *testing.T) {
+	r, ok := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	assert.True(t, ok)
+	pfMont := NewPolynomialField(fields.NewFq(r))
+	pfBig := NewPolynomialField(fields.Fq{Q: r})
+
+	b0 := big.NewInt(int64(0))
+	b1 := big.NewInt(int64(1))
+	b5 := big.NewInt(int64(5))
+	a := [][]*big.Int{
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b0, b0, b0, b1, b0, b0},
+		[]*big.Int{b0, b1, b0, b0, b1, b0},
+		[]*big.Int{b5, b0, b0, b0, b0, b1},
+	}
+	alphasMont, _, _, zMont := pfMont.R1CSToQAP(a, a, a)
+	alphasBig, _, _, zBig := pfBig.R1CSToQAP(a, a, a)
+	assert.Equal(t, alphasBig, alphasMont)
+	assert.Equal(t, zBig, zMont)
+	assert.Equal(t, pfBig.Eval(alphasBig[1], b5), pfMont.Eval(alphasMont[1], b5))
+}
@@ -0,0 +1,214 @@
+package r1csqap
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/arnaucube/go-snark/fields"
+)
+
+// Term is the term Coeff * w[Signal] of a linear combination of the signals
+type Term struct {
+	Signal int
+	Coeff  *big.Int
+}
+
+// LinearCombination is a linear combination of the signals, with only its
+// non zero terms. It is a row of a matrix of the R1CS
+type LinearCombination []Term
+
+// SparseR1CS is the R1CS with only the non zero coefficients of each
+// constraint: the constraint i is A[i]·w * B[i]·w = C[i]·w. The circuits have
+// few signals in each constraint, so it takes O(constraints) memory instead of
+// the O(constraints * signals) of the dense matrices
+type SparseR1CS struct {
+	NSignals int
+	A        []LinearCombination
+	B        []LinearCombination
+	C        []LinearCombination
+}
+
+// sparseRow returns the LinearCombination of the non zero values of the row
+func sparseRow(row []*big.Int) LinearCombination {
+	var lc LinearCombination
+	for i := 0; i < len(row); i++ {
+		if row[i] != nil && row[i].Sign() != 0 {
+			lc = append(lc, Term{i, row[i]})
+		}
+	}
+	return lc
+}
+
+// denseRow returns the row of n values of the LinearCombination
+func denseRow(lc LinearCombination, n int) []*big.Int {
+	row := ArrayOfBigZeros(n)
+	for _, t := range lc {
+		row[t.Signal] = new(big.Int).Add(row[t.Signal], t.Coeff)
+	}
+	return row
+}
+
+// NewSparseR1CS returns the SparseR1CS of the dense R1CS matrices
+func NewSparseR1CS(a, b, c [][]*big.Int) SparseR1CS {
+	var r SparseR1CS
+	if len(a) > 0 {
+		r.NSignals = len(a[0])
+	}
+	for i := 0; i < len(a); i++ {
+		r.A = append(r.A, sparseRow(a[i]))
+		r.B = append(r.B, sparseRow(b[i]))
+		r.C = append(r.C, sparseRow(c[i]))
+	}
+	return r
+}
+
+// NConstraints returns the number of constraints of the R1CS
+func (r SparseR1CS) NConstraints() int {
+	return len(r.A)
+}
+
+// Dense returns the dense matrices of the R1CS, of NConstraints rows of
+// NSignals values
+func (r SparseR1CS) Dense() ([][]*big.Int, [][]*big.Int, [][]*big.Int) {
+	var a, b, c [][]*big.Int
+	for i := 0; i < len(r.A); i++ {
+		a = append(a, denseRow(r.A[i], r.NSignals))
+		b = append(b, denseRow(r.B[i], r.NSignals))
+		c = append(c, denseRow(r.C[i], r.NSignals))
+	}
+	return a, b, c
+}
+
+// Eval returns the value of the linear combination for the witness w
+func (lc LinearCombination) Eval(f fields.Fq, w []*big.Int) *big.Int {
+	r := f.Zero()
+	for _, t := range lc {
+		r = f.Add(r, f.Mul(f.Affine(t.Coeff), f.Affine(w[t.Signal])))
+	}
+	return r
+}
+
+// columns returns the polynomial evaluations of each signal at the points of
+// the domain: the values of its column of the matrix, padded with zeros
+func (pf PolynomialField) columns(m []LinearCombination, nSignals int, d EvaluationDomain) [][]*big.Int {
+	cols := make([][]*big.Int, nSignals)
+	for i := 0; i < nSignals; i++ {
+		cols[i] = ArrayOfBigZeros(d.Size)
+	}
+	for j := 0; j < len(m); j++ {
+		for _, t := range m[j] {
+			cols[t.Signal][j] = pf.F.Add(cols[t.Signal][j], pf.F.Affine(t.Coeff))
+		}
+	}
+	return cols
+}
+
+// SparseR1CSToQAP converts the SparseR1CS to the QAP values, as R1CSToQAP
+func (pf PolynomialField) SparseR1CSToQAP(r SparseR1CS) ([][]*big.Int, [][]*big.Int, [][]*big.Int, []*big.Int) {
+	d := pf.mustDomain(r.NConstraints())
+	var alphas, betas, gammas [][]*big.Int
+	for _, col := range pf.columns(r.A, r.NSignals, d) {
+		alphas = append(alphas, d.IFFT(col))
+	}
+	for _, col := range pf.columns(r.B, r.NSignals, d) {
+		betas = append(betas, d.IFFT(col))
+	}
+	for _, col := range pf.columns(r.C, r.NSignals, d) {
+		gammas = append(gammas, d.IFFT(col))
+	}
+	return alphas, betas, gammas, d.VanishingPolynomial()
+}
+
+// lagrangeAt returns the evaluations at x of the Lagrange basis polynomials
+// of the domain, L_j(x) = ω^j/n * (x^n - 1) / (x - ω^j), that are 1 at ω^j
+// and 0 at the other points of the domain
+func (d EvaluationDomain) lagrangeAt(x *big.Int) []*big.Int {
+	ls := make([]*big.Int, d.Size)
+	zx := d.EvalVanishing(x)
+	if d.F.IsZero(zx) {
+		// x is a point of the domain
+		for j := 0; j < d.Size; j++ {
+			ls[j] = d.F.Zero()
+			if d.F.Equal(x, d.Element(j)) {
+				ls[j] = d.F.One()
+			}
+		}
+		return ls
+	}
+	ws := make([]*big.Int, d.Size)
+	dens := make([]*big.Int, d.Size)
+	w := d.F.One()
+	for j := 0; j < d.Size; j++ {
+		ws[j] = w
+		dens[j] = d.F.Sub(x, w)
+		w = d.F.Mul(w, d.Omega)
+	}
+	invs := d.F.BatchInverse(dens)
+	c := d.F.Mul(zx, d.SizeInv)
+	for j := 0; j < d.Size; j++ {
+		ls[j] = d.F.Mul(d.F.Mul(c, ws[j]), invs[j])
+	}
+	return ls
+}
+
+// EvalQAP returns the evaluations at x of the QAP polynomials of each signal
+// of the SparseR1CS (the polynomials of SparseR1CSToQAP), and of its vanishing
+// polynomial z(x), without interpolating them: each one is the sum of the
+// Lagrange basis at x weighted by the coefficients of its column, so it takes
+// O(domain size + terms)
+func (pf PolynomialField) EvalQAP(r SparseR1CS, x *big.Int) ([]*big.Int, []*big.Int, []*big.Int, *big.Int) {
+	d := pf.mustDomain(r.NConstraints())
+	x = pf.F.Affine(x)
+	ls := d.lagrangeAt(x)
+	eval := func(m []LinearCombination) []*big.Int {
+		e := make([]*big.Int, r.NSignals)
+		for i := 0; i < r.NSignals; i++ {
+			e[i] = pf.F.Zero()
+		}
+		for j := 0; j < len(m); j++ {
+			for _, t := range m[j] {
+				e[t.Signal] = pf.F.Add(e[t.Signal], pf.F.Mul(pf.F.Affine(t.Coeff), ls[j]))
+			}
+		}
+		return e
+	}
+	return eval(r.A), eval(r.B), eval(r.C), d.EvalVanishing(x)
+}
+
+// UnsatisfiedConstraint is a constraint of the R1CS that is not satisfied by a
+// witness: A·w * B·w != C·w
+type UnsatisfiedConstraint struct {
+	Index   int      // the row of the constraint in the R1CS
+	A       *big.Int // A·w
+	B       *big.Int // B·w
+	C       *big.Int // C·w
+	Literal string   // the flat code of the constraint, when it is known
+}
+
+// String returns the description of the unsatisfied constraint
+func (u UnsatisfiedConstraint) String() string {
+	s := fmt.Sprintf("constraint %d", u.Index)
+	if u.Literal != "" {
+		s += " (" + u.Literal + ")"
+	}
+	return fmt.Sprintf("%s: %s * %s != %s", s, u.A, u.B, u.C)
+}
+
+// CheckSparseSatisfied returns the constraints of the SparseR1CS that are not
+// satisfied by the witness w, with the values of its linear combinations. It
+// returns an error if w has not a value for each signal
+func (pf PolynomialField) CheckSparseSatisfied(r SparseR1CS, w []*big.Int) ([]UnsatisfiedConstraint, error) {
+	if len(w) != r.NSignals {
+		return nil, fmt.Errorf("witness of %d values for an R1CS of %d signals", len(w), r.NSignals)
+	}
+	var unsatisfied []UnsatisfiedConstraint
+	for i := 0; i < r.NConstraints(); i++ {
+		a := r.A[i].Eval(pf.F, w)
+		b := r.B[i].Eval(pf.F, w)
+		c := r.C[i].Eval(pf.F, w)
+		if !pf.F.Equal(pf.F.Mul(a, b), c) {
+			unsatisfied = append(unsatisfied, UnsatisfiedConstraint{Index: i, A: a, B: b, C: c})
+		}
+	}
+	return unsatisfied, nil
+}
@@ -0,0 +1,109 @@
+package r1csqap
+
+import (
+	"encoding/json"
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testR1CS() ([][]*big.Int, [][]*big.Int, [][]*big.Int) {
+	b0 := big.NewInt(int64(0))
+	b1 := big.NewInt(int64(1))
+	b5 := big.NewInt(int64(5))
+	a := [][]*big.Int{
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b0, b0, b0, b1, b0, b0},
+		[]*big.Int{b0, b1, b0, b0, b1, b0},
+		[]*big.Int{b5, b0, b0, b0, b0, b1},
+	}
+	b := [][]*big.Int{
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b0, b1, b0, b0, b0, b0},
+		[]*big.Int{b1, b0, b0, b0, b0, b0},
+		[]*big.Int{b1, b0, b0, b0, b0, b0},
+	}
+	c := [][]*big.Int{
+		[]*big.Int{b0, b0, b0, b1, b0, b0},
+		[]*big.Int{b0, b0, b0, b0, b1, b0},
+		[]*big.Int{b0, b0, b0, b0, b0, b1},
+		[]*big.Int{b0, b0, b1, b0, b0, b0},
+	}
+	return a, b, c
+}
+
+func TestSparseR1CS(t *testing.T) {
+	a, b, c := testR1CS()
+	r := NewSparseR1CS(a, b, c)
+	assert.Equal(t, 6, r.NSignals)
+	assert.Equal(t, 4, r.NConstraints())
+	assert.Equal(t, LinearCombination{{0, big.NewInt(5)}, {5, big.NewInt(1)}}, r.A[3])
+	assert.Equal(t, LinearCombination{{0, big.NewInt(1)}}, r.B[3])
+
+	a2, b2, c2 := r.Dense()
+	assert.Equal(t, a, a2)
+	assert.Equal(t, b, b2)
+	assert.Equal(t, c, c2)
+
+	rJSON, err := json.Marshal(r)
+	assert.Nil(t, err)
+	var r2 SparseR1CS
+	assert.Nil(t, json.Unmarshal(rJSON, &r2))
+	assert.Equal(t, r, r2)
+
+	pf := newTestPolynomialField(t)
+	w := []*big.Int{big.NewInt(1), big.NewInt(3), big.NewInt(35), big.NewInt(9), big.NewInt(27), big.NewInt(30)}
+	for i := 0; i < r.NConstraints(); i++ {
+		assert.Equal(t, pf.F.Mul(r.A[i].Eval(pf.F, w), r.B[i].Eval(pf.F, w)), r.C[i].Eval(pf.F, w))
+	}
+}
+
+func TestEvalQAP(t *testing.T) {
+	pf := newTestPolynomialField(t)
+	a, b, c := testR1CS()
+	r := NewSparseR1CS(a, b, c)
+	alphas, betas, gammas, z := pf.SparseR1CSToQAP(r)
+
+	rnd := mrand.New(mrand.NewSource(1))
+	x, err := pf.F.RandFrom(rnd)
+	assert.Nil(t, err)
+	d, err := pf.NewEvaluationDomain(r.NConstraints())
+	assert.Nil(t, err)
+	// a random point, and the points of the domain
+	points := []*big.Int{x}
+	for i := 0; i < d.Size; i++ {
+		points = append(points, d.Element(i))
+	}
+	for _, x := range points {
+		at, bt, ct, zt := pf.EvalQAP(r, x)
+		assert.Equal(t, pf.Eval(z, x), zt)
+		for i := 0; i < r.NSignals; i++ {
+			assert.Equal(t, pf.Eval(alphas[i], x), at[i])
+			assert.Equal(t, pf.Eval(betas[i], x), bt[i])
+			assert.Equal(t, pf.Eval(gammas[i], x), ct[i])
+		}
+	}
+}
+
+func TestCheckSatisfied(t *testing.T) {
+	pf := newTestPolynomialField(t)
+	a, b, c := testR1CS()
+	w := []*big.Int{big.NewInt(1), big.NewInt(3), big.NewInt(35), big.NewInt(9), big.NewInt(27), big.NewInt(30)}
+	unsatisfied, err := pf.CheckSatisfied(a, b, c, w)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(unsatisfied))
+
+	// a wrong value of the signal 5 breaks the constraints 2 and 3
+	w[5] = big.NewInt(31)
+	unsatisfied, err = pf.CheckSatisfied(a, b, c, w)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(unsatisfied))
+	assert.Equal(t, UnsatisfiedConstraint{Index: 2, A: big.NewInt(30), B: big.NewInt(1), C: big.NewInt(31)}, unsatisfied[0])
+	assert.Equal(t, UnsatisfiedConstraint{Index: 3, A: big.NewInt(36), B: big.NewInt(1), C: big.NewInt(35)}, unsatisfied[1])
+	assert.Equal(t, "constraint 2: 30 * 1 != 31", unsatisfied[0].String())
+
+	_, err = pf.CheckSatisfied(a, b, c, w[:5])
+	assert.NotNil(t, err)
+}
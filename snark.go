@@ -3,7 +3,10 @@
 package snark
 
 import (
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
 
@@ -14,26 +17,26 @@ import (
 )
 
 type Pk struct { // Proving Key pk:=(pkA, pkB, pkC, pkH)
-	G1T [][3]*big.Int // t encrypted in G1 curve, G1T == Pk.H
-	A   [][3]*big.Int
-	B   [][3][2]*big.Int
-	C   [][3]*big.Int
-	Kp  [][3]*big.Int
-	Ap  [][3]*big.Int
-	Bp  [][3]*big.Int
-	Cp  [][3]*big.Int
+	G1T []bn128.G1Point // t encrypted in G1 curve, G1T == Pk.H
+	A   []bn128.G1Point
+	B   []bn128.G2Point
+	C   []bn128.G1Point
+	Kp  []bn128.G1Point
+	Ap  []bn128.G1Point
+	Bp  []bn128.G1Point
+	Cp  []bn128.G1Point
 	Z   []*big.Int
 }
 
 type Vk struct {
-	Vka   [3][2]*big.Int
-	Vkb   [3]*big.Int
-	Vkc   [3][2]*big.Int
-	IC    [][3]*big.Int
-	G1Kbg [3]*big.Int    // g1 * Kbeta * Kgamma
-	G2Kbg [3][2]*big.Int // g2 * Kbeta * Kgamma
-	G2Kg  [3][2]*big.Int // g2 * Kgamma
-	Vkz   [3][2]*big.Int
+	Vka   bn128.G2Point
+	Vkb   bn128.G1Point
+	Vkc   bn128.G2Point
+	IC    []bn128.G1Point
+	G1Kbg bn128.G1Point // g1 * Kbeta * Kgamma
+	G2Kbg bn128.G2Point // g2 * Kbeta * Kgamma
+	G2Kg  bn128.G2Point // g2 * Kgamma
+	Vkz   bn128.G2Point
 }
 
 // Setup is the data structure holding the Trusted Setup data. The Setup.Toxic sub struct must be destroyed after the GenerateTrustedSetup function is completed
@@ -57,14 +60,14 @@ type Setup struct {
 
 // Proof contains the parameters to proof the zkSNARK
 type Proof struct {
-	PiA  [3]*big.Int
-	PiAp [3]*big.Int
-	PiB  [3][2]*big.Int
-	PiBp [3]*big.Int
-	PiC  [3]*big.Int
-	PiCp [3]*big.Int
-	PiH  [3]*big.Int
-	PiKp [3]*big.Int
+	PiA  bn128.G1Point
+	PiAp bn128.G1Point
+	PiB  bn128.G2Point
+	PiBp bn128.G1Point
+	PiC  bn128.G1Point
+	PiCp bn128.G1Point
+	PiH  bn128.G1Point
+	PiKp bn128.G1Point
 	// PublicSignals []*big.Int
 }
 
@@ -72,6 +75,14 @@ type utils struct {
 	Bn  bn128.Bn128
 	FqR fields.Fq
 	PF  r1csqap.PolynomialField
+	// ConstantTime enables the constant-time scalar multiplications for the
+	// values that depend on the secrets (the toxic values and the witness).
+	// It is slower, so it is opt-in.
+	// Only the scalar ladder (bn128.MulScalarCT and MultiExpCT) runs in
+	// constant time: the secrets are *big.Int, and their conversions to and
+	// from the Montgomery form and the FqR arithmetic over them do branch and
+	// allocate depending on their values
+	ConstantTime bool
 }
 
 // Utils is the data structure holding the BN128, FqR Finite Field over R, PolynomialField, that will be used inside the snarks operations
@@ -94,10 +105,34 @@ func prepareUtils() utils {
 	}
 }
 
+// g1MultiExpPoints returns Σ es[i] * ps[i] for secret scalars, in constant
+// time if Utils.ConstantTime is set
+func g1MultiExpPoints(ps []bn128.G1Point, es []*big.Int) bn128.G1Point {
+	return Utils.Bn.G1.Point(Utils.Bn.G1.MultiExpSecret(bn128.G1Arrays(ps), es, Utils.ConstantTime))
+}
+
 // GenerateTrustedSetup generates the Trusted Setup from a compiled Circuit. The Setup.Toxic sub data structure must be destroyed
-func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, alphas, betas, gammas [][]*big.Int) (Setup, error) {
+func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit) (Setup, error) {
+	return GenerateTrustedSetupWithReader(rand.Reader, witnessLength, circuit)
+}
+
+// GenerateTrustedSetupWithReader generates the Trusted Setup as GenerateTrustedSetup, reading the toxic values from the given randomness source.
+// The QAP polynomials are evaluated at τ directly from the sparse R1CS of the circuit
+func GenerateTrustedSetupWithReader(rnd io.Reader, witnessLength int, circuit circuitcompiler.Circuit) (Setup, error) {
 	var setup Setup
 	var err error
+	if circuit.R1CS.NConstraints() == 0 {
+		return Setup{}, errors.New("circuit without R1CS")
+	}
+	if circuit.R1CS.NSignals != len(circuit.Signals) {
+		return Setup{}, errors.New("R1CS with a different number of signals than the circuit")
+	}
+	// the domain of the QAP
+	domain, err := Utils.PF.NewEvaluationDomain(circuit.R1CS.NConstraints())
+	if err != nil {
+		return Setup{}, err
+	}
+	gm := bn128.NewGeneratorMul(Utils.Bn.G1, Utils.Bn.G2, Utils.ConstantTime)
 
 	// input soundness
 	// for i := 0; i < len(alphas); i++ {
@@ -111,41 +146,41 @@ func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, al
 	// }
 
 	// generate random t value
-	setup.Toxic.T, err = Utils.FqR.Rand()
+	setup.Toxic.T, err = Utils.FqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
 
 	// k for calculating pi' and Vk
-	setup.Toxic.Ka, err = Utils.FqR.Rand()
+	setup.Toxic.Ka, err = Utils.FqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
-	setup.Toxic.Kb, err = Utils.FqR.Rand()
+	setup.Toxic.Kb, err = Utils.FqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
-	setup.Toxic.Kc, err = Utils.FqR.Rand()
+	setup.Toxic.Kc, err = Utils.FqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
 
 	// generate Kβ (Kbeta) and Kγ (Kgamma)
-	setup.Toxic.Kbeta, err = Utils.FqR.Rand()
+	setup.Toxic.Kbeta, err = Utils.FqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
-	setup.Toxic.Kgamma, err = Utils.FqR.Rand()
+	setup.Toxic.Kgamma, err = Utils.FqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
 
 	// generate ρ (Rho): ρA, ρB, ρC
-	setup.Toxic.RhoA, err = Utils.FqR.Rand()
+	setup.Toxic.RhoA, err = Utils.FqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
-	setup.Toxic.RhoB, err = Utils.FqR.Rand()
+	setup.Toxic.RhoB, err = Utils.FqR.RandFrom(rnd)
 	if err != nil {
 		return Setup{}, err
 	}
@@ -162,9 +197,9 @@ func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, al
 	// gt1: g1, g1*t, g1*t^2, g1*t^3, ...
 	// gt2: g2, g2*t, g2*t^2, ...
 
-	setup.Vk.Vka = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Ka)
-	setup.Vk.Vkb = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, setup.Toxic.Kb)
-	setup.Vk.Vkc = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kc)
+	setup.Vk.Vka = Utils.Bn.G2.Point(gm.G2(setup.Toxic.Ka))
+	setup.Vk.Vkb = Utils.Bn.G1.Point(gm.G1(setup.Toxic.Kb))
+	setup.Vk.Vkc = Utils.Bn.G2.Point(gm.G2(setup.Toxic.Kc))
 
 	/*
 		Verification keys:
@@ -173,36 +208,41 @@ func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, al
 		- Vk_gamma: setup.G2Kg = g2 * Kgamma
 	*/
 	kbg := Utils.FqR.Mul(setup.Toxic.Kbeta, setup.Toxic.Kgamma)
-	setup.Vk.G1Kbg = Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, kbg)
-	setup.Vk.G2Kbg = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, kbg)
-	setup.Vk.G2Kg = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, setup.Toxic.Kgamma)
+	setup.Vk.G1Kbg = Utils.Bn.G1.Point(gm.G1(kbg))
+	setup.Vk.G2Kbg = Utils.Bn.G2.Point(gm.G2(kbg))
+	setup.Vk.G2Kg = Utils.Bn.G2.Point(gm.G2(setup.Toxic.Kgamma))
+
+	// the QAP polynomials of each signal and z(x) evaluated at τ
+	ats, bts, cts, zt := Utils.PF.EvalQAP(circuit.R1CS, setup.Toxic.T)
 
+	var pkA, pkC, pkKp, pkAp, pkBp, pkCp, ic [][3]*big.Int
+	var pkB [][3][2]*big.Int
 	// for i := 0; i < circuit.NVars; i++ {
 	for i := 0; i < len(circuit.Signals); i++ {
-		at := Utils.PF.Eval(alphas[i], setup.Toxic.T)
+		at := ats[i]
 		// rhoAat := Utils.Bn.Fq1.Mul(setup.Toxic.RhoA, at)
 		rhoAat := Utils.FqR.Mul(setup.Toxic.RhoA, at)
-		a := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, rhoAat)
-		setup.Pk.A = append(setup.Pk.A, a)
+		a := gm.G1(rhoAat)
+		pkA = append(pkA, a)
 		if i <= circuit.NPublic {
-			setup.Vk.IC = append(setup.Vk.IC, a)
+			ic = append(ic, a)
 		}
 
-		bt := Utils.PF.Eval(betas[i], setup.Toxic.T)
+		bt := bts[i]
 		// rhoBbt := Utils.Bn.Fq1.Mul(setup.Toxic.RhoB, bt)
 		rhoBbt := Utils.FqR.Mul(setup.Toxic.RhoB, bt)
-		bg1 := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, rhoBbt)
-		bg2 := Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, rhoBbt)
-		setup.Pk.B = append(setup.Pk.B, bg2)
+		bg1 := gm.G1(rhoBbt)
+		bg2 := gm.G2(rhoBbt)
+		pkB = append(pkB, bg2)
 
-		ct := Utils.PF.Eval(gammas[i], setup.Toxic.T)
+		ct := cts[i]
 		// rhoCct := Utils.Bn.Fq1.Mul(setup.Toxic.RhoC, ct)
 		rhoCct := Utils.FqR.Mul(setup.Toxic.RhoC, ct)
-		c := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, rhoCct)
-		setup.Pk.C = append(setup.Pk.C, c)
+		c := gm.G1(rhoCct)
+		pkC = append(pkC, c)
 
 		kt := Utils.FqR.Add(Utils.FqR.Add(rhoAat, rhoBbt), rhoCct)
-		k := Utils.Bn.G1.Affine(Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, kt))
+		k := Utils.Bn.G1.Affine(gm.G1(kt))
 
 		ktest := Utils.Bn.G1.Affine(Utils.Bn.G1.Add(Utils.Bn.G1.Add(a, bg1), c))
 		if !Utils.Bn.Fq2.Equal(k, ktest) {
@@ -210,160 +250,259 @@ func GenerateTrustedSetup(witnessLength int, circuit circuitcompiler.Circuit, al
 			return setup, err
 		}
 
-		setup.Pk.Ap = append(setup.Pk.Ap, Utils.Bn.G1.MulScalar(a, setup.Toxic.Ka))
-		setup.Pk.Bp = append(setup.Pk.Bp, Utils.Bn.G1.MulScalar(bg1, setup.Toxic.Kb))
-		setup.Pk.Cp = append(setup.Pk.Cp, Utils.Bn.G1.MulScalar(c, setup.Toxic.Kc))
-		k_ := Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, kt)
-		setup.Pk.Kp = append(setup.Pk.Kp, Utils.Bn.G1.MulScalar(k_, setup.Toxic.Kbeta))
-	}
-
-	// z pol
-	zpol := []*big.Int{big.NewInt(int64(1))}
-	// for i := 0; i < len(circuit.Constraints); i++ {
-	for i := 1; i < len(alphas)-1; i++ {
-		zpol = Utils.PF.Mul(
-			zpol,
-			[]*big.Int{
-				Utils.FqR.Neg( // neg over R
-					big.NewInt(int64(i))),
-				big.NewInt(int64(1)),
-			})
+		// a * Ka == g1 * (rhoAat * Ka), and the same for the others, so all are multiples of the generator
+		pkAp = append(pkAp, gm.G1(Utils.FqR.Mul(rhoAat, setup.Toxic.Ka)))
+		pkBp = append(pkBp, gm.G1(Utils.FqR.Mul(rhoBbt, setup.Toxic.Kb)))
+		pkCp = append(pkCp, gm.G1(Utils.FqR.Mul(rhoCct, setup.Toxic.Kc)))
+		pkKp = append(pkKp, gm.G1(Utils.FqR.Mul(kt, setup.Toxic.Kbeta)))
 	}
+
+	// z pol, the vanishing polynomial of the domain of the QAP
+	zpol := domain.VanishingPolynomial()
 	setup.Pk.Z = zpol
 
-	zt := Utils.PF.Eval(zpol, setup.Toxic.T)
 	// rhoCzt := Utils.Bn.Fq1.Mul(setup.Toxic.RhoC, zt)
 	rhoCzt := Utils.FqR.Mul(setup.Toxic.RhoC, zt)
-	setup.Vk.Vkz = Utils.Bn.G2.MulScalar(Utils.Bn.G2.G, rhoCzt)
+	setup.Vk.Vkz = Utils.Bn.G2.Point(gm.G2(rhoCzt))
 
 	// encrypt t values with curve generators
 	var gt1 [][3]*big.Int
 	gt1 = append(gt1, Utils.Bn.G1.G) // the first is t**0 * G1 = 1 * G1 = G1
 	tEncr := setup.Toxic.T
 	for i := 1; i < len(zpol); i++ { //should be G1T = pkH = (tau**i * G1) from i=0 to d, where d is degree of pol Z(x)
-		gt1 = append(gt1, Utils.Bn.G1.MulScalar(Utils.Bn.G1.G, tEncr))
+		gt1 = append(gt1, gm.G1(tEncr))
 		// tEncr = Utils.Bn.Fq1.Mul(tEncr, setup.Toxic.T)
 		tEncr = Utils.FqR.Mul(tEncr, setup.Toxic.T)
 	}
-	setup.Pk.G1T = gt1
+
+	// normalize the points to affine coordinates, with one inversion per array
+	setup.Pk.G1T = Utils.Bn.G1.Points(Utils.Bn.G1.BatchAffine(gt1))
+	setup.Pk.A = Utils.Bn.G1.Points(Utils.Bn.G1.BatchAffine(pkA))
+	setup.Pk.B = Utils.Bn.G2.Points(Utils.Bn.G2.BatchAffine(pkB))
+	setup.Pk.C = Utils.Bn.G1.Points(Utils.Bn.G1.BatchAffine(pkC))
+	setup.Pk.Kp = Utils.Bn.G1.Points(Utils.Bn.G1.BatchAffine(pkKp))
+	setup.Pk.Ap = Utils.Bn.G1.Points(Utils.Bn.G1.BatchAffine(pkAp))
+	setup.Pk.Bp = Utils.Bn.G1.Points(Utils.Bn.G1.BatchAffine(pkBp))
+	setup.Pk.Cp = Utils.Bn.G1.Points(Utils.Bn.G1.BatchAffine(pkCp))
+	setup.Vk.IC = Utils.Bn.G1.Points(Utils.Bn.G1.BatchAffine(ic))
 
 	return setup, nil
 }
 
-// GenerateProofs generates all the parameters to proof the zkSNARK from the Circuit, Setup and the Witness
-func GenerateProofs(circuit circuitcompiler.Circuit, pk Pk, w []*big.Int, px []*big.Int) (Proof, error) {
+// GenerateProofs generates all the parameters to proof the zkSNARK from the
+// Circuit (with its R1CS), the proving key and the Witness. The h(x) of the
+// QAP is computed from the R1CS and the Witness, with r1csqap.HPolynomial. It
+// returns an error if the Witness does not satisfy the R1CS
+func GenerateProofs(circuit circuitcompiler.Circuit, pk Pk, w []*big.Int) (Proof, error) {
 	var proof Proof
-	proof.PiA = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiAp = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiB = Utils.Bn.Fq6.Zero()
-	proof.PiBp = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiC = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiCp = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiH = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-	proof.PiKp = [3]*big.Int{Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero(), Utils.Bn.G1.F.Zero()}
-
-	for i := circuit.NPublic + 1; i < circuit.NVars; i++ {
-		proof.PiA = Utils.Bn.G1.Add(proof.PiA, Utils.Bn.G1.MulScalar(pk.A[i], w[i]))
-		proof.PiAp = Utils.Bn.G1.Add(proof.PiAp, Utils.Bn.G1.MulScalar(pk.Ap[i], w[i]))
+	if circuit.R1CS.NConstraints() == 0 {
+		return Proof{}, errors.New("circuit without R1CS")
+	}
+	// a witness that does not satisfy the R1CS gives a proof that does not
+	// verify, so it is refused with the failing constraints
+	if err := circuit.CheckWitness(Utils.PF, w); err != nil {
+		return Proof{}, err
 	}
 
-	for i := 0; i < circuit.NVars; i++ {
-		proof.PiB = Utils.Bn.G2.Add(proof.PiB, Utils.Bn.G2.MulScalar(pk.B[i], w[i]))
-		proof.PiBp = Utils.Bn.G1.Add(proof.PiBp, Utils.Bn.G1.MulScalar(pk.Bp[i], w[i]))
+	priv := circuit.NPublic + 1
+	proof.PiA = g1MultiExpPoints(pk.A[priv:circuit.NVars], w[priv:circuit.NVars])
+	proof.PiAp = g1MultiExpPoints(pk.Ap[priv:circuit.NVars], w[priv:circuit.NVars])
 
-		proof.PiC = Utils.Bn.G1.Add(proof.PiC, Utils.Bn.G1.MulScalar(pk.C[i], w[i]))
-		proof.PiCp = Utils.Bn.G1.Add(proof.PiCp, Utils.Bn.G1.MulScalar(pk.Cp[i], w[i]))
+	proof.PiB = Utils.Bn.G2.Point(Utils.Bn.G2.MultiExpSecret(bn128.G2Arrays(pk.B[:circuit.NVars]), w[:circuit.NVars], Utils.ConstantTime))
+	proof.PiBp = g1MultiExpPoints(pk.Bp[:circuit.NVars], w[:circuit.NVars])
 
-		proof.PiKp = Utils.Bn.G1.Add(proof.PiKp, Utils.Bn.G1.MulScalar(pk.Kp[i], w[i]))
-	}
+	proof.PiC = g1MultiExpPoints(pk.C[:circuit.NVars], w[:circuit.NVars])
+	proof.PiCp = g1MultiExpPoints(pk.Cp[:circuit.NVars], w[:circuit.NVars])
+
+	proof.PiKp = g1MultiExpPoints(pk.Kp[:circuit.NVars], w[:circuit.NVars])
 
-	hx := Utils.PF.DivisorPolynomial(px, pk.Z) // maybe move this calculation to a previous step
+	hx := Utils.PF.HPolynomial(circuit.R1CS, w)
+	if len(hx) > len(pk.G1T) {
+		return Proof{}, errors.New("the R1CS has more constraints than the proving key")
+	}
 
 	// piH = pkH,0 + sum (  hi * pk H,i ), where pkH = G1T, hi=hx
 	// proof.PiH = Utils.Bn.G1.Add(proof.PiH, pk.G1T[0])
-	for i := 0; i < len(hx); i++ {
-		proof.PiH = Utils.Bn.G1.Add(proof.PiH, Utils.Bn.G1.MulScalar(pk.G1T[i], hx[i]))
-	}
+	proof.PiH = g1MultiExpPoints(pk.G1T[:len(hx)], hx)
 
 	return proof, nil
 }
 
-// VerifyProof verifies over the BN128 the Pairings of the Proof
-func VerifyProof(vk Vk, proof Proof, publicSignals []*big.Int, debug bool) bool {
-	// e(piA, Va) == e(piA', g2)
-	pairingPiaVa := Utils.Bn.Pairing(proof.PiA, vk.Vka)
-	pairingPiapG2 := Utils.Bn.Pairing(proof.PiAp, Utils.Bn.G2.G)
-	if !Utils.Bn.Fq12.Equal(pairingPiaVa, pairingPiapG2) {
-		if debug {
-			fmt.Println("❌ e(piA, Va) == e(piA', g2), valid knowledge commitment for A")
+// ValidateVk returns a bn128.InvalidPointError if any point of the Vk is not in its group
+func ValidateVk(vk Vk) error {
+	for i := 0; i < len(vk.IC); i++ {
+		if err := Utils.Bn.G1.Validate(vk.IC[i].Array()); err != nil {
+			return fmt.Errorf("Vk.IC[%d]: %w", i, err)
 		}
-		return false
 	}
-	if debug {
-		fmt.Println("✓ e(piA, Va) == e(piA', g2), valid knowledge commitment for A")
+	g1Names := []string{"Vkb", "G1Kbg"}
+	for i, p := range []bn128.G1Point{vk.Vkb, vk.G1Kbg} {
+		if err := Utils.Bn.G1.Validate(p.Array()); err != nil {
+			return fmt.Errorf("Vk.%s: %w", g1Names[i], err)
+		}
+	}
+	g2Names := []string{"Vka", "Vkc", "G2Kbg", "G2Kg", "Vkz"}
+	for i, p := range []bn128.G2Point{vk.Vka, vk.Vkc, vk.G2Kbg, vk.G2Kg, vk.Vkz} {
+		if err := Utils.Bn.G2.Validate(p.Array()); err != nil {
+			return fmt.Errorf("Vk.%s: %w", g2Names[i], err)
+		}
 	}
+	return nil
+}
 
-	// e(Vb, piB) == e(piB', g2)
-	pairingVbPib := Utils.Bn.Pairing(vk.Vkb, proof.PiB)
-	pairingPibpG2 := Utils.Bn.Pairing(proof.PiBp, Utils.Bn.G2.G)
-	if !Utils.Bn.Fq12.Equal(pairingVbPib, pairingPibpG2) {
-		if debug {
-			fmt.Println("❌ e(Vb, piB) == e(piB', g2), valid knowledge commitment for B")
+// ValidateProof returns a bn128.InvalidPointError if any point of the Proof is not in its group
+func ValidateProof(proof Proof) error {
+	g1Names := []string{"PiA", "PiAp", "PiBp", "PiC", "PiCp", "PiH", "PiKp"}
+	for i, p := range []bn128.G1Point{proof.PiA, proof.PiAp, proof.PiBp, proof.PiC, proof.PiCp, proof.PiH, proof.PiKp} {
+		if err := Utils.Bn.G1.Validate(p.Array()); err != nil {
+			return fmt.Errorf("Proof.%s: %w", g1Names[i], err)
 		}
-		return false
 	}
-	if debug {
-		fmt.Println("✓ e(Vb, piB) == e(piB', g2), valid knowledge commitment for B")
+	if err := Utils.Bn.G2.Validate(proof.PiB.Array()); err != nil {
+		return fmt.Errorf("Proof.PiB: %w", err)
 	}
+	return nil
+}
 
-	// e(piC, Vc) == e(piC', g2)
-	pairingPicVc := Utils.Bn.Pairing(proof.PiC, vk.Vkc)
-	pairingPicpG2 := Utils.Bn.Pairing(proof.PiCp, Utils.Bn.G2.G)
-	if !Utils.Bn.Fq12.Equal(pairingPicVc, pairingPicpG2) {
-		if debug {
-			fmt.Println("❌ e(piC, Vc) == e(piC', g2), valid knowledge commitment for C")
-		}
-		return false
+// checkPublicSignals returns an error if the number of public signals is not
+// the one of the Vk, len(vk.IC) - 1
+func checkPublicSignals(vk Vk, publicSignals []*big.Int) error {
+	if len(publicSignals)+1 != len(vk.IC) {
+		return fmt.Errorf("%d public signals for a Vk with %d", len(publicSignals), len(vk.IC)-1)
 	}
-	if debug {
-		fmt.Println("✓ e(piC, Vc) == e(piC', g2), valid knowledge commitment for C")
+	return nil
+}
+
+// VerifyProof verifies over the BN128 the Pairings of the Proof. It returns an
+// error (wrapping a bn128.InvalidPointError) without verifying when a point of
+// the Vk or of the Proof is not in its group, or when the number of public
+// signals is not the one of the Vk
+func VerifyProof(vk Vk, proof Proof, publicSignals []*big.Int, debug bool) (bool, error) {
+	if err := ValidateVk(vk); err != nil {
+		return false, err
 	}
+	return VerifyProofPrepared(PrepareVk(vk), proof, publicSignals, debug)
+}
 
-	// Vkx, to then calculate Vkx+piA
-	vkxpia := vk.IC[0]
-	for i := 0; i < len(publicSignals); i++ {
-		vkxpia = Utils.Bn.G1.Add(vkxpia, Utils.Bn.G1.MulScalar(vk.IC[i+1], publicSignals[i]))
+// PreparedVk is a Vk with the Miller loop line coefficients of its G2 points
+// (and of the G2 generator) already computed, for the verifiers that check
+// many proofs with the same Vk. It is created with PrepareVk
+type PreparedVk struct {
+	Vk    Vk
+	Vka   bn128.AteG2Precomp
+	Vkc   bn128.AteG2Precomp
+	Vkz   bn128.AteG2Precomp
+	G2Kbg bn128.AteG2Precomp
+	G2Kg  bn128.AteG2Precomp
+	G2    bn128.AteG2Precomp // G2 generator
+}
+
+// PrepareVk computes the PreparedVk of the vk. The points of the vk are not
+// checked again when verifying, so it has to be valid (see ValidateVk, the
+// decoders of utils already check it)
+func PrepareVk(vk Vk) PreparedVk {
+	return PreparedVk{
+		Vk:    vk,
+		Vka:   Utils.Bn.PreComputeG2(vk.Vka.Array()),
+		Vkc:   Utils.Bn.PreComputeG2(vk.Vkc.Array()),
+		Vkz:   Utils.Bn.PreComputeG2(vk.Vkz.Array()),
+		G2Kbg: Utils.Bn.PreComputeG2(vk.G2Kbg.Array()),
+		G2Kg:  Utils.Bn.PreComputeG2(vk.G2Kg.Array()),
+		G2:    Utils.Bn.PreComputeG2(Utils.Bn.G2.G),
 	}
+}
 
-	// e(Vkx+piA, piB) == e(piH, Vkz) * e(piC, g2)
-	if !Utils.Bn.Fq12.Equal(
-		Utils.Bn.Pairing(Utils.Bn.G1.Add(vkxpia, proof.PiA), proof.PiB), // TODO Add(vkxpia, proof.PiA) can go outside in order to save computation, as is reused later
-		Utils.Bn.Fq12.Mul(
-			Utils.Bn.Pairing(proof.PiH, vk.Vkz),
-			Utils.Bn.Pairing(proof.PiC, Utils.Bn.G2.G))) {
-		if debug {
-			fmt.Println("❌ e(Vkx+piA, piB) == e(piH, Vkz) * e(piC, g2), QAP disibility checked")
-		}
-		return false
+// VerifyProofPrepared verifies the Proof as VerifyProof, with the
+// precomputations of the PreparedVk, in a single product of pairings. It returns an error (wrapping a
+// bn128.InvalidPointError) without verifying when a point of the Proof is not
+// in its group, or when the number of public signals is not the one of the Vk
+func VerifyProofPrepared(pvk PreparedVk, proof Proof, publicSignals []*big.Int, debug bool) (bool, error) {
+	if err := ValidateProof(proof); err != nil {
+		return false, err
 	}
-	if debug {
-		fmt.Println("✓ e(Vkx+piA, piB) == e(piH, Vkz) * e(piC, g2), QAP disibility checked")
+	if err := checkPublicSignals(pvk.Vk, publicSignals); err != nil {
+		return false, err
+	}
+	vk := pvk.Vk
+	piB := Utils.Bn.PreComputeG2(proof.PiB.Array())
+
+	// Vkx, to then calculate Vkx+piA
+	ic := bn128.G1Arrays(vk.IC)
+	vkx := Utils.Bn.G1.Add(ic[0], Utils.Bn.G1.MultiExp(ic[1:], publicSignals))
+	vkxpia := Utils.Bn.G1.Add(vkx, proof.PiA.Array())
+	piApiC := Utils.Bn.G1.Add(vkxpia, proof.PiC.Array())
+
+	// each check is Π e(g1s[i], g2s[i]) == 1
+	checks := []struct {
+		name string
+		g1s  [][3]*big.Int
+		g2s  []bn128.AteG2Precomp
+	}{
+		{
+			"e(piA, Va) == e(piA', g2), valid knowledge commitment for A",
+			[][3]*big.Int{proof.PiA.Array(), proof.PiAp.Neg().Array()},
+			[]bn128.AteG2Precomp{pvk.Vka, pvk.G2},
+		},
+		{
+			"e(Vb, piB) == e(piB', g2), valid knowledge commitment for B",
+			[][3]*big.Int{vk.Vkb.Array(), proof.PiBp.Neg().Array()},
+			[]bn128.AteG2Precomp{piB, pvk.G2},
+		},
+		{
+			"e(piC, Vc) == e(piC', g2), valid knowledge commitment for C",
+			[][3]*big.Int{proof.PiC.Array(), proof.PiCp.Neg().Array()},
+			[]bn128.AteG2Precomp{pvk.Vkc, pvk.G2},
+		},
+		{
+			"e(Vkx+piA, piB) == e(piH, Vkz) * e(piC, g2), QAP disibility checked",
+			[][3]*big.Int{vkxpia, proof.PiH.Neg().Array(), proof.PiC.Neg().Array()},
+			[]bn128.AteG2Precomp{piB, pvk.Vkz, pvk.G2},
+		},
+		{
+			"e(Vkx+piA+piC, g2KbetaKgamma) * e(g1KbetaKgamma, piB) == e(piK, g2Kgamma)",
+			[][3]*big.Int{piApiC, vk.G1Kbg.Array(), proof.PiKp.Neg().Array()},
+			[]bn128.AteG2Precomp{pvk.G2Kbg, piB, pvk.G2Kg},
+		},
 	}
 
-	// e(Vkx+piA+piC, g2KbetaKgamma) * e(g1KbetaKgamma, piB)
-	// == e(piK, g2Kgamma)
-	piApiC := Utils.Bn.G1.Add(Utils.Bn.G1.Add(vkxpia, proof.PiA), proof.PiC)
-	pairingPiACG2Kbg := Utils.Bn.Pairing(piApiC, vk.G2Kbg)
-	pairingG1KbgPiB := Utils.Bn.Pairing(vk.G1Kbg, proof.PiB)
-	pairingL := Utils.Bn.Fq12.Mul(pairingPiACG2Kbg, pairingG1KbgPiB)
-	pairingR := Utils.Bn.Pairing(proof.PiKp, vk.G2Kg)
-	if !Utils.Bn.Fq12.Equal(pairingL, pairingR) {
-		fmt.Println("❌ e(Vkx+piA+piC, g2KbetaKgamma) * e(g1KbetaKgamma, piB) == e(piK, g2Kgamma)")
-		return false
+	// the checks are verified at once, with a single final exponentiation, as
+	// Π_j (Π_i e(g1s[i], g2s[i]))^r_j == 1 for r_0 = 1 and random r_j unknown
+	// to the prover, multiplying the G1 points by them. A proof that fails
+	// any of the checks passes it with probability 1/R
+	var g1s [][3]*big.Int
+	var g2s []bn128.AteG2Precomp
+	for j, c := range checks {
+		r := big.NewInt(int64(1))
+		if j > 0 {
+			var err error
+			r, err = Utils.FqR.Rand()
+			if err != nil {
+				return false, err
+			}
+		}
+		for i := 0; i < len(c.g1s); i++ {
+			g1s = append(g1s, Utils.Bn.G1.MulScalar(c.g1s[i], r))
+			g2s = append(g2s, c.g2s[i])
+		}
+	}
+	one := Utils.Bn.Fq12.One()
+	if Utils.Bn.Fq12.Equal(one, Utils.Bn.PairingProduct(g1s, g2s)) {
+		if debug {
+			for _, c := range checks {
+				fmt.Println("✓ " + c.name)
+			}
+		}
+		return true, nil
 	}
 	if debug {
-		fmt.Println("✓ e(Vkx+piA+piC, g2KbetaKgamma) * e(g1KbetaKgamma, piB) == e(piK, g2Kgamma)")
+		// each check alone, to tell which ones fail
+		for _, c := range checks {
+			if Utils.Bn.Fq12.Equal(one, Utils.Bn.PairingProduct(c.g1s, c.g2s)) {
+				fmt.Println("✓ " + c.name)
+			} else {
+				fmt.Println("❌ " + c.name)
+			}
+		}
 	}
-
-	return true
+	return false, nil
 }
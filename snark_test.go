@@ -61,24 +61,24 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	assert.True(t, !bytes.Equal(alphas[1][1].Bytes(), big.NewInt(int64(0)).Bytes()))
 
 	ax, bx, cx, px := Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
-	assert.Equal(t, 7, len(ax))
-	assert.Equal(t, 7, len(bx))
-	assert.Equal(t, 7, len(cx))
-	assert.Equal(t, 13, len(px))
+	assert.Equal(t, 8, len(ax))
+	assert.Equal(t, 8, len(bx))
+	assert.Equal(t, 8, len(cx))
+	assert.Equal(t, 15, len(px))
 
 	// ---
 	// from here is the GROTH16
 	// ---
 	// calculate trusted setup
 	fmt.Println("groth")
-	setup, err := groth16.GenerateTrustedSetup(len(w), *circuit, alphas, betas, gammas)
+	setup, err := groth16.GenerateTrustedSetup(len(w), *circuit)
 	assert.Nil(t, err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
 	hx := Utils.PF.DivisorPolynomial(px, setup.Pk.Z)
 	div, rem := Utils.PF.Div(px, setup.Pk.Z)
 	assert.Equal(t, hx, div)
-	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(6))
+	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(8))
 
 	// hx==px/zx so px==hx*zx
 	assert.Equal(t, px, Utils.PF.Mul(hx, setup.Pk.Z))
@@ -86,7 +86,7 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	// check length of polynomials H(x) and Z(x)
 	assert.Equal(t, len(hx), len(px)-len(setup.Pk.Z)+1)
 
-	proof, err := groth16.GenerateProofs(*circuit, setup.Pk, w, px)
+	proof, err := groth16.GenerateProofs(*circuit, setup.Pk, w)
 	assert.Nil(t, err)
 
 	// fmt.Println("\n proofs:")
@@ -98,13 +98,17 @@ func TestGroth16MinimalFlow(t *testing.T) {
 	b35Verif := big.NewInt(int64(35))
 	publicSignalsVerif := []*big.Int{b35Verif}
 	before := time.Now()
-	assert.True(t, groth16.VerifyProof(setup.Vk, proof, publicSignalsVerif, true))
+	verified, err := groth16.VerifyProof(setup.Vk, proof, publicSignalsVerif, true)
+	assert.Nil(t, err)
+	assert.True(t, verified)
 	fmt.Println("verify proof time elapsed:", time.Since(before))
 
 	// check that with another public input the verification returns false
 	bOtherWrongPublic := big.NewInt(int64(34))
 	wrongPublicSignalsVerif := []*big.Int{bOtherWrongPublic}
-	assert.True(t, !groth16.VerifyProof(setup.Vk, proof, wrongPublicSignalsVerif, false))
+	verified, err = groth16.VerifyProof(setup.Vk, proof, wrongPublicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
 }
 
 func TestZkFromFlatCircuitCode(t *testing.T) {
@@ -173,14 +177,14 @@ func TestZkFromFlatCircuitCode(t *testing.T) {
 	assert.Equal(t, 8, len(alphas))
 	assert.Equal(t, 8, len(alphas))
 	assert.Equal(t, 8, len(alphas))
-	assert.Equal(t, 7, len(zxQAP))
+	assert.Equal(t, 9, len(zxQAP))
 	assert.True(t, !bytes.Equal(alphas[1][1].Bytes(), big.NewInt(int64(0)).Bytes()))
 
 	ax, bx, cx, px := Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
-	assert.Equal(t, 7, len(ax))
-	assert.Equal(t, 7, len(bx))
-	assert.Equal(t, 7, len(cx))
-	assert.Equal(t, 13, len(px))
+	assert.Equal(t, 8, len(ax))
+	assert.Equal(t, 8, len(bx))
+	assert.Equal(t, 8, len(cx))
+	assert.Equal(t, 15, len(px))
 
 	hxQAP := Utils.PF.DivisorPolynomial(px, zxQAP)
 	assert.Equal(t, 7, len(hxQAP))
@@ -196,14 +200,14 @@ func TestZkFromFlatCircuitCode(t *testing.T) {
 
 	div, rem := Utils.PF.Div(px, zxQAP)
 	assert.Equal(t, hxQAP, div)
-	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(6))
+	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(8))
 
 	// calculate trusted setup
-	setup, err := GenerateTrustedSetup(len(w), *circuit, alphas, betas, gammas)
+	setup, err := GenerateTrustedSetup(len(w), *circuit)
 	assert.Nil(t, err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
-	// zx and setup.Pk.Z should be the same (currently not, the correct one is the calculation used inside GenerateTrustedSetup function), the calculation is repeated. TODO avoid repeating calculation
+	// zx and setup.Pk.Z are the same, the vanishing polynomial of the domain of the QAP
 	assert.Equal(t, zxQAP, setup.Pk.Z)
 
 	hx := Utils.PF.DivisorPolynomial(px, setup.Pk.Z)
@@ -211,7 +215,7 @@ func TestZkFromFlatCircuitCode(t *testing.T) {
 	// assert.Equal(t, hxQAP, hx)
 	div, rem = Utils.PF.Div(px, setup.Pk.Z)
 	assert.Equal(t, hx, div)
-	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(6))
+	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(8))
 
 	assert.Equal(t, px, Utils.PF.Mul(hxQAP, zxQAP))
 	// hx==px/zx so px==hx*zx
@@ -221,7 +225,7 @@ func TestZkFromFlatCircuitCode(t *testing.T) {
 	assert.Equal(t, len(hx), len(px)-len(setup.Pk.Z)+1)
 	assert.Equal(t, len(hxQAP), len(px)-len(zxQAP)+1)
 
-	proof, err := GenerateProofs(*circuit, setup.Pk, w, px)
+	proof, err := GenerateProofs(*circuit, setup.Pk, w)
 	assert.Nil(t, err)
 
 	// fmt.Println("\n proofs:")
@@ -233,13 +237,17 @@ func TestZkFromFlatCircuitCode(t *testing.T) {
 	b35Verif := big.NewInt(int64(35))
 	publicSignalsVerif := []*big.Int{b35Verif}
 	before := time.Now()
-	assert.True(t, VerifyProof(setup.Vk, proof, publicSignalsVerif, true))
+	verified, err := VerifyProof(setup.Vk, proof, publicSignalsVerif, true)
+	assert.Nil(t, err)
+	assert.True(t, verified)
 	fmt.Println("verify proof time elapsed:", time.Since(before))
 
 	// check that with another public input the verification returns false
 	bOtherWrongPublic := big.NewInt(int64(34))
 	wrongPublicSignalsVerif := []*big.Int{bOtherWrongPublic}
-	assert.True(t, !VerifyProof(setup.Vk, proof, wrongPublicSignalsVerif, false))
+	verified, err = VerifyProof(setup.Vk, proof, wrongPublicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
 }
 
 func TestZkMultiplication(t *testing.T) {
@@ -306,11 +314,11 @@ func TestZkMultiplication(t *testing.T) {
 	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(4))
 
 	// calculate trusted setup
-	setup, err := GenerateTrustedSetup(len(w), *circuit, alphas, betas, gammas)
+	setup, err := GenerateTrustedSetup(len(w), *circuit)
 	assert.Nil(t, err)
 	// fmt.Println("\nt:", setup.Toxic.T)
 
-	// zx and setup.Pk.Z should be the same (currently not, the correct one is the calculation used inside GenerateTrustedSetup function), the calculation is repeated. TODO avoid repeating calculation
+	// zx and setup.Pk.Z are the same, the vanishing polynomial of the domain of the QAP
 	assert.Equal(t, zxQAP, setup.Pk.Z)
 
 	hx := Utils.PF.DivisorPolynomial(px, setup.Pk.Z)
@@ -329,7 +337,7 @@ func TestZkMultiplication(t *testing.T) {
 	assert.Equal(t, len(hx), len(px)-len(setup.Pk.Z)+1)
 	assert.Equal(t, len(hxQAP), len(px)-len(zxQAP)+1)
 
-	proof, err := GenerateProofs(*circuit, setup.Pk, w, px)
+	proof, err := GenerateProofs(*circuit, setup.Pk, w)
 	assert.Nil(t, err)
 
 	// fmt.Println("\n proofs:")
@@ -341,13 +349,38 @@ func TestZkMultiplication(t *testing.T) {
 	b12Verif := big.NewInt(int64(12))
 	publicSignalsVerif := []*big.Int{b12Verif}
 	before := time.Now()
-	assert.True(t, VerifyProof(setup.Vk, proof, publicSignalsVerif, true))
+	verified, err := VerifyProof(setup.Vk, proof, publicSignalsVerif, true)
+	assert.Nil(t, err)
+	assert.True(t, verified)
 	fmt.Println("verify proof time elapsed:", time.Since(before))
 
 	// check that with another public input the verification returns false
 	bOtherWrongPublic := big.NewInt(int64(11))
 	wrongPublicSignalsVerif := []*big.Int{bOtherWrongPublic}
-	assert.True(t, !VerifyProof(setup.Vk, proof, wrongPublicSignalsVerif, false))
+	verified, err = VerifyProof(setup.Vk, proof, wrongPublicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	// the same with the PreparedVk
+	pvk := PrepareVk(setup.Vk)
+	verified, err = VerifyProofPrepared(pvk, proof, publicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+	verified, err = VerifyProofPrepared(pvk, proof, wrongPublicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
+
+	// the public signals must be as many as in the Vk
+	verified, err = VerifyProofPrepared(pvk, proof, []*big.Int{}, false)
+	assert.False(t, verified)
+	assert.NotNil(t, err)
+
+	// a proof that only fails one of the checks is rejected
+	badKp := proof
+	badKp.PiKp = badKp.PiKp.Add(Utils.Bn.G1.Point(Utils.Bn.G1.G))
+	verified, err = VerifyProofPrepared(pvk, badKp, publicSignalsVerif, true)
+	assert.Nil(t, err)
+	assert.False(t, verified)
 }
 
 func TestMinimalFlow(t *testing.T) {
@@ -397,20 +430,20 @@ func TestMinimalFlow(t *testing.T) {
 	assert.True(t, !bytes.Equal(alphas[1][1].Bytes(), big.NewInt(int64(0)).Bytes()))
 
 	ax, bx, cx, px := Utils.PF.CombinePolynomials(w, alphas, betas, gammas)
-	assert.Equal(t, 7, len(ax))
-	assert.Equal(t, 7, len(bx))
-	assert.Equal(t, 7, len(cx))
-	assert.Equal(t, 13, len(px))
+	assert.Equal(t, 8, len(ax))
+	assert.Equal(t, 8, len(bx))
+	assert.Equal(t, 8, len(cx))
+	assert.Equal(t, 15, len(px))
 
 	// calculate trusted setup
-	setup, err := GenerateTrustedSetup(len(w), *circuit, alphas, betas, gammas)
+	setup, err := GenerateTrustedSetup(len(w), *circuit)
 	assert.Nil(t, err)
 	fmt.Println("\nt:", setup.Toxic.T)
 
 	hx := Utils.PF.DivisorPolynomial(px, setup.Pk.Z)
 	div, rem := Utils.PF.Div(px, setup.Pk.Z)
 	assert.Equal(t, hx, div)
-	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(6))
+	assert.Equal(t, rem, r1csqap.ArrayOfBigZeros(8))
 
 	// hx==px/zx so px==hx*zx
 	assert.Equal(t, px, Utils.PF.Mul(hx, setup.Pk.Z))
@@ -418,7 +451,7 @@ func TestMinimalFlow(t *testing.T) {
 	// check length of polynomials H(x) and Z(x)
 	assert.Equal(t, len(hx), len(px)-len(setup.Pk.Z)+1)
 
-	proof, err := GenerateProofs(*circuit, setup.Pk, w, px)
+	proof, err := GenerateProofs(*circuit, setup.Pk, w)
 	assert.Nil(t, err)
 
 	// fmt.Println("\n proofs:")
@@ -430,11 +463,15 @@ func TestMinimalFlow(t *testing.T) {
 	b35Verif := big.NewInt(int64(35))
 	publicSignalsVerif := []*big.Int{b35Verif}
 	before := time.Now()
-	assert.True(t, VerifyProof(setup.Vk, proof, publicSignalsVerif, true))
+	verified, err := VerifyProof(setup.Vk, proof, publicSignalsVerif, true)
+	assert.Nil(t, err)
+	assert.True(t, verified)
 	fmt.Println("verify proof time elapsed:", time.Since(before))
 
 	// check that with another public input the verification returns false
 	bOtherWrongPublic := big.NewInt(int64(34))
 	wrongPublicSignalsVerif := []*big.Int{bOtherWrongPublic}
-	assert.True(t, !VerifyProof(setup.Vk, proof, wrongPublicSignalsVerif, false))
+	verified, err = VerifyProof(setup.Vk, proof, wrongPublicSignalsVerif, false)
+	assert.Nil(t, err)
+	assert.False(t, verified)
 }
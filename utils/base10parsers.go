@@ -5,8 +5,11 @@ import (
 	"math/big"
 
 	snark "github.com/arnaucube/go-snark"
+	"github.com/arnaucube/go-snark/bn128"
 	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/curve"
 	"github.com/arnaucube/go-snark/groth16"
+	"github.com/arnaucube/go-snark/r1csqap"
 )
 
 // []*big.Int
@@ -131,6 +134,86 @@ func Array32BigIntToString(b [][3][2]*big.Int) [][3][2]string {
 	return o
 }
 
+// bn128.G1Point
+func String3ToG1Point(g1 bn128.G1, s [3]string) (bn128.G1Point, error) {
+	p, err := String3ToBigInt(s)
+	if err != nil {
+		return bn128.G1Point{}, err
+	}
+	if err := g1.CheckInfinity(p); err != nil {
+		return bn128.G1Point{}, err
+	}
+	return g1.Point(p), nil
+}
+
+// G1PointToString encodes the point in affine coordinates, [0, 0, 0] for the
+// point at infinity
+func G1PointToString(p bn128.G1Point) [3]string {
+	return BigInt3ToString(p.Affine().Array())
+}
+
+// []bn128.G1Point
+func Array3StringToG1Points(g1 bn128.G1, s [][3]string) ([]bn128.G1Point, error) {
+	ps, err := Array3StringToBigInt(s)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(ps); i++ {
+		if err := g1.CheckInfinity(ps[i]); err != nil {
+			return nil, err
+		}
+	}
+	return g1.Points(ps), nil
+}
+
+// G1PointsToString encodes the points in affine coordinates
+func G1PointsToString(ps []bn128.G1Point) [][3]string {
+	if len(ps) == 0 {
+		return nil
+	}
+	return Array3BigIntToString(ps[0].Group().BatchAffine(bn128.G1Arrays(ps)))
+}
+
+// bn128.G2Point
+func String32ToG2Point(g2 bn128.G2, s [3][2]string) (bn128.G2Point, error) {
+	p, err := String32ToBigInt(s)
+	if err != nil {
+		return bn128.G2Point{}, err
+	}
+	if err := g2.CheckInfinity(p); err != nil {
+		return bn128.G2Point{}, err
+	}
+	return g2.Point(p), nil
+}
+
+// G2PointToString encodes the point in affine coordinates, G2.Zero() for the
+// point at infinity
+func G2PointToString(p bn128.G2Point) [3][2]string {
+	return BigInt32ToString(p.Affine().Array())
+}
+
+// []bn128.G2Point
+func Array32StringToG2Points(g2 bn128.G2, s [][3][2]string) ([]bn128.G2Point, error) {
+	ps, err := Array32StringToBigInt(s)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(ps); i++ {
+		if err := g2.CheckInfinity(ps[i]); err != nil {
+			return nil, err
+		}
+	}
+	return g2.Points(ps), nil
+}
+
+// G2PointsToString encodes the points in affine coordinates
+func G2PointsToString(ps []bn128.G2Point) [][3][2]string {
+	if len(ps) == 0 {
+		return nil
+	}
+	return Array32BigIntToString(ps[0].Group().BatchAffine(bn128.G2Arrays(ps)))
+}
+
 // Setup
 type SetupString struct {
 	// public
@@ -159,57 +242,57 @@ type SetupString struct {
 
 func SetupToString(setup snark.Setup) SetupString {
 	var s SetupString
-	s.Pk.G1T = Array3BigIntToString(setup.Pk.G1T)
-	s.Pk.A = Array3BigIntToString(setup.Pk.A)
-	s.Pk.B = Array32BigIntToString(setup.Pk.B)
-	s.Pk.C = Array3BigIntToString(setup.Pk.C)
-	s.Pk.Kp = Array3BigIntToString(setup.Pk.Kp)
-	s.Pk.Ap = Array3BigIntToString(setup.Pk.Ap)
-	s.Pk.Bp = Array3BigIntToString(setup.Pk.Bp)
-	s.Pk.Cp = Array3BigIntToString(setup.Pk.Cp)
+	s.Pk.G1T = G1PointsToString(setup.Pk.G1T)
+	s.Pk.A = G1PointsToString(setup.Pk.A)
+	s.Pk.B = G2PointsToString(setup.Pk.B)
+	s.Pk.C = G1PointsToString(setup.Pk.C)
+	s.Pk.Kp = G1PointsToString(setup.Pk.Kp)
+	s.Pk.Ap = G1PointsToString(setup.Pk.Ap)
+	s.Pk.Bp = G1PointsToString(setup.Pk.Bp)
+	s.Pk.Cp = G1PointsToString(setup.Pk.Cp)
 	s.Pk.Z = ArrayBigIntToString(setup.Pk.Z)
-	s.Vk.Vka = BigInt32ToString(setup.Vk.Vka)
-	s.Vk.Vkb = BigInt3ToString(setup.Vk.Vkb)
-	s.Vk.Vkc = BigInt32ToString(setup.Vk.Vkc)
-	s.Vk.IC = Array3BigIntToString(setup.Vk.IC)
-	s.Vk.G1Kbg = BigInt3ToString(setup.Vk.G1Kbg)
-	s.Vk.G2Kbg = BigInt32ToString(setup.Vk.G2Kbg)
-	s.Vk.G2Kg = BigInt32ToString(setup.Vk.G2Kg)
-	s.Vk.Vkz = BigInt32ToString(setup.Vk.Vkz)
+	s.Vk.Vka = G2PointToString(setup.Vk.Vka)
+	s.Vk.Vkb = G1PointToString(setup.Vk.Vkb)
+	s.Vk.Vkc = G2PointToString(setup.Vk.Vkc)
+	s.Vk.IC = G1PointsToString(setup.Vk.IC)
+	s.Vk.G1Kbg = G1PointToString(setup.Vk.G1Kbg)
+	s.Vk.G2Kbg = G2PointToString(setup.Vk.G2Kbg)
+	s.Vk.G2Kg = G2PointToString(setup.Vk.G2Kg)
+	s.Vk.Vkz = G2PointToString(setup.Vk.Vkz)
 	return s
 }
 func SetupFromString(s SetupString) (snark.Setup, error) {
 	var o snark.Setup
 	var err error
-	o.Pk.G1T, err = Array3StringToBigInt(s.Pk.G1T)
+	o.Pk.G1T, err = Array3StringToG1Points(snark.Utils.Bn.G1, s.Pk.G1T)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.A, err = Array3StringToBigInt(s.Pk.A)
+	o.Pk.A, err = Array3StringToG1Points(snark.Utils.Bn.G1, s.Pk.A)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.B, err = Array32StringToBigInt(s.Pk.B)
+	o.Pk.B, err = Array32StringToG2Points(snark.Utils.Bn.G2, s.Pk.B)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.C, err = Array3StringToBigInt(s.Pk.C)
+	o.Pk.C, err = Array3StringToG1Points(snark.Utils.Bn.G1, s.Pk.C)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.Kp, err = Array3StringToBigInt(s.Pk.Kp)
+	o.Pk.Kp, err = Array3StringToG1Points(snark.Utils.Bn.G1, s.Pk.Kp)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.Ap, err = Array3StringToBigInt(s.Pk.Ap)
+	o.Pk.Ap, err = Array3StringToG1Points(snark.Utils.Bn.G1, s.Pk.Ap)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.Bp, err = Array3StringToBigInt(s.Pk.Bp)
+	o.Pk.Bp, err = Array3StringToG1Points(snark.Utils.Bn.G1, s.Pk.Bp)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.Cp, err = Array3StringToBigInt(s.Pk.Cp)
+	o.Pk.Cp, err = Array3StringToG1Points(snark.Utils.Bn.G1, s.Pk.Cp)
 	if err != nil {
 		return o, err
 	}
@@ -218,39 +301,42 @@ func SetupFromString(s SetupString) (snark.Setup, error) {
 		return o, err
 	}
 
-	o.Vk.Vka, err = String32ToBigInt(s.Vk.Vka)
+	o.Vk.Vka, err = String32ToG2Point(snark.Utils.Bn.G2, s.Vk.Vka)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.Vkb, err = String3ToBigInt(s.Vk.Vkb)
+	o.Vk.Vkb, err = String3ToG1Point(snark.Utils.Bn.G1, s.Vk.Vkb)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.Vkc, err = String32ToBigInt(s.Vk.Vkc)
+	o.Vk.Vkc, err = String32ToG2Point(snark.Utils.Bn.G2, s.Vk.Vkc)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.IC, err = Array3StringToBigInt(s.Vk.IC)
+	o.Vk.IC, err = Array3StringToG1Points(snark.Utils.Bn.G1, s.Vk.IC)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.G1Kbg, err = String3ToBigInt(s.Vk.G1Kbg)
+	o.Vk.G1Kbg, err = String3ToG1Point(snark.Utils.Bn.G1, s.Vk.G1Kbg)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.G2Kbg, err = String32ToBigInt(s.Vk.G2Kbg)
+	o.Vk.G2Kbg, err = String32ToG2Point(snark.Utils.Bn.G2, s.Vk.G2Kbg)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.G2Kg, err = String32ToBigInt(s.Vk.G2Kg)
+	o.Vk.G2Kg, err = String32ToG2Point(snark.Utils.Bn.G2, s.Vk.G2Kg)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.Vkz, err = String32ToBigInt(s.Vk.Vkz)
+	o.Vk.Vkz, err = String32ToG2Point(snark.Utils.Bn.G2, s.Vk.Vkz)
 	if err != nil {
 		return o, err
 	}
 
+	if err := snark.ValidateVk(o.Vk); err != nil {
+		return o, err
+	}
 	return o, nil
 
 }
@@ -266,12 +352,51 @@ type CircuitString struct {
 	Witness       []string
 	Constraints   []circuitcompiler.Constraint
 	R1CS          struct {
-		A [][]string
-		B [][]string
-		C [][]string
+		NSignals int
+		A        [][]TermString
+		B        [][]TermString
+		C        [][]TermString
 	}
 }
 
+// TermString is the r1csqap.Term with the coefficient in base 10
+type TermString struct {
+	Signal int
+	Coeff  string
+}
+
+// []r1csqap.LinearCombination
+func LinearCombinationsToString(lcs []r1csqap.LinearCombination) [][]TermString {
+	var o [][]TermString
+	for i := 0; i < len(lcs); i++ {
+		lc := lcs[i]
+		ts := []TermString{}
+		for j := 0; j < len(lc); j++ {
+			ts = append(ts, TermString{lc[j].Signal, lc[j].Coeff.String()})
+		}
+		o = append(o, ts)
+	}
+	return o
+}
+func StringToLinearCombinations(s [][]TermString, nSignals int) ([]r1csqap.LinearCombination, error) {
+	var o []r1csqap.LinearCombination
+	for i := 0; i < len(s); i++ {
+		var lc r1csqap.LinearCombination
+		for j := 0; j < len(s[i]); j++ {
+			if s[i][j].Signal < 0 || s[i][j].Signal >= nSignals {
+				return nil, errors.New("R1CS term of a signal out of range")
+			}
+			coeff, ok := new(big.Int).SetString(s[i][j].Coeff, 10)
+			if !ok {
+				return nil, errors.New("error parsing R1CS coefficient from coeffString")
+			}
+			lc = append(lc, r1csqap.Term{Signal: s[i][j].Signal, Coeff: coeff})
+		}
+		o = append(o, lc)
+	}
+	return o, nil
+}
+
 func ArrayArrayBigIntToString(b [][]*big.Int) [][]string {
 	var o [][]string
 	for i := 0; i < len(b); i++ {
@@ -300,9 +425,10 @@ func CircuitToString(c circuitcompiler.Circuit) CircuitString {
 	cs.Signals = c.Signals
 	cs.Witness = ArrayBigIntToString(c.Witness)
 	cs.Constraints = c.Constraints
-	cs.R1CS.A = ArrayArrayBigIntToString(c.R1CS.A)
-	cs.R1CS.B = ArrayArrayBigIntToString(c.R1CS.B)
-	cs.R1CS.C = ArrayArrayBigIntToString(c.R1CS.C)
+	cs.R1CS.NSignals = c.R1CS.NSignals
+	cs.R1CS.A = LinearCombinationsToString(c.R1CS.A)
+	cs.R1CS.B = LinearCombinationsToString(c.R1CS.B)
+	cs.R1CS.C = LinearCombinationsToString(c.R1CS.C)
 	return cs
 }
 func CircuitFromString(cs CircuitString) (circuitcompiler.Circuit, error) {
@@ -319,15 +445,16 @@ func CircuitFromString(cs CircuitString) (circuitcompiler.Circuit, error) {
 		return c, err
 	}
 	c.Constraints = cs.Constraints
-	c.R1CS.A, err = ArrayArrayStringToBigInt(cs.R1CS.A)
+	c.R1CS.NSignals = cs.R1CS.NSignals
+	c.R1CS.A, err = StringToLinearCombinations(cs.R1CS.A, cs.R1CS.NSignals)
 	if err != nil {
 		return c, err
 	}
-	c.R1CS.B, err = ArrayArrayStringToBigInt(cs.R1CS.B)
+	c.R1CS.B, err = StringToLinearCombinations(cs.R1CS.B, cs.R1CS.NSignals)
 	if err != nil {
 		return c, err
 	}
-	c.R1CS.C, err = ArrayArrayStringToBigInt(cs.R1CS.C)
+	c.R1CS.C, err = StringToLinearCombinations(cs.R1CS.C, cs.R1CS.NSignals)
 	if err != nil {
 		return c, err
 	}
@@ -348,56 +475,81 @@ type ProofString struct {
 
 func ProofToString(p snark.Proof) ProofString {
 	var s ProofString
-	s.PiA = BigInt3ToString(p.PiA)
-	s.PiAp = BigInt3ToString(p.PiAp)
-	s.PiB = BigInt32ToString(p.PiB)
-	s.PiBp = BigInt3ToString(p.PiBp)
-	s.PiC = BigInt3ToString(p.PiC)
-	s.PiCp = BigInt3ToString(p.PiCp)
-	s.PiH = BigInt3ToString(p.PiH)
-	s.PiKp = BigInt3ToString(p.PiKp)
+	s.PiA = G1PointToString(p.PiA)
+	s.PiAp = G1PointToString(p.PiAp)
+	s.PiB = G2PointToString(p.PiB)
+	s.PiBp = G1PointToString(p.PiBp)
+	s.PiC = G1PointToString(p.PiC)
+	s.PiCp = G1PointToString(p.PiCp)
+	s.PiH = G1PointToString(p.PiH)
+	s.PiKp = G1PointToString(p.PiKp)
 	return s
 }
 func ProofFromString(s ProofString) (snark.Proof, error) {
 	var p snark.Proof
 	var err error
 
-	p.PiA, err = String3ToBigInt(s.PiA)
+	p.PiA, err = String3ToG1Point(snark.Utils.Bn.G1, s.PiA)
 	if err != nil {
 		return p, err
 	}
-	p.PiAp, err = String3ToBigInt(s.PiAp)
+	p.PiAp, err = String3ToG1Point(snark.Utils.Bn.G1, s.PiAp)
 	if err != nil {
 		return p, err
 	}
-	p.PiB, err = String32ToBigInt(s.PiB)
+	p.PiB, err = String32ToG2Point(snark.Utils.Bn.G2, s.PiB)
 	if err != nil {
 		return p, err
 	}
-	p.PiBp, err = String3ToBigInt(s.PiBp)
+	p.PiBp, err = String3ToG1Point(snark.Utils.Bn.G1, s.PiBp)
 	if err != nil {
 		return p, err
 	}
-	p.PiC, err = String3ToBigInt(s.PiC)
+	p.PiC, err = String3ToG1Point(snark.Utils.Bn.G1, s.PiC)
 	if err != nil {
 		return p, err
 	}
-	p.PiCp, err = String3ToBigInt(s.PiCp)
+	p.PiCp, err = String3ToG1Point(snark.Utils.Bn.G1, s.PiCp)
 	if err != nil {
 		return p, err
 	}
-	p.PiH, err = String3ToBigInt(s.PiH)
+	p.PiH, err = String3ToG1Point(snark.Utils.Bn.G1, s.PiH)
 	if err != nil {
 		return p, err
 	}
-	p.PiKp, err = String3ToBigInt(s.PiKp)
+	p.PiKp, err = String3ToG1Point(snark.Utils.Bn.G1, s.PiKp)
 	if err != nil {
 		return p, err
 	}
+	if err := snark.ValidateProof(p); err != nil {
+		return p, err
+	}
 	return p, nil
 }
 
 // groth
+
+// The groth16 values hold the Name of their curve, and the points are parsed
+// in the groups of that curve. The values without a Curve are of
+// groth16.Utils.Curve.
+
+// grothCurve returns the curve with the given Name, or groth16.Utils.Curve for
+// the empty name
+func grothCurve(name string) (curve.Curve, error) {
+	if name == "" {
+		return groth16.Utils.Curve, nil
+	}
+	return curve.ByName(name)
+}
+
+// grothCurveName returns the Name of the curve c of a groth16 value
+func grothCurveName(c curve.Curve) string {
+	if c == nil {
+		return groth16.Utils.Curve.Name()
+	}
+	return c.Name()
+}
+
 type GrothPkString struct { // Proving Key
 	BACDelta [][3]string
 	Z        []string
@@ -415,6 +567,7 @@ type GrothPkString struct { // Proving Key
 		BACGamma [][3][2]string
 	}
 	PowersTauDelta [][3]string
+	Curve          string
 }
 type GrothVkString struct {
 	IC [][3]string
@@ -426,6 +579,7 @@ type GrothVkString struct {
 		Gamma [3][2]string
 		Delta [3][2]string
 	}
+	Curve string
 }
 type GrothSetupString struct {
 	Pk GrothPkString
@@ -434,118 +588,126 @@ type GrothSetupString struct {
 
 func GrothSetupToString(setup groth16.Setup) GrothSetupString {
 	var s GrothSetupString
-	s.Pk.BACDelta = Array3BigIntToString(setup.Pk.BACDelta)
+	s.Pk.BACDelta = G1PointsToString(setup.Pk.BACDelta)
 	s.Pk.Z = ArrayBigIntToString(setup.Pk.Z)
-	s.Pk.G1.Alpha = BigInt3ToString(setup.Pk.G1.Alpha)
-	s.Pk.G1.Beta = BigInt3ToString(setup.Pk.G1.Beta)
-	s.Pk.G1.Delta = BigInt3ToString(setup.Pk.G1.Delta)
-	s.Pk.G1.At = Array3BigIntToString(setup.Pk.G1.At)
-	s.Pk.G1.BACGamma = Array3BigIntToString(setup.Pk.G1.BACGamma)
-	s.Pk.G2.Beta = BigInt32ToString(setup.Pk.G2.Beta)
-	s.Pk.G2.Gamma = BigInt32ToString(setup.Pk.G2.Gamma)
-	s.Pk.G2.Delta = BigInt32ToString(setup.Pk.G2.Delta)
-	s.Pk.G2.BACGamma = Array32BigIntToString(setup.Pk.G2.BACGamma)
-	s.Pk.PowersTauDelta = Array3BigIntToString(setup.Pk.PowersTauDelta)
-	s.Vk.IC = Array3BigIntToString(setup.Vk.IC)
-	s.Vk.G1.Alpha = BigInt3ToString(setup.Vk.G1.Alpha)
-	s.Vk.G2.Beta = BigInt32ToString(setup.Vk.G2.Beta)
-	s.Vk.G2.Gamma = BigInt32ToString(setup.Vk.G2.Gamma)
-	s.Vk.G2.Delta = BigInt32ToString(setup.Vk.G2.Delta)
+	s.Pk.G1.Alpha = G1PointToString(setup.Pk.G1.Alpha)
+	s.Pk.G1.Beta = G1PointToString(setup.Pk.G1.Beta)
+	s.Pk.G1.Delta = G1PointToString(setup.Pk.G1.Delta)
+	s.Pk.G1.At = G1PointsToString(setup.Pk.G1.At)
+	s.Pk.G1.BACGamma = G1PointsToString(setup.Pk.G1.BACGamma)
+	s.Pk.G2.Beta = G2PointToString(setup.Pk.G2.Beta)
+	s.Pk.G2.Gamma = G2PointToString(setup.Pk.G2.Gamma)
+	s.Pk.G2.Delta = G2PointToString(setup.Pk.G2.Delta)
+	s.Pk.G2.BACGamma = G2PointsToString(setup.Pk.G2.BACGamma)
+	s.Pk.PowersTauDelta = G1PointsToString(setup.Pk.PowersTauDelta)
+	s.Pk.Curve = grothCurveName(setup.Pk.Curve)
+	s.Vk.IC = G1PointsToString(setup.Vk.IC)
+	s.Vk.G1.Alpha = G1PointToString(setup.Vk.G1.Alpha)
+	s.Vk.G2.Beta = G2PointToString(setup.Vk.G2.Beta)
+	s.Vk.G2.Gamma = G2PointToString(setup.Vk.G2.Gamma)
+	s.Vk.G2.Delta = G2PointToString(setup.Vk.G2.Delta)
+	s.Vk.Curve = grothCurveName(setup.Vk.Curve)
 	return s
 }
 func GrothVkFromString(s GrothVkString) (groth16.Vk, error) {
 	var vk groth16.Vk
 	var err error
-	vk.IC, err = Array3StringToBigInt(s.IC)
+	vk.Curve, err = grothCurve(s.Curve)
 	if err != nil {
 		return vk, err
 	}
-	vk.G1.Alpha, err = String3ToBigInt(s.G1.Alpha)
+	g1, g2 := vk.Curve.G1(), vk.Curve.G2()
+	vk.IC, err = Array3StringToG1Points(g1, s.IC)
 	if err != nil {
 		return vk, err
 	}
-	vk.G2.Beta, err = String32ToBigInt(s.G2.Beta)
+	vk.G1.Alpha, err = String3ToG1Point(g1, s.G1.Alpha)
 	if err != nil {
 		return vk, err
 	}
-	vk.G2.Gamma, err = String32ToBigInt(s.G2.Gamma)
+	vk.G2.Beta, err = String32ToG2Point(g2, s.G2.Beta)
 	if err != nil {
 		return vk, err
 	}
-	vk.G2.Delta, err = String32ToBigInt(s.G2.Delta)
+	vk.G2.Gamma, err = String32ToG2Point(g2, s.G2.Gamma)
 	if err != nil {
 		return vk, err
 	}
-	return vk, nil
-}
-func GrothSetupFromString(s GrothSetupString) (groth16.Setup, error) {
-	var o groth16.Setup
-	var err error
-	o.Pk.BACDelta, err = Array3StringToBigInt(s.Pk.BACDelta)
+	vk.G2.Delta, err = String32ToG2Point(g2, s.G2.Delta)
 	if err != nil {
-		return o, err
+		return vk, err
 	}
-	o.Pk.Z, err = ArrayStringToBigInt(s.Pk.Z)
-	if err != nil {
-		return o, err
+	if err := groth16.ValidateVk(vk); err != nil {
+		return vk, err
 	}
-	o.Pk.G1.Alpha, err = String3ToBigInt(s.Pk.G1.Alpha)
+	return vk, nil
+}
+func grothPkFromString(s GrothPkString) (groth16.Pk, error) {
+	var pk groth16.Pk
+	var err error
+	pk.Curve, err = grothCurve(s.Curve)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G1.Beta, err = String3ToBigInt(s.Pk.G1.Beta)
+	g1, g2 := pk.Curve.G1(), pk.Curve.G2()
+	pk.BACDelta, err = Array3StringToG1Points(g1, s.BACDelta)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G1.Delta, err = String3ToBigInt(s.Pk.G1.Delta)
+	pk.Z, err = ArrayStringToBigInt(s.Z)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G1.At, err = Array3StringToBigInt(s.Pk.G1.At)
+	pk.G1.Alpha, err = String3ToG1Point(g1, s.G1.Alpha)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G1.BACGamma, err = Array3StringToBigInt(s.Pk.G1.BACGamma)
+	pk.G1.Beta, err = String3ToG1Point(g1, s.G1.Beta)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G2.Beta, err = String32ToBigInt(s.Pk.G2.Beta)
+	pk.G1.Delta, err = String3ToG1Point(g1, s.G1.Delta)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G2.Gamma, err = String32ToBigInt(s.Pk.G2.Gamma)
+	pk.G1.At, err = Array3StringToG1Points(g1, s.G1.At)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G2.Delta, err = String32ToBigInt(s.Pk.G2.Delta)
+	pk.G1.BACGamma, err = Array3StringToG1Points(g1, s.G1.BACGamma)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G2.BACGamma, err = Array32StringToBigInt(s.Pk.G2.BACGamma)
+	pk.G2.Beta, err = String32ToG2Point(g2, s.G2.Beta)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.PowersTauDelta, err = Array3StringToBigInt(s.Pk.PowersTauDelta)
+	pk.G2.Gamma, err = String32ToG2Point(g2, s.G2.Gamma)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Vk.IC, err = Array3StringToBigInt(s.Vk.IC)
+	pk.G2.Delta, err = String32ToG2Point(g2, s.G2.Delta)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Vk.G1.Alpha, err = String3ToBigInt(s.Vk.G1.Alpha)
+	pk.G2.BACGamma, err = Array32StringToG2Points(g2, s.G2.BACGamma)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Vk.G2.Beta, err = String32ToBigInt(s.Vk.G2.Beta)
+	pk.PowersTauDelta, err = Array3StringToG1Points(g1, s.PowersTauDelta)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Vk.G2.Gamma, err = String32ToBigInt(s.Vk.G2.Gamma)
+	return pk, nil
+}
+func GrothSetupFromString(s GrothSetupString) (groth16.Setup, error) {
+	var o groth16.Setup
+	var err error
+	o.Pk, err = grothPkFromString(s.Pk)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.G2.Delta, err = String32ToBigInt(s.Vk.G2.Delta)
+	o.Vk, err = GrothVkFromString(s.Vk)
 	if err != nil {
 		return o, err
 	}
@@ -553,33 +715,42 @@ func GrothSetupFromString(s GrothSetupString) (groth16.Setup, error) {
 }
 
 type GrothProofString struct {
-	PiA [3]string
-	PiB [3][2]string
-	PiC [3]string
+	PiA   [3]string
+	PiB   [3][2]string
+	PiC   [3]string
+	Curve string
 }
 
 func GrothProofToString(p groth16.Proof) GrothProofString {
 	var s GrothProofString
-	s.PiA = BigInt3ToString(p.PiA)
-	s.PiB = BigInt32ToString(p.PiB)
-	s.PiC = BigInt3ToString(p.PiC)
+	s.PiA = G1PointToString(p.PiA)
+	s.PiB = G2PointToString(p.PiB)
+	s.PiC = G1PointToString(p.PiC)
+	s.Curve = grothCurveName(p.Curve)
 	return s
 }
 func GrothProofFromString(s GrothProofString) (groth16.Proof, error) {
 	var p groth16.Proof
 	var err error
 
-	p.PiA, err = String3ToBigInt(s.PiA)
+	p.Curve, err = grothCurve(s.Curve)
 	if err != nil {
 		return p, err
 	}
-	p.PiB, err = String32ToBigInt(s.PiB)
+	p.PiA, err = String3ToG1Point(p.Curve.G1(), s.PiA)
 	if err != nil {
 		return p, err
 	}
-	p.PiC, err = String3ToBigInt(s.PiC)
+	p.PiB, err = String32ToG2Point(p.Curve.G2(), s.PiB)
 	if err != nil {
 		return p, err
 	}
+	p.PiC, err = String3ToG1Point(p.Curve.G1(), s.PiC)
+	if err != nil {
+		return p, err
+	}
+	if err := groth16.ValidateProof(p); err != nil {
+		return p, err
+	}
 	return p, nil
 }
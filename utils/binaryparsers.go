@@ -0,0 +1,460 @@
+package utils
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	snark "github.com/arnaucube/go-snark"
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/curve"
+	"github.com/arnaucube/go-snark/fields"
+	"github.com/arnaucube/go-snark/groth16"
+)
+
+// The binary format concatenates the canonical encodings of the values: the
+// field elements in the bytes of the field (32 for the BN128), and the affine
+// G1 and G2 points in 2 and 4 field elements (see bn128.G1.MarshalBinary). The
+// arrays are prefixed by their length, as a 4 bytes big-endian integer. The
+// groth16 Setup and Vk start with the curve.ID of their curve, and the groth16
+// proofs are decoded over the curve given to the decoder.
+
+type binaryWriter struct {
+	group1 bn128.G1
+	group2 bn128.G2
+	fqR    fields.Fq
+	buf    []byte
+}
+
+// newGrothWriter returns a binaryWriter over the curve c of a groth16 value,
+// groth16.Utils.Curve if c is nil
+func newGrothWriter(c curve.Curve) *binaryWriter {
+	if c == nil {
+		c = groth16.Utils.Curve
+	}
+	return &binaryWriter{group1: c.G1(), group2: c.G2(), fqR: c.Fr()}
+}
+
+// curve writes the curve.ID of the curve c
+func (w *binaryWriter) curve(c curve.Curve) {
+	if c == nil {
+		c = groth16.Utils.Curve
+	}
+	id, err := curve.ID(c)
+	if err != nil {
+		panic(err)
+	}
+	w.buf = append(w.buf, id)
+}
+
+func (w *binaryWriter) length(n int) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(n))
+	w.buf = append(w.buf, b[:]...)
+}
+
+func (w *binaryWriter) fr(a *big.Int) {
+	w.buf = append(w.buf, w.fqR.MarshalBinary(a)...)
+}
+
+func (w *binaryWriter) frArray(a []*big.Int) {
+	w.length(len(a))
+	for i := 0; i < len(a); i++ {
+		w.fr(a[i])
+	}
+}
+
+func (w *binaryWriter) g1(p bn128.G1Point) {
+	w.buf = append(w.buf, w.group1.MarshalBinary(p.Array())...)
+}
+
+func (w *binaryWriter) g1Array(ps []bn128.G1Point) {
+	w.length(len(ps))
+	affine := w.group1.BatchAffine(bn128.G1Arrays(ps))
+	for i := 0; i < len(affine); i++ {
+		w.buf = append(w.buf, w.group1.MarshalBinary(affine[i])...)
+	}
+}
+
+func (w *binaryWriter) g2(p bn128.G2Point) {
+	w.buf = append(w.buf, w.group2.MarshalBinary(p.Array())...)
+}
+
+func (w *binaryWriter) g2Array(ps []bn128.G2Point) {
+	w.length(len(ps))
+	affine := w.group2.BatchAffine(bn128.G2Arrays(ps))
+	for i := 0; i < len(affine); i++ {
+		w.buf = append(w.buf, w.group2.MarshalBinary(affine[i])...)
+	}
+}
+
+// binaryReader decodes the values written by binaryWriter. After the first
+// error the rest of the reads return zero values, and the error is returned by end()
+type binaryReader struct {
+	group1 bn128.G1
+	group2 bn128.G2
+	fqR    fields.Fq
+	buf    []byte
+	err    error
+}
+
+// newGrothReader returns a binaryReader of b over the curve c of a groth16
+// value, groth16.Utils.Curve if c is nil
+func newGrothReader(c curve.Curve, b []byte) *binaryReader {
+	if c == nil {
+		c = groth16.Utils.Curve
+	}
+	return &binaryReader{group1: c.G1(), group2: c.G2(), fqR: c.Fr(), buf: b}
+}
+
+// readGrothCurve reads the curve.ID written by binaryWriter.curve, and
+// returns a binaryReader of the rest of b over that curve
+func readGrothCurve(b []byte) (curve.Curve, *binaryReader, error) {
+	if len(b) == 0 {
+		return nil, nil, errors.New("unexpected end of binary data")
+	}
+	c, err := curve.ByID(b[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, newGrothReader(c, b[1:]), nil
+}
+
+func (r *binaryReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.buf) < n {
+		r.err = errors.New("unexpected end of binary data")
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+// length reads an array length, checking that there is data for all the elements of elemSize bytes
+func (r *binaryReader) length(elemSize int) int {
+	b := r.next(4)
+	if r.err != nil {
+		return 0
+	}
+	n := int(binary.BigEndian.Uint32(b))
+	if n > len(r.buf)/elemSize {
+		r.err = errors.New("unexpected end of binary data")
+		return 0
+	}
+	return n
+}
+
+func (r *binaryReader) fr() *big.Int {
+	b := r.next(r.fqR.ByteLen())
+	if r.err != nil {
+		return nil
+	}
+	a, err := r.fqR.UnmarshalBinary(b)
+	r.err = err
+	return a
+}
+
+func (r *binaryReader) frArray() []*big.Int {
+	n := r.length(r.fqR.ByteLen())
+	var a []*big.Int
+	for i := 0; i < n && r.err == nil; i++ {
+		a = append(a, r.fr())
+	}
+	return a
+}
+
+func (r *binaryReader) g1() bn128.G1Point {
+	b := r.next(2 * r.group1.F.ByteLen())
+	if r.err != nil {
+		return bn128.G1Point{}
+	}
+	p, err := r.group1.UnmarshalBinary(b)
+	r.err = err
+	return r.group1.Point(p)
+}
+
+func (r *binaryReader) g1Array() []bn128.G1Point {
+	n := r.length(2 * r.group1.F.ByteLen())
+	var ps []bn128.G1Point
+	for i := 0; i < n && r.err == nil; i++ {
+		ps = append(ps, r.g1())
+	}
+	return ps
+}
+
+func (r *binaryReader) g2() bn128.G2Point {
+	b := r.next(4 * r.group2.F.F.ByteLen())
+	if r.err != nil {
+		return bn128.G2Point{}
+	}
+	p, err := r.group2.UnmarshalBinary(b)
+	r.err = err
+	return r.group2.Point(p)
+}
+
+func (r *binaryReader) g2Array() []bn128.G2Point {
+	n := r.length(4 * r.group2.F.F.ByteLen())
+	var ps []bn128.G2Point
+	for i := 0; i < n && r.err == nil; i++ {
+		ps = append(ps, r.g2())
+	}
+	return ps
+}
+
+// end returns the first error of the reads, or an error if there is data left
+func (r *binaryReader) end() error {
+	if r.err == nil && len(r.buf) != 0 {
+		return errors.New("unexpected data at the end of binary data")
+	}
+	return r.err
+}
+
+func snarkPkToBinary(w *binaryWriter, pk snark.Pk) {
+	w.g1Array(pk.G1T)
+	w.g1Array(pk.A)
+	w.g2Array(pk.B)
+	w.g1Array(pk.C)
+	w.g1Array(pk.Kp)
+	w.g1Array(pk.Ap)
+	w.g1Array(pk.Bp)
+	w.g1Array(pk.Cp)
+	w.frArray(pk.Z)
+}
+
+func snarkVkToBinary(w *binaryWriter, vk snark.Vk) {
+	w.g2(vk.Vka)
+	w.g1(vk.Vkb)
+	w.g2(vk.Vkc)
+	w.g1Array(vk.IC)
+	w.g1(vk.G1Kbg)
+	w.g2(vk.G2Kbg)
+	w.g2(vk.G2Kg)
+	w.g2(vk.Vkz)
+}
+
+// SetupToBinary returns the binary encoding of the public part (Pk and Vk) of the Setup
+func SetupToBinary(setup snark.Setup) []byte {
+	w := &binaryWriter{group1: snark.Utils.Bn.G1, group2: snark.Utils.Bn.G2, fqR: snark.Utils.FqR}
+	snarkPkToBinary(w, setup.Pk)
+	snarkVkToBinary(w, setup.Vk)
+	return w.buf
+}
+
+// SetupFromBinary decodes a Setup encoded with SetupToBinary
+func SetupFromBinary(b []byte) (snark.Setup, error) {
+	var setup snark.Setup
+	r := &binaryReader{group1: snark.Utils.Bn.G1, group2: snark.Utils.Bn.G2, fqR: snark.Utils.FqR, buf: b}
+	setup.Pk.G1T = r.g1Array()
+	setup.Pk.A = r.g1Array()
+	setup.Pk.B = r.g2Array()
+	setup.Pk.C = r.g1Array()
+	setup.Pk.Kp = r.g1Array()
+	setup.Pk.Ap = r.g1Array()
+	setup.Pk.Bp = r.g1Array()
+	setup.Pk.Cp = r.g1Array()
+	setup.Pk.Z = r.frArray()
+	setup.Vk.Vka = r.g2()
+	setup.Vk.Vkb = r.g1()
+	setup.Vk.Vkc = r.g2()
+	setup.Vk.IC = r.g1Array()
+	setup.Vk.G1Kbg = r.g1()
+	setup.Vk.G2Kbg = r.g2()
+	setup.Vk.G2Kg = r.g2()
+	setup.Vk.Vkz = r.g2()
+	if err := r.end(); err != nil {
+		return snark.Setup{}, err
+	}
+	return setup, nil
+}
+
+// ProofToBinary returns the binary encoding of the Proof
+func ProofToBinary(p snark.Proof) []byte {
+	w := &binaryWriter{group1: snark.Utils.Bn.G1, group2: snark.Utils.Bn.G2, fqR: snark.Utils.FqR}
+	w.g1(p.PiA)
+	w.g1(p.PiAp)
+	w.g2(p.PiB)
+	w.g1(p.PiBp)
+	w.g1(p.PiC)
+	w.g1(p.PiCp)
+	w.g1(p.PiH)
+	w.g1(p.PiKp)
+	return w.buf
+}
+
+// ProofFromBinary decodes a Proof encoded with ProofToBinary
+func ProofFromBinary(b []byte) (snark.Proof, error) {
+	var p snark.Proof
+	r := &binaryReader{group1: snark.Utils.Bn.G1, group2: snark.Utils.Bn.G2, fqR: snark.Utils.FqR, buf: b}
+	p.PiA = r.g1()
+	p.PiAp = r.g1()
+	p.PiB = r.g2()
+	p.PiBp = r.g1()
+	p.PiC = r.g1()
+	p.PiCp = r.g1()
+	p.PiH = r.g1()
+	p.PiKp = r.g1()
+	if err := r.end(); err != nil {
+		return snark.Proof{}, err
+	}
+	return p, nil
+}
+
+// groth
+
+func grothPkToBinary(w *binaryWriter, pk groth16.Pk) {
+	w.g1Array(pk.BACDelta)
+	w.frArray(pk.Z)
+	w.g1(pk.G1.Alpha)
+	w.g1(pk.G1.Beta)
+	w.g1(pk.G1.Delta)
+	w.g1Array(pk.G1.At)
+	w.g1Array(pk.G1.BACGamma)
+	w.g2(pk.G2.Beta)
+	w.g2(pk.G2.Gamma)
+	w.g2(pk.G2.Delta)
+	w.g2Array(pk.G2.BACGamma)
+	w.g1Array(pk.PowersTauDelta)
+}
+
+func grothVkToBinary(w *binaryWriter, vk groth16.Vk) {
+	w.g1Array(vk.IC)
+	w.g1(vk.G1.Alpha)
+	w.g2(vk.G2.Beta)
+	w.g2(vk.G2.Gamma)
+	w.g2(vk.G2.Delta)
+}
+
+func grothVkFromBinary(r *binaryReader) groth16.Vk {
+	var vk groth16.Vk
+	vk.IC = r.g1Array()
+	vk.G1.Alpha = r.g1()
+	vk.G2.Beta = r.g2()
+	vk.G2.Gamma = r.g2()
+	vk.G2.Delta = r.g2()
+	return vk
+}
+
+// GrothSetupToBinary returns the binary encoding of the public part (Pk and
+// Vk) of the groth16 Setup, after the curve.ID of the curve of the Pk
+func GrothSetupToBinary(setup groth16.Setup) []byte {
+	w := newGrothWriter(setup.Pk.Curve)
+	w.curve(setup.Pk.Curve)
+	grothPkToBinary(w, setup.Pk)
+	grothVkToBinary(w, setup.Vk)
+	return w.buf
+}
+
+// GrothSetupFromBinary decodes a groth16 Setup encoded with GrothSetupToBinary
+func GrothSetupFromBinary(b []byte) (groth16.Setup, error) {
+	var setup groth16.Setup
+	c, r, err := readGrothCurve(b)
+	if err != nil {
+		return groth16.Setup{}, err
+	}
+	setup.Pk.BACDelta = r.g1Array()
+	setup.Pk.Z = r.frArray()
+	setup.Pk.G1.Alpha = r.g1()
+	setup.Pk.G1.Beta = r.g1()
+	setup.Pk.G1.Delta = r.g1()
+	setup.Pk.G1.At = r.g1Array()
+	setup.Pk.G1.BACGamma = r.g1Array()
+	setup.Pk.G2.Beta = r.g2()
+	setup.Pk.G2.Gamma = r.g2()
+	setup.Pk.G2.Delta = r.g2()
+	setup.Pk.G2.BACGamma = r.g2Array()
+	setup.Pk.PowersTauDelta = r.g1Array()
+	setup.Vk = grothVkFromBinary(r)
+	if err := r.end(); err != nil {
+		return groth16.Setup{}, err
+	}
+	setup.Pk.Curve = c
+	setup.Vk.Curve = c
+	return setup, nil
+}
+
+// GrothVkToBinary returns the binary encoding of the groth16 Vk, after the
+// curve.ID of its curve
+func GrothVkToBinary(vk groth16.Vk) []byte {
+	w := newGrothWriter(vk.Curve)
+	w.curve(vk.Curve)
+	grothVkToBinary(w, vk)
+	return w.buf
+}
+
+// GrothVkFromBinary decodes a groth16 Vk encoded with GrothVkToBinary
+func GrothVkFromBinary(b []byte) (groth16.Vk, error) {
+	c, r, err := readGrothCurve(b)
+	if err != nil {
+		return groth16.Vk{}, err
+	}
+	vk := grothVkFromBinary(r)
+	if err := r.end(); err != nil {
+		return groth16.Vk{}, err
+	}
+	vk.Curve = c
+	return vk, nil
+}
+
+// GrothProofToBinary returns the binary encoding of the groth16 Proof: PiA, PiB
+// and PiC, 256 bytes over the BN128. The curve is not encoded, it is the one
+// of the Vk that verifies the proof
+func GrothProofToBinary(p groth16.Proof) []byte {
+	w := newGrothWriter(p.Curve)
+	w.g1(p.PiA)
+	w.g2(p.PiB)
+	w.g1(p.PiC)
+	return w.buf
+}
+
+// GrothProofFromBinary decodes a groth16 Proof over the curve c (nil is
+// groth16.Utils.Curve) encoded with GrothProofToBinary
+func GrothProofFromBinary(c curve.Curve, b []byte) (groth16.Proof, error) {
+	var p groth16.Proof
+	r := newGrothReader(c, b)
+	p.PiA = r.g1()
+	p.PiB = r.g2()
+	p.PiC = r.g1()
+	if err := r.end(); err != nil {
+		return groth16.Proof{}, err
+	}
+	p.Curve = c
+	return p, nil
+}
+
+// GrothProofToCompressed returns the compressed encoding of the groth16 Proof:
+// PiA, PiB and PiC compressed with bn128.G1.Compress and bn128.G2.Compress, in
+// 1, 2 and 1 field elements (128 bytes over the BN128)
+func GrothProofToCompressed(p groth16.Proof) []byte {
+	w := newGrothWriter(p.Curve)
+	var b []byte
+	b = append(b, w.group1.Compress(p.PiA.Array())...)
+	b = append(b, w.group2.Compress(p.PiB.Array())...)
+	b = append(b, w.group1.Compress(p.PiC.Array())...)
+	return b
+}
+
+// GrothProofFromCompressed decodes a groth16 Proof over the curve c (nil is
+// groth16.Utils.Curve) encoded with GrothProofToCompressed
+func GrothProofFromCompressed(c curve.Curve, b []byte) (groth16.Proof, error) {
+	r := newGrothReader(c, nil)
+	n := r.group1.F.ByteLen()
+	if len(b) != 4*n {
+		return groth16.Proof{}, errors.New("invalid compressed proof length")
+	}
+	piA, err := r.group1.Decompress(b[:n])
+	if err != nil {
+		return groth16.Proof{}, err
+	}
+	piB, err := r.group2.Decompress(b[n : 3*n])
+	if err != nil {
+		return groth16.Proof{}, err
+	}
+	piC, err := r.group1.Decompress(b[3*n:])
+	if err != nil {
+		return groth16.Proof{}, err
+	}
+	return groth16.Proof{PiA: r.group1.Point(piA), PiB: r.group2.Point(piB), PiC: r.group1.Point(piC), Curve: c}, nil
+}
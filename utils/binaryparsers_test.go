@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	mrand "math/rand"
+	"strings"
+	"testing"
+
+	snark "github.com/arnaucube/go-snark"
+	"github.com/arnaucube/go-snark/bn128"
+	"github.com/arnaucube/go-snark/circuitcompiler"
+	"github.com/arnaucube/go-snark/curve"
+	"github.com/arnaucube/go-snark/groth16"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinaryParsers(t *testing.T) {
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	publicSignals := []*big.Int{big.NewInt(int64(35))}
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, publicSignals)
+	assert.Nil(t, err)
+	circuit.GenerateSparseR1CS()
+
+	// the sparse R1CS in the string and hex encodings of the circuit
+	circuitS, err := CircuitFromString(CircuitToString(*circuit))
+	assert.Nil(t, err)
+	assert.Equal(t, circuit.R1CS, circuitS.R1CS)
+	circuitH, err := CircuitFromHex(CircuitToHex(*circuit))
+	assert.Nil(t, err)
+	assert.Equal(t, circuit.R1CS, circuitH.R1CS)
+	cs := CircuitToString(*circuit)
+	cs.R1CS.A[0][0].Signal = cs.R1CS.NSignals
+	_, err = CircuitFromString(cs)
+	assert.NotNil(t, err)
+
+	// groth16
+	setup, err := groth16.GenerateTrustedSetup(len(w), *circuit)
+	assert.Nil(t, err)
+	setup2, err := GrothSetupFromBinary(GrothSetupToBinary(setup))
+	assert.Nil(t, err)
+	// the arrays are already in affine coordinates
+	assert.Equal(t, setup.Pk.G1.At, setup2.Pk.G1.At)
+	assert.Equal(t, setup.Pk.BACDelta, setup2.Pk.BACDelta)
+	assert.Equal(t, setup.Pk.Z, setup2.Pk.Z)
+	assert.Equal(t, GrothSetupToBinary(setup), GrothSetupToBinary(setup2))
+
+	vk, err := GrothVkFromBinary(GrothVkToBinary(setup.Vk))
+	assert.Nil(t, err)
+	proof, err := groth16.GenerateProofs(*circuit, setup2.Pk, w)
+	assert.Nil(t, err)
+	proofBytes := GrothProofToBinary(proof)
+	assert.Equal(t, 256, len(proofBytes))
+	proof2, err := GrothProofFromBinary(vk.Curve, proofBytes)
+	assert.Nil(t, err)
+	verified, err := groth16.VerifyProof(vk, proof2, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+
+	compressed := GrothProofToCompressed(proof)
+	assert.Equal(t, 128, len(compressed))
+	proof3, err := GrothProofFromCompressed(vk.Curve, compressed)
+	assert.Nil(t, err)
+	assert.Equal(t, proofBytes, GrothProofToBinary(proof3))
+	verified, err = groth16.VerifyProof(vk, proof3, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+	_, err = GrothProofFromCompressed(vk.Curve, compressed[:127])
+	assert.NotNil(t, err)
+
+	// truncated and extended data is rejected
+	_, err = GrothProofFromBinary(vk.Curve, proofBytes[:255])
+	assert.NotNil(t, err)
+	_, err = GrothProofFromBinary(vk.Curve, append(proofBytes, 0))
+	assert.NotNil(t, err)
+	setupBytes := GrothSetupToBinary(setup)
+	assert.Equal(t, curve.IDBN128, setupBytes[0])
+	_, err = GrothSetupFromBinary(setupBytes[:len(setupBytes)-1])
+	assert.NotNil(t, err)
+	// non canonical coordinate
+	nonCanonical := append([]byte{}, proofBytes...)
+	copy(nonCanonical[:32], groth16.Utils.Bn.Q.Bytes())
+	_, err = GrothProofFromBinary(vk.Curve, nonCanonical)
+	assert.NotNil(t, err)
+	// points not on the curve are rejected by all the decoders
+	offCurve := append([]byte{}, proofBytes...)
+	offCurve[63] ^= 1
+	_, err = GrothProofFromBinary(vk.Curve, offCurve)
+	assert.True(t, errors.Is(err, bn128.ErrNotOnCurve))
+	proofStr := GrothProofToString(proof)
+	proofStr.PiC[1] = "3"
+	_, err = GrothProofFromString(proofStr)
+	assert.True(t, errors.Is(err, bn128.ErrNotOnCurve))
+	vkStr := GrothSetupToString(setup).Vk
+	vkStr.G2.Beta[0][0] = "1"
+	_, err = GrothVkFromString(vkStr)
+	var invalid *bn128.InvalidPointError
+	assert.True(t, errors.As(err, &invalid))
+	assert.Equal(t, "G2", invalid.Group)
+	// the point at infinity only has the canonical encoding
+	proofStr = GrothProofToString(proof)
+	proofStr.PiC = [3]string{"1", "2", "0"}
+	_, err = GrothProofFromString(proofStr)
+	assert.True(t, errors.Is(err, bn128.ErrNonCanonicalInfinity))
+	proofHex := GrothProofToHex(proof)
+	proofHex.PiB = [3][2]string{{"0", "0"}, {"0", "0"}, {"0", "0"}}
+	_, err = GrothProofFromHex(proofHex)
+	assert.True(t, errors.Is(err, bn128.ErrNonCanonicalInfinity))
+
+	// snark
+	snarkSetup, err := snark.GenerateTrustedSetup(len(w), *circuit)
+	assert.Nil(t, err)
+	snarkSetup2, err := SetupFromBinary(SetupToBinary(snarkSetup))
+	assert.Nil(t, err)
+	assert.Equal(t, SetupToBinary(snarkSetup), SetupToBinary(snarkSetup2))
+	snarkProof, err := snark.GenerateProofs(*circuit, snarkSetup2.Pk, w)
+	assert.Nil(t, err)
+	snarkProof2, err := ProofFromBinary(ProofToBinary(snarkProof))
+	assert.Nil(t, err)
+	verified, err = snark.VerifyProof(snarkSetup2.Vk, snarkProof2, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+}
+
+func TestGrothParsersBLS12381(t *testing.T) {
+	bls, err := curve.NewBLS12381()
+	assert.Nil(t, err)
+	code := `
+	func main(private s0, public s1):
+		s2 = s0 * s0
+		s3 = s2 * s0
+		s4 = s3 + s0
+		s5 = s4 + 5
+		equals(s1, s5)
+		out = 1 * 1
+	`
+	parser := circuitcompiler.NewParser(strings.NewReader(code))
+	circuit, err := parser.Parse()
+	assert.Nil(t, err)
+	publicSignals := []*big.Int{big.NewInt(int64(35))}
+	w, err := circuit.CalculateWitness([]*big.Int{big.NewInt(int64(3))}, publicSignals)
+	assert.Nil(t, err)
+	circuit.GenerateSparseR1CS()
+	setup, err := groth16.GenerateTrustedSetupWithCurve(bls, mrand.New(mrand.NewSource(1)), len(w), *circuit)
+	assert.Nil(t, err)
+	proof, err := groth16.GenerateProofsWithReader(mrand.New(mrand.NewSource(2)), *circuit, setup.Pk, w)
+	assert.Nil(t, err)
+
+	// the binary Setup and Vk start with the curve identifier
+	setupBytes := GrothSetupToBinary(setup)
+	assert.Equal(t, curve.IDBLS12381, setupBytes[0])
+	setup2, err := GrothSetupFromBinary(setupBytes)
+	assert.Nil(t, err)
+	assert.Equal(t, "bls12381", setup2.Pk.Curve.Name())
+	assert.Equal(t, setupBytes, GrothSetupToBinary(setup2))
+	vk, err := GrothVkFromBinary(GrothVkToBinary(setup.Vk))
+	assert.Nil(t, err)
+	assert.Equal(t, "bls12381", vk.Curve.Name())
+	_, err = GrothVkFromBinary(append([]byte{0}, GrothVkToBinary(setup.Vk)[1:]...))
+	assert.NotNil(t, err)
+
+	// the proofs are decoded over the curve of the Vk
+	proofBytes := GrothProofToBinary(proof)
+	assert.Equal(t, 2*48+4*48+2*48, len(proofBytes))
+	proof2, err := GrothProofFromBinary(vk.Curve, proofBytes)
+	assert.Nil(t, err)
+	verified, err := groth16.VerifyProof(vk, proof2, publicSignals, false)
+	assert.Nil(t, err)
+	assert.True(t, verified)
+	_, err = GrothProofFromBinary(nil, proofBytes)
+	assert.NotNil(t, err)
+	compressed := GrothProofToCompressed(proof)
+	assert.Equal(t, 4*48, len(compressed))
+	proof3, err := GrothProofFromCompressed(vk.Curve, compressed)
+	assert.Nil(t, err)
+	assert.Equal(t, proofBytes, GrothProofToBinary(proof3))
+
+	// the text encodings hold the curve Name
+	setupStr := GrothSetupToString(setup)
+	assert.Equal(t, "bls12381", setupStr.Vk.Curve)
+	setup3, err := GrothSetupFromString(setupStr)
+	assert.Nil(t, err)
+	setup4, err := GrothSetupFromHex(GrothSetupToHex(setup))
+	assert.Nil(t, err)
+	assert.Equal(t, setupBytes, GrothSetupToBinary(setup3))
+	assert.Equal(t, setupBytes, GrothSetupToBinary(setup4))
+	proof4, err := GrothProofFromString(GrothProofToString(proof))
+	assert.Nil(t, err)
+	proof5, err := GrothProofFromHex(GrothProofToHex(proof))
+	assert.Nil(t, err)
+	for _, p := range []groth16.Proof{proof4, proof5} {
+		verified, err = groth16.VerifyProof(setup3.Vk, p, publicSignals, false)
+		assert.Nil(t, err)
+		assert.True(t, verified)
+	}
+	proofStr := GrothProofToString(proof)
+	proofStr.Curve = ""
+	_, err = GrothProofFromString(proofStr)
+	assert.NotNil(t, err)
+}
@@ -6,8 +6,10 @@ import (
 	"math/big"
 
 	snark "github.com/arnaucube/go-snark"
+	"github.com/arnaucube/go-snark/bn128"
 	"github.com/arnaucube/go-snark/circuitcompiler"
 	"github.com/arnaucube/go-snark/groth16"
+	"github.com/arnaucube/go-snark/r1csqap"
 )
 
 // []*big.Int
@@ -132,6 +134,86 @@ func Array32BigIntToHex(b [][3][2]*big.Int) [][3][2]string {
 	return o
 }
 
+// bn128.G1Point
+func Hex3ToG1Point(g1 bn128.G1, s [3]string) (bn128.G1Point, error) {
+	p, err := Hex3ToBigInt(s)
+	if err != nil {
+		return bn128.G1Point{}, err
+	}
+	if err := g1.CheckInfinity(p); err != nil {
+		return bn128.G1Point{}, err
+	}
+	return g1.Point(p), nil
+}
+
+// G1PointToHex encodes the point in affine coordinates, [0, 0, 0] for the
+// point at infinity
+func G1PointToHex(p bn128.G1Point) [3]string {
+	return BigInt3ToHex(p.Affine().Array())
+}
+
+// []bn128.G1Point
+func Array3HexToG1Points(g1 bn128.G1, s [][3]string) ([]bn128.G1Point, error) {
+	ps, err := Array3HexToBigInt(s)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(ps); i++ {
+		if err := g1.CheckInfinity(ps[i]); err != nil {
+			return nil, err
+		}
+	}
+	return g1.Points(ps), nil
+}
+
+// G1PointsToHex encodes the points in affine coordinates
+func G1PointsToHex(ps []bn128.G1Point) [][3]string {
+	if len(ps) == 0 {
+		return nil
+	}
+	return Array3BigIntToHex(ps[0].Group().BatchAffine(bn128.G1Arrays(ps)))
+}
+
+// bn128.G2Point
+func Hex32ToG2Point(g2 bn128.G2, s [3][2]string) (bn128.G2Point, error) {
+	p, err := Hex32ToBigInt(s)
+	if err != nil {
+		return bn128.G2Point{}, err
+	}
+	if err := g2.CheckInfinity(p); err != nil {
+		return bn128.G2Point{}, err
+	}
+	return g2.Point(p), nil
+}
+
+// G2PointToHex encodes the point in affine coordinates, G2.Zero() for the
+// point at infinity
+func G2PointToHex(p bn128.G2Point) [3][2]string {
+	return BigInt32ToHex(p.Affine().Array())
+}
+
+// []bn128.G2Point
+func Array32HexToG2Points(g2 bn128.G2, s [][3][2]string) ([]bn128.G2Point, error) {
+	ps, err := Array32HexToBigInt(s)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(ps); i++ {
+		if err := g2.CheckInfinity(ps[i]); err != nil {
+			return nil, err
+		}
+	}
+	return g2.Points(ps), nil
+}
+
+// G2PointsToHex encodes the points in affine coordinates
+func G2PointsToHex(ps []bn128.G2Point) [][3][2]string {
+	if len(ps) == 0 {
+		return nil
+	}
+	return Array32BigIntToHex(ps[0].Group().BatchAffine(bn128.G2Arrays(ps)))
+}
+
 // Setup
 type PkHex struct {
 	G1T [][3]string
@@ -161,57 +243,57 @@ type SetupHex struct {
 
 func SetupToHex(setup snark.Setup) SetupHex {
 	var s SetupHex
-	s.Pk.G1T = Array3BigIntToHex(setup.Pk.G1T)
-	s.Pk.A = Array3BigIntToHex(setup.Pk.A)
-	s.Pk.B = Array32BigIntToHex(setup.Pk.B)
-	s.Pk.C = Array3BigIntToHex(setup.Pk.C)
-	s.Pk.Kp = Array3BigIntToHex(setup.Pk.Kp)
-	s.Pk.Ap = Array3BigIntToHex(setup.Pk.Ap)
-	s.Pk.Bp = Array3BigIntToHex(setup.Pk.Bp)
-	s.Pk.Cp = Array3BigIntToHex(setup.Pk.Cp)
+	s.Pk.G1T = G1PointsToHex(setup.Pk.G1T)
+	s.Pk.A = G1PointsToHex(setup.Pk.A)
+	s.Pk.B = G2PointsToHex(setup.Pk.B)
+	s.Pk.C = G1PointsToHex(setup.Pk.C)
+	s.Pk.Kp = G1PointsToHex(setup.Pk.Kp)
+	s.Pk.Ap = G1PointsToHex(setup.Pk.Ap)
+	s.Pk.Bp = G1PointsToHex(setup.Pk.Bp)
+	s.Pk.Cp = G1PointsToHex(setup.Pk.Cp)
 	s.Pk.Z = ArrayBigIntToHex(setup.Pk.Z)
-	s.Vk.Vka = BigInt32ToHex(setup.Vk.Vka)
-	s.Vk.Vkb = BigInt3ToHex(setup.Vk.Vkb)
-	s.Vk.Vkc = BigInt32ToHex(setup.Vk.Vkc)
-	s.Vk.IC = Array3BigIntToHex(setup.Vk.IC)
-	s.Vk.G1Kbg = BigInt3ToHex(setup.Vk.G1Kbg)
-	s.Vk.G2Kbg = BigInt32ToHex(setup.Vk.G2Kbg)
-	s.Vk.G2Kg = BigInt32ToHex(setup.Vk.G2Kg)
-	s.Vk.Vkz = BigInt32ToHex(setup.Vk.Vkz)
+	s.Vk.Vka = G2PointToHex(setup.Vk.Vka)
+	s.Vk.Vkb = G1PointToHex(setup.Vk.Vkb)
+	s.Vk.Vkc = G2PointToHex(setup.Vk.Vkc)
+	s.Vk.IC = G1PointsToHex(setup.Vk.IC)
+	s.Vk.G1Kbg = G1PointToHex(setup.Vk.G1Kbg)
+	s.Vk.G2Kbg = G2PointToHex(setup.Vk.G2Kbg)
+	s.Vk.G2Kg = G2PointToHex(setup.Vk.G2Kg)
+	s.Vk.Vkz = G2PointToHex(setup.Vk.Vkz)
 	return s
 }
 func SetupFromHex(s SetupHex) (snark.Setup, error) {
 	var o snark.Setup
 	var err error
-	o.Pk.G1T, err = Array3HexToBigInt(s.Pk.G1T)
+	o.Pk.G1T, err = Array3HexToG1Points(snark.Utils.Bn.G1, s.Pk.G1T)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.A, err = Array3HexToBigInt(s.Pk.A)
+	o.Pk.A, err = Array3HexToG1Points(snark.Utils.Bn.G1, s.Pk.A)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.B, err = Array32HexToBigInt(s.Pk.B)
+	o.Pk.B, err = Array32HexToG2Points(snark.Utils.Bn.G2, s.Pk.B)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.C, err = Array3HexToBigInt(s.Pk.C)
+	o.Pk.C, err = Array3HexToG1Points(snark.Utils.Bn.G1, s.Pk.C)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.Kp, err = Array3HexToBigInt(s.Pk.Kp)
+	o.Pk.Kp, err = Array3HexToG1Points(snark.Utils.Bn.G1, s.Pk.Kp)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.Ap, err = Array3HexToBigInt(s.Pk.Ap)
+	o.Pk.Ap, err = Array3HexToG1Points(snark.Utils.Bn.G1, s.Pk.Ap)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.Bp, err = Array3HexToBigInt(s.Pk.Bp)
+	o.Pk.Bp, err = Array3HexToG1Points(snark.Utils.Bn.G1, s.Pk.Bp)
 	if err != nil {
 		return o, err
 	}
-	o.Pk.Cp, err = Array3HexToBigInt(s.Pk.Cp)
+	o.Pk.Cp, err = Array3HexToG1Points(snark.Utils.Bn.G1, s.Pk.Cp)
 	if err != nil {
 		return o, err
 	}
@@ -220,39 +302,42 @@ func SetupFromHex(s SetupHex) (snark.Setup, error) {
 		return o, err
 	}
 
-	o.Vk.Vka, err = Hex32ToBigInt(s.Vk.Vka)
+	o.Vk.Vka, err = Hex32ToG2Point(snark.Utils.Bn.G2, s.Vk.Vka)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.Vkb, err = Hex3ToBigInt(s.Vk.Vkb)
+	o.Vk.Vkb, err = Hex3ToG1Point(snark.Utils.Bn.G1, s.Vk.Vkb)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.Vkc, err = Hex32ToBigInt(s.Vk.Vkc)
+	o.Vk.Vkc, err = Hex32ToG2Point(snark.Utils.Bn.G2, s.Vk.Vkc)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.IC, err = Array3HexToBigInt(s.Vk.IC)
+	o.Vk.IC, err = Array3HexToG1Points(snark.Utils.Bn.G1, s.Vk.IC)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.G1Kbg, err = Hex3ToBigInt(s.Vk.G1Kbg)
+	o.Vk.G1Kbg, err = Hex3ToG1Point(snark.Utils.Bn.G1, s.Vk.G1Kbg)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.G2Kbg, err = Hex32ToBigInt(s.Vk.G2Kbg)
+	o.Vk.G2Kbg, err = Hex32ToG2Point(snark.Utils.Bn.G2, s.Vk.G2Kbg)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.G2Kg, err = Hex32ToBigInt(s.Vk.G2Kg)
+	o.Vk.G2Kg, err = Hex32ToG2Point(snark.Utils.Bn.G2, s.Vk.G2Kg)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.Vkz, err = Hex32ToBigInt(s.Vk.Vkz)
+	o.Vk.Vkz, err = Hex32ToG2Point(snark.Utils.Bn.G2, s.Vk.Vkz)
 	if err != nil {
 		return o, err
 	}
 
+	if err := snark.ValidateVk(o.Vk); err != nil {
+		return o, err
+	}
 	return o, nil
 
 }
@@ -268,10 +353,49 @@ type CircuitHex struct {
 	Witness       []string
 	Constraints   []circuitcompiler.Constraint
 	R1CS          struct {
-		A [][]string
-		B [][]string
-		C [][]string
+		NSignals int
+		A        [][]TermHex
+		B        [][]TermHex
+		C        [][]TermHex
+	}
+}
+
+// TermHex is the r1csqap.Term with the coefficient in base 16
+type TermHex struct {
+	Signal int
+	Coeff  string
+}
+
+// []r1csqap.LinearCombination
+func LinearCombinationsToHex(lcs []r1csqap.LinearCombination) [][]TermHex {
+	var o [][]TermHex
+	for i := 0; i < len(lcs); i++ {
+		lc := lcs[i]
+		ts := []TermHex{}
+		for j := 0; j < len(lc); j++ {
+			ts = append(ts, TermHex{lc[j].Signal, fmt.Sprintf("%x", lc[j].Coeff)})
+		}
+		o = append(o, ts)
+	}
+	return o
+}
+func HexToLinearCombinations(s [][]TermHex, nSignals int) ([]r1csqap.LinearCombination, error) {
+	var o []r1csqap.LinearCombination
+	for i := 0; i < len(s); i++ {
+		var lc r1csqap.LinearCombination
+		for j := 0; j < len(s[i]); j++ {
+			if s[i][j].Signal < 0 || s[i][j].Signal >= nSignals {
+				return nil, errors.New("R1CS term of a signal out of range")
+			}
+			coeff, ok := new(big.Int).SetString(s[i][j].Coeff, 16)
+			if !ok {
+				return nil, errors.New("error parsing R1CS coefficient from coeffHex")
+			}
+			lc = append(lc, r1csqap.Term{Signal: s[i][j].Signal, Coeff: coeff})
+		}
+		o = append(o, lc)
 	}
+	return o, nil
 }
 
 func ArrayArrayBigIntToHex(b [][]*big.Int) [][]string {
@@ -302,9 +426,10 @@ func CircuitToHex(c circuitcompiler.Circuit) CircuitHex {
 	cs.Signals = c.Signals
 	cs.Witness = ArrayBigIntToHex(c.Witness)
 	cs.Constraints = c.Constraints
-	cs.R1CS.A = ArrayArrayBigIntToHex(c.R1CS.A)
-	cs.R1CS.B = ArrayArrayBigIntToHex(c.R1CS.B)
-	cs.R1CS.C = ArrayArrayBigIntToHex(c.R1CS.C)
+	cs.R1CS.NSignals = c.R1CS.NSignals
+	cs.R1CS.A = LinearCombinationsToHex(c.R1CS.A)
+	cs.R1CS.B = LinearCombinationsToHex(c.R1CS.B)
+	cs.R1CS.C = LinearCombinationsToHex(c.R1CS.C)
 	return cs
 }
 func CircuitFromHex(cs CircuitHex) (circuitcompiler.Circuit, error) {
@@ -321,15 +446,16 @@ func CircuitFromHex(cs CircuitHex) (circuitcompiler.Circuit, error) {
 		return c, err
 	}
 	c.Constraints = cs.Constraints
-	c.R1CS.A, err = ArrayArrayHexToBigInt(cs.R1CS.A)
+	c.R1CS.NSignals = cs.R1CS.NSignals
+	c.R1CS.A, err = HexToLinearCombinations(cs.R1CS.A, cs.R1CS.NSignals)
 	if err != nil {
 		return c, err
 	}
-	c.R1CS.B, err = ArrayArrayHexToBigInt(cs.R1CS.B)
+	c.R1CS.B, err = HexToLinearCombinations(cs.R1CS.B, cs.R1CS.NSignals)
 	if err != nil {
 		return c, err
 	}
-	c.R1CS.C, err = ArrayArrayHexToBigInt(cs.R1CS.C)
+	c.R1CS.C, err = HexToLinearCombinations(cs.R1CS.C, cs.R1CS.NSignals)
 	if err != nil {
 		return c, err
 	}
@@ -350,52 +476,55 @@ type ProofHex struct {
 
 func ProofToHex(p snark.Proof) ProofHex {
 	var s ProofHex
-	s.PiA = BigInt3ToHex(p.PiA)
-	s.PiAp = BigInt3ToHex(p.PiAp)
-	s.PiB = BigInt32ToHex(p.PiB)
-	s.PiBp = BigInt3ToHex(p.PiBp)
-	s.PiC = BigInt3ToHex(p.PiC)
-	s.PiCp = BigInt3ToHex(p.PiCp)
-	s.PiH = BigInt3ToHex(p.PiH)
-	s.PiKp = BigInt3ToHex(p.PiKp)
+	s.PiA = G1PointToHex(p.PiA)
+	s.PiAp = G1PointToHex(p.PiAp)
+	s.PiB = G2PointToHex(p.PiB)
+	s.PiBp = G1PointToHex(p.PiBp)
+	s.PiC = G1PointToHex(p.PiC)
+	s.PiCp = G1PointToHex(p.PiCp)
+	s.PiH = G1PointToHex(p.PiH)
+	s.PiKp = G1PointToHex(p.PiKp)
 	return s
 }
 func ProofFromHex(s ProofHex) (snark.Proof, error) {
 	var p snark.Proof
 	var err error
 
-	p.PiA, err = Hex3ToBigInt(s.PiA)
+	p.PiA, err = Hex3ToG1Point(snark.Utils.Bn.G1, s.PiA)
 	if err != nil {
 		return p, err
 	}
-	p.PiAp, err = Hex3ToBigInt(s.PiAp)
+	p.PiAp, err = Hex3ToG1Point(snark.Utils.Bn.G1, s.PiAp)
 	if err != nil {
 		return p, err
 	}
-	p.PiB, err = Hex32ToBigInt(s.PiB)
+	p.PiB, err = Hex32ToG2Point(snark.Utils.Bn.G2, s.PiB)
 	if err != nil {
 		return p, err
 	}
-	p.PiBp, err = Hex3ToBigInt(s.PiBp)
+	p.PiBp, err = Hex3ToG1Point(snark.Utils.Bn.G1, s.PiBp)
 	if err != nil {
 		return p, err
 	}
-	p.PiC, err = Hex3ToBigInt(s.PiC)
+	p.PiC, err = Hex3ToG1Point(snark.Utils.Bn.G1, s.PiC)
 	if err != nil {
 		return p, err
 	}
-	p.PiCp, err = Hex3ToBigInt(s.PiCp)
+	p.PiCp, err = Hex3ToG1Point(snark.Utils.Bn.G1, s.PiCp)
 	if err != nil {
 		return p, err
 	}
-	p.PiH, err = Hex3ToBigInt(s.PiH)
+	p.PiH, err = Hex3ToG1Point(snark.Utils.Bn.G1, s.PiH)
 	if err != nil {
 		return p, err
 	}
-	p.PiKp, err = Hex3ToBigInt(s.PiKp)
+	p.PiKp, err = Hex3ToG1Point(snark.Utils.Bn.G1, s.PiKp)
 	if err != nil {
 		return p, err
 	}
+	if err := snark.ValidateProof(p); err != nil {
+		return p, err
+	}
 	return p, nil
 }
 
@@ -417,6 +546,7 @@ type GrothPkHex struct { // Proving Key
 		BACGamma [][3][2]string
 	}
 	PowersTauDelta [][3]string
+	Curve          string
 }
 type GrothVkHex struct {
 	IC [][3]string
@@ -428,6 +558,7 @@ type GrothVkHex struct {
 		Gamma [3][2]string
 		Delta [3][2]string
 	}
+	Curve string
 }
 type GrothSetupHex struct {
 	Pk GrothPkHex
@@ -436,93 +567,126 @@ type GrothSetupHex struct {
 
 func GrothSetupToHex(setup groth16.Setup) GrothSetupHex {
 	var s GrothSetupHex
-	s.Pk.BACDelta = Array3BigIntToHex(setup.Pk.BACDelta)
+	s.Pk.BACDelta = G1PointsToHex(setup.Pk.BACDelta)
 	s.Pk.Z = ArrayBigIntToHex(setup.Pk.Z)
-	s.Pk.G1.Alpha = BigInt3ToHex(setup.Pk.G1.Alpha)
-	s.Pk.G1.Beta = BigInt3ToHex(setup.Pk.G1.Beta)
-	s.Pk.G1.Delta = BigInt3ToHex(setup.Pk.G1.Delta)
-	s.Pk.G1.At = Array3BigIntToHex(setup.Pk.G1.At)
-	s.Pk.G1.BACGamma = Array3BigIntToHex(setup.Pk.G1.BACGamma)
-	s.Pk.G2.Beta = BigInt32ToHex(setup.Pk.G2.Beta)
-	s.Pk.G2.Gamma = BigInt32ToHex(setup.Pk.G2.Gamma)
-	s.Pk.G2.Delta = BigInt32ToHex(setup.Pk.G2.Delta)
-	s.Pk.G2.BACGamma = Array32BigIntToHex(setup.Pk.G2.BACGamma)
-	s.Pk.PowersTauDelta = Array3BigIntToHex(setup.Pk.PowersTauDelta)
-	s.Vk.IC = Array3BigIntToHex(setup.Vk.IC)
-	s.Vk.G1.Alpha = BigInt3ToHex(setup.Vk.G1.Alpha)
-	s.Vk.G2.Beta = BigInt32ToHex(setup.Vk.G2.Beta)
-	s.Vk.G2.Gamma = BigInt32ToHex(setup.Vk.G2.Gamma)
-	s.Vk.G2.Delta = BigInt32ToHex(setup.Vk.G2.Delta)
+	s.Pk.G1.Alpha = G1PointToHex(setup.Pk.G1.Alpha)
+	s.Pk.G1.Beta = G1PointToHex(setup.Pk.G1.Beta)
+	s.Pk.G1.Delta = G1PointToHex(setup.Pk.G1.Delta)
+	s.Pk.G1.At = G1PointsToHex(setup.Pk.G1.At)
+	s.Pk.G1.BACGamma = G1PointsToHex(setup.Pk.G1.BACGamma)
+	s.Pk.G2.Beta = G2PointToHex(setup.Pk.G2.Beta)
+	s.Pk.G2.Gamma = G2PointToHex(setup.Pk.G2.Gamma)
+	s.Pk.G2.Delta = G2PointToHex(setup.Pk.G2.Delta)
+	s.Pk.G2.BACGamma = G2PointsToHex(setup.Pk.G2.BACGamma)
+	s.Pk.PowersTauDelta = G1PointsToHex(setup.Pk.PowersTauDelta)
+	s.Pk.Curve = grothCurveName(setup.Pk.Curve)
+	s.Vk.IC = G1PointsToHex(setup.Vk.IC)
+	s.Vk.G1.Alpha = G1PointToHex(setup.Vk.G1.Alpha)
+	s.Vk.G2.Beta = G2PointToHex(setup.Vk.G2.Beta)
+	s.Vk.G2.Gamma = G2PointToHex(setup.Vk.G2.Gamma)
+	s.Vk.G2.Delta = G2PointToHex(setup.Vk.G2.Delta)
+	s.Vk.Curve = grothCurveName(setup.Vk.Curve)
 	return s
 }
-func GrothSetupFromHex(s GrothSetupHex) (groth16.Setup, error) {
-	var o groth16.Setup
+func grothVkFromHex(s GrothVkHex) (groth16.Vk, error) {
+	var vk groth16.Vk
 	var err error
-	o.Pk.BACDelta, err = Array3HexToBigInt(s.Pk.BACDelta)
+	vk.Curve, err = grothCurve(s.Curve)
 	if err != nil {
-		return o, err
+		return vk, err
 	}
-	o.Pk.Z, err = ArrayHexToBigInt(s.Pk.Z)
+	g1, g2 := vk.Curve.G1(), vk.Curve.G2()
+	vk.IC, err = Array3HexToG1Points(g1, s.IC)
 	if err != nil {
-		return o, err
+		return vk, err
 	}
-	o.Pk.G1.Alpha, err = Hex3ToBigInt(s.Pk.G1.Alpha)
+	vk.G1.Alpha, err = Hex3ToG1Point(g1, s.G1.Alpha)
 	if err != nil {
-		return o, err
+		return vk, err
 	}
-	o.Pk.G1.Beta, err = Hex3ToBigInt(s.Pk.G1.Beta)
+	vk.G2.Beta, err = Hex32ToG2Point(g2, s.G2.Beta)
 	if err != nil {
-		return o, err
+		return vk, err
 	}
-	o.Pk.G1.Delta, err = Hex3ToBigInt(s.Pk.G1.Delta)
+	vk.G2.Gamma, err = Hex32ToG2Point(g2, s.G2.Gamma)
 	if err != nil {
-		return o, err
+		return vk, err
 	}
-	o.Pk.G1.At, err = Array3HexToBigInt(s.Pk.G1.At)
+	vk.G2.Delta, err = Hex32ToG2Point(g2, s.G2.Delta)
 	if err != nil {
-		return o, err
+		return vk, err
 	}
-	o.Pk.G1.BACGamma, err = Array3HexToBigInt(s.Pk.G1.BACGamma)
+	if err := groth16.ValidateVk(vk); err != nil {
+		return vk, err
+	}
+	return vk, nil
+}
+func grothPkFromHex(s GrothPkHex) (groth16.Pk, error) {
+	var pk groth16.Pk
+	var err error
+	pk.Curve, err = grothCurve(s.Curve)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G2.Beta, err = Hex32ToBigInt(s.Pk.G2.Beta)
+	g1, g2 := pk.Curve.G1(), pk.Curve.G2()
+	pk.BACDelta, err = Array3HexToG1Points(g1, s.BACDelta)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G2.Gamma, err = Hex32ToBigInt(s.Pk.G2.Gamma)
+	pk.Z, err = ArrayHexToBigInt(s.Z)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G2.Delta, err = Hex32ToBigInt(s.Pk.G2.Delta)
+	pk.G1.Alpha, err = Hex3ToG1Point(g1, s.G1.Alpha)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.G2.BACGamma, err = Array32HexToBigInt(s.Pk.G2.BACGamma)
+	pk.G1.Beta, err = Hex3ToG1Point(g1, s.G1.Beta)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Pk.PowersTauDelta, err = Array3HexToBigInt(s.Pk.PowersTauDelta)
+	pk.G1.Delta, err = Hex3ToG1Point(g1, s.G1.Delta)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Vk.IC, err = Array3HexToBigInt(s.Vk.IC)
+	pk.G1.At, err = Array3HexToG1Points(g1, s.G1.At)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Vk.G1.Alpha, err = Hex3ToBigInt(s.Vk.G1.Alpha)
+	pk.G1.BACGamma, err = Array3HexToG1Points(g1, s.G1.BACGamma)
 	if err != nil {
-		return o, err
+		return pk, err
 	}
-	o.Vk.G2.Beta, err = Hex32ToBigInt(s.Vk.G2.Beta)
+	pk.G2.Beta, err = Hex32ToG2Point(g2, s.G2.Beta)
 	if err != nil {
-		return o, err
+		return pk, err
+	}
+	pk.G2.Gamma, err = Hex32ToG2Point(g2, s.G2.Gamma)
+	if err != nil {
+		return pk, err
 	}
-	o.Vk.G2.Gamma, err = Hex32ToBigInt(s.Vk.G2.Gamma)
+	pk.G2.Delta, err = Hex32ToG2Point(g2, s.G2.Delta)
+	if err != nil {
+		return pk, err
+	}
+	pk.G2.BACGamma, err = Array32HexToG2Points(g2, s.G2.BACGamma)
+	if err != nil {
+		return pk, err
+	}
+	pk.PowersTauDelta, err = Array3HexToG1Points(g1, s.PowersTauDelta)
+	if err != nil {
+		return pk, err
+	}
+	return pk, nil
+}
+func GrothSetupFromHex(s GrothSetupHex) (groth16.Setup, error) {
+	var o groth16.Setup
+	var err error
+	o.Pk, err = grothPkFromHex(s.Pk)
 	if err != nil {
 		return o, err
 	}
-	o.Vk.G2.Delta, err = Hex32ToBigInt(s.Vk.G2.Delta)
+	o.Vk, err = grothVkFromHex(s.Vk)
 	if err != nil {
 		return o, err
 	}
@@ -530,33 +694,42 @@ func GrothSetupFromHex(s GrothSetupHex) (groth16.Setup, error) {
 }
 
 type GrothProofHex struct {
-	PiA [3]string
-	PiB [3][2]string
-	PiC [3]string
+	PiA   [3]string
+	PiB   [3][2]string
+	PiC   [3]string
+	Curve string
 }
 
 func GrothProofToHex(p groth16.Proof) GrothProofHex {
 	var s GrothProofHex
-	s.PiA = BigInt3ToHex(p.PiA)
-	s.PiB = BigInt32ToHex(p.PiB)
-	s.PiC = BigInt3ToHex(p.PiC)
+	s.PiA = G1PointToHex(p.PiA)
+	s.PiB = G2PointToHex(p.PiB)
+	s.PiC = G1PointToHex(p.PiC)
+	s.Curve = grothCurveName(p.Curve)
 	return s
 }
 func GrothProofFromHex(s GrothProofHex) (groth16.Proof, error) {
 	var p groth16.Proof
 	var err error
 
-	p.PiA, err = Hex3ToBigInt(s.PiA)
+	p.Curve, err = grothCurve(s.Curve)
 	if err != nil {
 		return p, err
 	}
-	p.PiB, err = Hex32ToBigInt(s.PiB)
+	p.PiA, err = Hex3ToG1Point(p.Curve.G1(), s.PiA)
 	if err != nil {
 		return p, err
 	}
-	p.PiC, err = Hex3ToBigInt(s.PiC)
+	p.PiB, err = Hex32ToG2Point(p.Curve.G2(), s.PiB)
 	if err != nil {
 		return p, err
 	}
+	p.PiC, err = Hex3ToG1Point(p.Curve.G1(), s.PiC)
+	if err != nil {
+		return p, err
+	}
+	if err := groth16.ValidateProof(p); err != nil {
+		return p, err
+	}
 	return p, nil
 }
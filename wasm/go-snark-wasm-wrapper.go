@@ -58,29 +58,14 @@ func generateProofs(this js.Value, i []js.Value) interface{} {
 	}
 	println("set", string(sj))
 
-	var pxStr []string
-	err = json.Unmarshal([]byte(i[2].String()), &pxStr)
-	if err != nil {
-		println("error parsing pxStr from stringified json")
-	}
-	px, err := utils.ArrayStringToBigInt(pxStr)
-	if err != nil {
-		println(err.Error())
-	}
-	sj, err = json.Marshal(px)
-	if err != nil {
-		println("error " + err.Error())
-	}
-	println("px", string(sj))
-
 	var inputs circuitcompiler.Inputs
-	err = json.Unmarshal([]byte(i[3].String()), &inputs)
+	err = json.Unmarshal([]byte(i[2].String()), &inputs)
 	if err != nil {
 		println("error parsing inputs from stringified json")
 	}
 	w, err := circuit.CalculateWitness(inputs.Private, inputs.Public)
 
-	proof, err := snark.GenerateProofs(circuit, setup.Pk, w, px)
+	proof, err := snark.GenerateProofs(circuit, setup.Pk, w)
 	if err != nil {
 		println("error generating proof", err)
 	}
@@ -123,7 +108,7 @@ func verifyProofs(this js.Value, i []js.Value) interface{} {
 		println("error parsing publicInputs from stringified json")
 	}
 
-	verified := snark.VerifyProof(setup.Vk, proof, publicInputs, false)
+	verified, err := snark.VerifyProof(setup.Vk, proof, publicInputs, false)
 	if err != nil {
 		println("error verifiyng proof", err)
 	}
@@ -168,29 +153,14 @@ func grothGenerateProofs(this js.Value, i []js.Value) interface{} {
 	}
 	println("set", string(sj))
 
-	var pxStr []string
-	err = json.Unmarshal([]byte(i[2].String()), &pxStr)
-	if err != nil {
-		println("error parsing pxStr from stringified json")
-	}
-	px, err := utils.ArrayStringToBigInt(pxStr)
-	if err != nil {
-		println(err.Error())
-	}
-	sj, err = json.Marshal(px)
-	if err != nil {
-		println("error " + err.Error())
-	}
-	println("px", string(sj))
-
 	var inputs circuitcompiler.Inputs
-	err = json.Unmarshal([]byte(i[3].String()), &inputs)
+	err = json.Unmarshal([]byte(i[2].String()), &inputs)
 	if err != nil {
 		println("error parsing inputs from stringified json")
 	}
 	w, err := circuit.CalculateWitness(inputs.Private, inputs.Public)
 
-	proof, err := groth16.GenerateProofs(circuit, setup.Pk, w, px)
+	proof, err := groth16.GenerateProofs(circuit, setup.Pk, w)
 	if err != nil {
 		println("error generating proof", err)
 	}
@@ -233,7 +203,7 @@ func grothVerifyProofs(this js.Value, i []js.Value) interface{} {
 		println("error parsing publicInputs from stringified json")
 	}
 
-	verified := groth16.VerifyProof(setup.Vk, proof, publicInputs, false)
+	verified, err := groth16.VerifyProof(setup.Vk, proof, publicInputs, false)
 	if err != nil {
 		println("error verifiyng proof", err)
 	}